@@ -0,0 +1,532 @@
+// Package aseprite decodes Aseprite (.aseprite / .ase) files: frames with
+// their durations, layers, tags and slices. It is pure Go and does not
+// depend on raylib; core turns the result into textures and animations.
+//
+// The format is described at
+// https://github.com/aseprite/aseprite/blob/main/docs/ase-file-specs.md
+package aseprite
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"os"
+	"sort"
+)
+
+const (
+	fileMagic  = 0xA5E0
+	frameMagic = 0xF1FA
+
+	chunkOldPalette = 0x0004
+	chunkLayer      = 0x2004
+	chunkCel        = 0x2005
+	chunkTags       = 0x2018
+	chunkPalette    = 0x2019
+	chunkSlice      = 0x2022
+
+	celRaw        = 0
+	celLinked     = 1
+	celCompressed = 2
+
+	layerVisible    = 1
+	layerBackground = 8
+	layerGroup      = 1
+
+	headerLayerOpacity = 1
+
+	sliceNinePatch = 1
+	sliceHasPivot  = 2
+
+	// Limits that keep a corrupt header from requesting huge allocations
+	maxCanvasPixels = 8192 * 8192
+	maxPaletteSize  = 65536
+)
+
+// Direction is the playback direction of a tag.
+type Direction int
+
+const (
+	Forward Direction = iota
+	Reverse
+	PingPong
+	PingPongReverse
+)
+
+// File is a decoded Aseprite file.
+type File struct {
+	Width, Height int
+	Frames        []Frame
+	Layers        []Layer
+	Tags          []Tag
+	Slices        []Slice
+}
+
+// Frame is one animation frame: the composited image of all visible layers
+// and the cels it was built from.
+type Frame struct {
+	Duration float32 // Seconds
+	Image    *image.NRGBA
+	Cels     []Cel
+}
+
+// Cel is the image of one layer in one frame, placed at X, Y on the canvas.
+type Cel struct {
+	Layer   int
+	X, Y    int
+	Opacity uint8
+	ZIndex  int
+	Image   *image.NRGBA
+}
+
+// Layer describes a layer. Group layers have no cels; ChildLevel nests
+// layers under the group above them.
+type Layer struct {
+	Name       string
+	Visible    bool // Own flag and that of every parent group
+	Background bool
+	Group      bool
+	ChildLevel int
+	BlendMode  int
+	Opacity    uint8
+}
+
+// Tag is a named frame range, used as an animation clip.
+type Tag struct {
+	Name      string
+	From, To  int // Inclusive frame indices
+	Direction Direction
+	Repeat    int // 0 = loop forever
+}
+
+// Slice is a named region, such as a hitbox or pivot. Its keys change the
+// region from their frame on.
+type Slice struct {
+	Name string
+	Keys []SliceKey
+}
+
+// SliceKey is the state of a slice from Frame until the next key.
+type SliceKey struct {
+	Frame    int
+	Bounds   image.Rectangle
+	Center   image.Rectangle // 9-patch center, relative to Bounds; empty if none
+	Pivot    image.Point     // Relative to Bounds
+	HasPivot bool
+}
+
+// KeyAt returns the key of the slice that applies to a frame.
+func (s Slice) KeyAt(frame int) (SliceKey, bool) {
+	var key SliceKey
+	found := false
+	for _, k := range s.Keys {
+		if k.Frame <= frame && (!found || k.Frame >= key.Frame) {
+			key, found = k, true
+		}
+	}
+	return key, found
+}
+
+// Load decodes the Aseprite file at path.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Decode reads an Aseprite file. Colour depths 32 (RGBA), 16 (grayscale)
+// and 8 (indexed) are supported; blend modes other than normal are drawn as
+// normal, and tilemap cels are skipped.
+func Decode(r io.Reader) (*File, error) {
+	var header struct {
+		FileSize         uint32
+		Magic            uint16
+		Frames           uint16
+		Width, Height    uint16
+		ColorDepth       uint16
+		Flags            uint32
+		Speed            uint16
+		_                [2]uint32
+		TransparentIndex uint8
+		_                [3]byte
+		Colors           uint16
+		_                [94]byte
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if header.Magic != fileMagic {
+		return nil, errors.New("not an Aseprite file")
+	}
+	switch header.ColorDepth {
+	case 32, 16, 8:
+	default:
+		return nil, fmt.Errorf("unsupported colour depth %d", header.ColorDepth)
+	}
+	if header.Width == 0 || header.Height == 0 || int(header.Width)*int(header.Height) > maxCanvasPixels {
+		return nil, fmt.Errorf("bad canvas size %dx%d", header.Width, header.Height)
+	}
+
+	d := &decoder{
+		file:             &File{Width: int(header.Width), Height: int(header.Height)},
+		depth:            int(header.ColorDepth),
+		transparentIndex: header.TransparentIndex,
+		layerOpacity:     header.Flags&headerLayerOpacity != 0,
+		palette:          make(color.Palette, 256),
+	}
+	for i := range d.palette {
+		d.palette[i] = color.NRGBA{}
+	}
+
+	for i := 0; i < int(header.Frames); i++ {
+		if err := d.readFrame(r, i); err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, err)
+		}
+	}
+
+	d.resolveVisibility()
+	for i := range d.file.Frames {
+		d.composite(&d.file.Frames[i])
+	}
+	return d.file, nil
+}
+
+// decoder holds the state shared between chunks of a file.
+type decoder struct {
+	file             *File
+	depth            int
+	transparentIndex uint8
+	layerOpacity     bool
+	palette          color.Palette
+	lastSlice        *Slice
+}
+
+// readFrame reads a frame header and its chunks.
+func (d *decoder) readFrame(r io.Reader, index int) error {
+	var header struct {
+		Size      uint32
+		Magic     uint16
+		OldChunks uint16
+		Duration  uint16
+		_         [2]byte
+		NewChunks uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if header.Magic != frameMagic {
+		return errors.New("bad frame magic")
+	}
+
+	chunks := int(header.NewChunks)
+	if chunks == 0 {
+		chunks = int(header.OldChunks)
+	}
+
+	d.file.Frames = append(d.file.Frames, Frame{Duration: float32(header.Duration) / 1000})
+	frame := &d.file.Frames[index]
+
+	for i := 0; i < chunks; i++ {
+		var chunkHeader struct {
+			Size uint32
+			Type uint16
+		}
+		if err := binary.Read(r, binary.LittleEndian, &chunkHeader); err != nil {
+			return err
+		}
+		if chunkHeader.Size < 6 {
+			return fmt.Errorf("chunk %#04x: bad size %d", chunkHeader.Type, chunkHeader.Size)
+		}
+		// Read through a limit rather than allocating the claimed size up
+		// front: a corrupt size must fail at the end of the data
+		size := int64(chunkHeader.Size) - 6
+		data, err := io.ReadAll(io.LimitReader(r, size))
+		if err != nil {
+			return err
+		}
+		if int64(len(data)) < size {
+			return io.ErrUnexpectedEOF
+		}
+		if err := d.readChunk(chunkHeader.Type, &reader{data: data}, frame); err != nil {
+			return fmt.Errorf("chunk %#04x: %w", chunkHeader.Type, err)
+		}
+	}
+	return nil
+}
+
+// readChunk decodes a chunk of a known type; other chunks are ignored.
+func (d *decoder) readChunk(chunkType uint16, r *reader, frame *Frame) error {
+	switch chunkType {
+	case chunkOldPalette:
+		d.readOldPalette(r)
+	case chunkPalette:
+		d.readPalette(r)
+	case chunkLayer:
+		d.readLayer(r)
+	case chunkCel:
+		return d.readCel(r, frame)
+	case chunkTags:
+		d.readTags(r)
+	case chunkSlice:
+		d.readSlice(r)
+	}
+	return r.err
+}
+
+func (d *decoder) readOldPalette(r *reader) {
+	// The new palette chunk supersedes this one when both are present
+	packets := int(r.u16())
+	index := 0
+	for p := 0; p < packets && r.err == nil; p++ {
+		index += int(r.u8())
+		count := int(r.u8())
+		if count == 0 {
+			count = 256
+		}
+		for c := 0; c < count; c++ {
+			red, green, blue := r.u8(), r.u8(), r.u8()
+			if index < len(d.palette) {
+				d.palette[index] = color.NRGBA{R: red, G: green, B: blue, A: 255}
+			}
+			index++
+		}
+	}
+}
+
+func (d *decoder) readPalette(r *reader) {
+	size := int(r.u32())
+	first := int(r.u32())
+	last := int(r.u32())
+	r.skip(8)
+	if size > maxPaletteSize {
+		r.err = fmt.Errorf("palette of %d colours", size)
+		return
+	}
+	if size > len(d.palette) {
+		d.palette = append(d.palette, make(color.Palette, size-len(d.palette))...)
+	}
+	for i := first; i <= last && r.err == nil; i++ {
+		flags := r.u16()
+		c := color.NRGBA{R: r.u8(), G: r.u8(), B: r.u8(), A: r.u8()}
+		if flags&1 != 0 {
+			r.str()
+		}
+		if i < len(d.palette) {
+			d.palette[i] = c
+		}
+	}
+}
+
+func (d *decoder) readLayer(r *reader) {
+	flags := r.u16()
+	layerType := r.u16()
+	childLevel := r.u16()
+	r.skip(4) // Default width and height
+	blendMode := r.u16()
+	opacity := r.u8()
+	r.skip(3)
+	name := r.str()
+	if !d.layerOpacity {
+		opacity = 255
+	}
+
+	d.file.Layers = append(d.file.Layers, Layer{
+		Name:       name,
+		Visible:    flags&layerVisible != 0,
+		Background: flags&layerBackground != 0,
+		Group:      layerType == layerGroup,
+		ChildLevel: int(childLevel),
+		BlendMode:  int(blendMode),
+		Opacity:    opacity,
+	})
+}
+
+func (d *decoder) readCel(r *reader, frame *Frame) error {
+	cel := Cel{
+		Layer:   int(r.u16()),
+		X:       int(r.i16()),
+		Y:       int(r.i16()),
+		Opacity: r.u8(),
+	}
+	celType := r.u16()
+	cel.ZIndex = int(r.i16())
+	r.skip(5)
+
+	switch celType {
+	case celRaw:
+		width, height := int(r.u16()), int(r.u16())
+		img, err := d.pixels(r.rest(), width, height, cel.Layer)
+		if err != nil {
+			return err
+		}
+		cel.Image = img
+
+	case celLinked:
+		position := int(r.u16())
+		if position >= len(d.file.Frames) {
+			return fmt.Errorf("cel linked to unknown frame %d", position)
+		}
+		for _, linked := range d.file.Frames[position].Cels {
+			if linked.Layer == cel.Layer {
+				cel.Image = linked.Image
+			}
+		}
+		if cel.Image == nil {
+			return nil
+		}
+
+	case celCompressed:
+		width, height := int(r.u16()), int(r.u16())
+		zr, err := zlib.NewReader(bytes.NewReader(r.rest()))
+		if err != nil {
+			return err
+		}
+		// Inflate no more than the cel can use
+		limit := int64(width) * int64(height) * int64(d.depth/8)
+		raw, err := io.ReadAll(io.LimitReader(zr, limit))
+		if err != nil {
+			return err
+		}
+		img, err := d.pixels(raw, width, height, cel.Layer)
+		if err != nil {
+			return err
+		}
+		cel.Image = img
+
+	default:
+		return r.err // Tilemap cels are not supported
+	}
+
+	frame.Cels = append(frame.Cels, cel)
+	return r.err
+}
+
+func (d *decoder) readTags(r *reader) {
+	count := int(r.u16())
+	r.skip(8)
+	for i := 0; i < count && r.err == nil; i++ {
+		tag := Tag{
+			From:      int(r.u16()),
+			To:        int(r.u16()),
+			Direction: Direction(r.u8()),
+			Repeat:    int(r.u16()),
+		}
+		r.skip(10) // Reserved, colour and padding
+		tag.Name = r.str()
+		d.file.Tags = append(d.file.Tags, tag)
+	}
+}
+
+func (d *decoder) readSlice(r *reader) {
+	keys := int(r.u32())
+	flags := r.u32()
+	r.skip(4)
+	slice := Slice{Name: r.str()}
+
+	for i := 0; i < keys && r.err == nil; i++ {
+		key := SliceKey{Frame: int(r.u32())}
+		x, y := int(r.i32()), int(r.i32())
+		width, height := int(r.u32()), int(r.u32())
+		key.Bounds = image.Rect(x, y, x+width, y+height)
+		if flags&sliceNinePatch != 0 {
+			cx, cy := int(r.i32()), int(r.i32())
+			cw, ch := int(r.u32()), int(r.u32())
+			key.Center = image.Rect(cx, cy, cx+cw, cy+ch)
+		}
+		if flags&sliceHasPivot != 0 {
+			key.Pivot = image.Pt(int(r.i32()), int(r.i32()))
+			key.HasPivot = true
+		}
+		slice.Keys = append(slice.Keys, key)
+	}
+
+	d.file.Slices = append(d.file.Slices, slice)
+}
+
+// pixels converts cel pixel data of the file's colour depth to NRGBA.
+func (d *decoder) pixels(data []byte, width, height, layer int) (*image.NRGBA, error) {
+	bytesPerPixel := d.depth / 8
+	if len(data) < width*height*bytesPerPixel {
+		return nil, fmt.Errorf("cel data too short for %dx%d", width, height)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if d.depth == 32 {
+		copy(img.Pix, data[:width*height*4])
+		return img, nil
+	}
+
+	// The transparent index is opaque on the background layer
+	background := layer < len(d.file.Layers) && d.file.Layers[layer].Background
+	for i := 0; i < width*height; i++ {
+		var c color.NRGBA
+		switch d.depth {
+		case 16:
+			value, alpha := data[i*2], data[i*2+1]
+			c = color.NRGBA{R: value, G: value, B: value, A: alpha}
+		case 8:
+			index := data[i]
+			if index != d.transparentIndex || background {
+				c = color.NRGBAModel.Convert(d.palette[index]).(color.NRGBA)
+			}
+		}
+		img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = c.R, c.G, c.B, c.A
+	}
+	return img, nil
+}
+
+// resolveVisibility hides layers inside hidden groups.
+func (d *decoder) resolveVisibility() {
+	var groups []bool // Visibility of the enclosing group at each child level
+	for i := range d.file.Layers {
+		layer := &d.file.Layers[i]
+		if layer.ChildLevel < len(groups) {
+			groups = groups[:layer.ChildLevel]
+		}
+		if len(groups) > 0 && !groups[len(groups)-1] {
+			layer.Visible = false
+		}
+		if layer.Group {
+			groups = append(groups, layer.Visible)
+		}
+	}
+}
+
+// composite draws the visible cels of a frame onto a canvas-sized image in
+// layer order (adjusted by each cel's z-index).
+func (d *decoder) composite(frame *Frame) {
+	cels := make([]Cel, 0, len(frame.Cels))
+	for _, cel := range frame.Cels {
+		if cel.Layer < len(d.file.Layers) && d.file.Layers[cel.Layer].Visible {
+			cels = append(cels, cel)
+		}
+	}
+	sort.SliceStable(cels, func(i, j int) bool {
+		oi, oj := cels[i].Layer+cels[i].ZIndex, cels[j].Layer+cels[j].ZIndex
+		if oi != oj {
+			return oi < oj
+		}
+		return cels[i].ZIndex < cels[j].ZIndex
+	})
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, d.file.Width, d.file.Height))
+	for _, cel := range cels {
+		opacity := int(cel.Opacity) * int(d.file.Layers[cel.Layer].Opacity) / 255
+		bounds := cel.Image.Bounds().Add(image.Pt(cel.X, cel.Y))
+		mask := image.NewUniform(color.Alpha{A: uint8(opacity)})
+		draw.DrawMask(canvas, bounds, cel.Image, image.Point{}, mask, image.Point{}, draw.Over)
+	}
+	frame.Image = canvas
+}
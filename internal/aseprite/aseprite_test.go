@@ -0,0 +1,216 @@
+package aseprite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testdata/sprite.aseprite is an 8x8 RGBA sprite with three frames (100,
+// 150 and 200 ms) and two layers, "body" and the hidden "hidden":
+//   - frame 0: a raw 4x4 red cel at (2, 2)
+//   - frame 1: a compressed 2x2 green cel at (0, 0), and an 8x8 blue cel
+//     on the hidden layer
+//   - frame 2: a cel linked to frame 1
+//
+// Tags "idle" (0-1, forward) and "spin" (1-2, ping-pong); slice "hitbox"
+// with a pivot, keyed on frames 0 and 2.
+const fixturePath = "testdata/sprite.aseprite"
+
+func loadFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+var (
+	red   = color.NRGBA{R: 255, A: 255}
+	green = color.NRGBA{G: 255, A: 255}
+	clear = color.NRGBA{}
+)
+
+func TestLoadFixture(t *testing.T) {
+	file, err := Load(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if file.Width != 8 || file.Height != 8 {
+		t.Errorf("canvas %dx%d, want 8x8", file.Width, file.Height)
+	}
+	if len(file.Frames) != 3 {
+		t.Fatalf("%d frames, want 3", len(file.Frames))
+	}
+	for i, want := range []float32{0.1, 0.15, 0.2} {
+		if file.Frames[i].Duration != want {
+			t.Errorf("frame %d: duration %v, want %v", i, file.Frames[i].Duration, want)
+		}
+	}
+
+	wantLayers := []Layer{
+		{Name: "body", Visible: true, Opacity: 255},
+		{Name: "hidden", Visible: false, Opacity: 255},
+	}
+	if len(file.Layers) != len(wantLayers) {
+		t.Fatalf("layers %+v, want %+v", file.Layers, wantLayers)
+	}
+	for i := range wantLayers {
+		if file.Layers[i] != wantLayers[i] {
+			t.Errorf("layer %d: %+v, want %+v", i, file.Layers[i], wantLayers[i])
+		}
+	}
+
+	wantTags := []Tag{
+		{Name: "idle", From: 0, To: 1, Direction: Forward},
+		{Name: "spin", From: 1, To: 2, Direction: PingPong},
+	}
+	if len(file.Tags) != len(wantTags) {
+		t.Fatalf("tags %+v, want %+v", file.Tags, wantTags)
+	}
+	for i := range wantTags {
+		if file.Tags[i] != wantTags[i] {
+			t.Errorf("tag %d: %+v, want %+v", i, file.Tags[i], wantTags[i])
+		}
+	}
+
+	if len(file.Slices) != 1 || file.Slices[0].Name != "hitbox" || len(file.Slices[0].Keys) != 2 {
+		t.Fatalf("slices %+v, want one \"hitbox\" with two keys", file.Slices)
+	}
+}
+
+func TestFixturePixels(t *testing.T) {
+	file, err := Load(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		frame int
+		at    image.Point
+		want  color.NRGBA
+	}{
+		{0, image.Pt(2, 2), red},   // Raw cel, offset
+		{0, image.Pt(5, 5), red},   // Its far corner
+		{0, image.Pt(1, 1), clear}, // Outside it
+		{1, image.Pt(1, 1), green}, // Compressed cel
+		{1, image.Pt(5, 5), clear}, // Hidden layer is not drawn
+		{2, image.Pt(0, 0), green}, // Linked cel
+	}
+	for _, tt := range tests {
+		if got := file.Frames[tt.frame].Image.NRGBAAt(tt.at.X, tt.at.Y); got != tt.want {
+			t.Errorf("frame %d at %v: %v, want %v", tt.frame, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestSliceKeyAt(t *testing.T) {
+	file, err := Load(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slice := file.Slices[0]
+
+	tests := []struct {
+		frame  int
+		bounds image.Rectangle
+		pivot  image.Point
+	}{
+		{0, image.Rect(1, 2, 4, 6), image.Pt(1, 1)},
+		{1, image.Rect(1, 2, 4, 6), image.Pt(1, 1)}, // Frame 0's key still applies
+		{2, image.Rect(4, 4, 6, 6), image.Pt(0, 2)},
+	}
+	for _, tt := range tests {
+		key, ok := slice.KeyAt(tt.frame)
+		if !ok || key.Bounds != tt.bounds || key.Pivot != tt.pivot || !key.HasPivot {
+			t.Errorf("frame %d: key %+v (found %v), want bounds %v pivot %v", tt.frame, key, ok, tt.bounds, tt.pivot)
+		}
+	}
+	if _, ok := (Slice{Keys: []SliceKey{{Frame: 2}}}).KeyAt(1); ok {
+		t.Error("KeyAt found a key before the first one")
+	}
+}
+
+// withUint32 returns a copy of data with a little-endian value written at
+// offset.
+func withUint32(data []byte, offset int, value uint32) []byte {
+	data = bytes.Clone(data)
+	binary.LittleEndian.PutUint32(data[offset:], value)
+	return data
+}
+
+func withUint16(data []byte, offset int, value uint16) []byte {
+	data = bytes.Clone(data)
+	binary.LittleEndian.PutUint16(data[offset:], value)
+	return data
+}
+
+func TestDecodeRejectsCorruptInput(t *testing.T) {
+	valid := loadFixture(t)
+
+	// Offsets into the fixture: the file header is 128 bytes, then the
+	// first frame's 16-byte header, then its first chunk
+	const (
+		frameHeader = 128
+		firstChunk  = frameHeader + 16
+	)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "header"},
+		{"not aseprite", withUint16(valid, 4, 0x1234), "not an Aseprite file"},
+		{"bad colour depth", withUint16(valid, 12, 24), "colour depth"},
+		{"empty canvas", withUint16(valid, 8, 0), "canvas size"},
+		{"huge canvas", withUint16(withUint16(valid, 8, 65535), 10, 65535), "canvas size"},
+		{"bad frame magic", withUint16(valid, frameHeader+4, 0), "frame magic"},
+		{"more frames than stored", withUint16(valid, 6, 4), "frame 3"},
+		{"chunk smaller than its header", withUint32(valid, firstChunk, 2), "bad size"},
+		{"chunk larger than the file", withUint32(valid, firstChunk, 0xFFFFFFF0), "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	valid := loadFixture(t)
+	for size := 0; size < len(valid); size++ {
+		if _, err := Decode(bytes.NewReader(valid[:size])); err == nil {
+			t.Errorf("decoding the first %d of %d bytes succeeded", size, len(valid))
+		}
+	}
+}
+
+func TestDecodeCorruptBytesDoNotPanic(t *testing.T) {
+	valid := loadFixture(t)
+
+	// Every single-byte corruption must decode or fail, never panic
+	for offset := range valid {
+		for _, value := range []byte{0x00, 0x7F, 0xFF} {
+			data := bytes.Clone(valid)
+			data[offset] = value
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("byte %d set to %#x: panic: %v", offset, value, r)
+					}
+				}()
+				Decode(bytes.NewReader(data))
+			}()
+		}
+	}
+}
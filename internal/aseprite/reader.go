@@ -0,0 +1,72 @@
+package aseprite
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// reader reads little-endian values from a chunk's data. The first read past
+// the end sets err; later reads return zero values.
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *reader) take(n int) []byte {
+	if r.err != nil || r.pos+n > len(r.data) {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) skip(n int) {
+	r.take(n)
+}
+
+func (r *reader) u8() uint8 {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) u16() uint16 {
+	if b := r.take(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) i16() int16 {
+	return int16(r.u16())
+}
+
+func (r *reader) u32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) i32() int32 {
+	return int32(r.u32())
+}
+
+// str reads a length-prefixed UTF-8 string.
+func (r *reader) str() string {
+	return string(r.take(int(r.u16())))
+}
+
+// rest returns the unread data.
+func (r *reader) rest() []byte {
+	if r.err != nil {
+		return nil
+	}
+	b := r.data[r.pos:]
+	r.pos = len(r.data)
+	return b
+}
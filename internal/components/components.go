@@ -1,6 +1,8 @@
 package components
 
 import (
+	"math"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -12,7 +14,7 @@ type TransformComponent struct {
 	FacingRight bool
 }
 
-// AnimationState represents the current animation state.
+// AnimationState identifies an animation clip of a sprite.
 type AnimationState int
 
 const (
@@ -20,18 +22,133 @@ const (
 	AnimRunning
 	AnimJumping
 	AnimFalling
+	AnimTurnAround
+	AnimWallSlide
+	AnimWallHang
+	AnimDash
+	AnimRoll
+	AnimSlide
+	AnimCrouch
+	AnimCrouchWalk
+	AnimCrouchTransition
+	AnimAttack
+	AnimAttack2
+	AnimAttackCombo
+	AnimCrouchAttack
+	AnimJumpFall
+	AnimHit
+	AnimDeath
 )
 
-// AnimationData holds data for a single animation.
+// animationStateNames are the clip names used in data files.
+var animationStateNames = map[AnimationState]string{
+	AnimIdle:             "idle",
+	AnimRunning:          "run",
+	AnimJumping:          "jump",
+	AnimFalling:          "fall",
+	AnimTurnAround:       "turnAround",
+	AnimWallSlide:        "wallSlide",
+	AnimWallHang:         "wallHang",
+	AnimDash:             "dash",
+	AnimRoll:             "roll",
+	AnimSlide:            "slide",
+	AnimCrouch:           "crouch",
+	AnimCrouchWalk:       "crouchWalk",
+	AnimCrouchTransition: "crouchTransition",
+	AnimAttack:           "attack",
+	AnimAttack2:          "attack2",
+	AnimAttackCombo:      "attackCombo",
+	AnimCrouchAttack:     "crouchAttack",
+	AnimJumpFall:         "jumpFall",
+	AnimHit:              "hit",
+	AnimDeath:            "death",
+}
+
+// String returns the clip name used in data files.
+func (a AnimationState) String() string {
+	return animationStateNames[a]
+}
+
+// ParseAnimationState returns the clip with the given data file name.
+func ParseAnimationState(name string) (AnimationState, bool) {
+	for state, stateName := range animationStateNames {
+		if stateName == name {
+			return state, true
+		}
+	}
+	return AnimIdle, false
+}
+
+// PlaybackMode is the order in which an animation's frames play.
+type PlaybackMode int32
+
+const (
+	PlayForward  PlaybackMode = iota
+	PlayReverse               // Last frame to first
+	PlayPingPong              // First to last and back again
+)
+
+// AnimationData holds data for a single animation. Frames are regions of a
+// shared texture (sprite sheet or atlas), so entities playing the same clip
+// animate independently. Its clock runs on game time, so playback speed does
+// not depend on the frame rate.
 type AnimationData struct {
-	Image         *rl.Image
-	Texture       rl.Texture2D
-	FrameCount    int32
-	CurrentFrame  int32
-	FrameDelay    int32
-	FrameCounter  int32
-	FrameSize     int32
-	IsSpriteSheet bool
+	Texture        rl.Texture2D
+	Frames         []rl.Rectangle // Source rectangle of each frame in Texture
+	FrameCount     int32
+	CurrentFrame   int32
+	FrameDurations []float32 // Seconds per frame; a single entry applies to every frame
+	Elapsed        float32   // Time spent on CurrentFrame
+	Speed          float32   // Playback speed multiplier (1 = authored speed)
+	Mode           PlaybackMode
+	Direction      int32 // Current step of a ping-pong (+1 or -1)
+	Completed      bool  // Set once a full cycle has played; cleared on restart
+
+	// Named per-frame regions such as hitboxes and pivots (from Aseprite
+	// slices), indexed by frame
+	Slices map[string][]FrameSlice
+}
+
+// FrameSlice is a named region of one animation frame, in frame pixels.
+type FrameSlice struct {
+	Bounds   rl.Rectangle // Zero when the slice is absent on the frame
+	Pivot    rl.Vector2   // Relative to the frame
+	HasPivot bool
+}
+
+// Slice returns the named region of a frame, if the frame has one.
+func (a *AnimationData) Slice(name string, frame int32) (FrameSlice, bool) {
+	slices := a.Slices[name]
+	if frame < 0 || int(frame) >= len(slices) {
+		return FrameSlice{}, false
+	}
+	slice := slices[frame]
+	return slice, slice.Bounds.Width > 0 && slice.Bounds.Height > 0
+}
+
+// FrameRect returns the source rectangle of a frame. Without frame
+// rectangles the whole texture is one frame.
+func (a *AnimationData) FrameRect(frame int32) rl.Rectangle {
+	if frame >= 0 && int(frame) < len(a.Frames) {
+		return a.Frames[frame]
+	}
+	return rl.Rectangle{Width: float32(a.Texture.Width), Height: float32(a.Texture.Height)}
+}
+
+// FrameDuration returns how long the given frame is shown at normal speed.
+func (a *AnimationData) FrameDuration(frame int32) float32 {
+	switch {
+	case int(frame) < len(a.FrameDurations):
+		return a.FrameDurations[frame]
+	case len(a.FrameDurations) > 0:
+		return a.FrameDurations[0]
+	}
+	return 0
+}
+
+// FrameWidth returns the width of the current frame in texture pixels.
+func (a *AnimationData) FrameWidth() float32 {
+	return a.FrameRect(a.CurrentFrame).Width
 }
 
 // SpriteComponent holds visual representation data.
@@ -39,6 +156,7 @@ type SpriteComponent struct {
 	Animations   map[AnimationState]*AnimationData
 	CurrentAnim  AnimationState
 	Scale        float32
+	Restart     bool // Play CurrentAnim from its first frame on the next update
 }
 
 // GetCurrentAnimation returns the current animation data.
@@ -46,12 +164,218 @@ func (s *SpriteComponent) GetCurrentAnimation() *AnimationData {
 	return s.Animations[s.CurrentAnim]
 }
 
+// RenderLayer groups what is drawn: lower layers are drawn first. Layers
+// below LayerUI are drawn in world space through the camera; LayerUI is
+// drawn in screen space over everything.
+type RenderLayer int32
+
+const (
+	LayerParallax   RenderLayer = iota // Parallax backdrop
+	LayerBackground                    // Scenery behind the level
+	LayerTiles                         // Tiles and platforms
+	LayerEntities                      // Characters and mobs
+	LayerForeground                    // Scenery over characters (e.g. tree canopies)
+	LayerUI                            // Screen space
+)
+
+// RenderOrderComponent places an entity in the draw list. Entities without
+// one are drawn on their kind's default layer with order 0. Within a layer,
+// lower Order is drawn first and ties are drawn in entity ID order.
+type RenderOrderComponent struct {
+	Layer RenderLayer
+	Order float32
+}
+
+// ParallaxAnchor is where a parallax layer sits vertically.
+type ParallaxAnchor int32
+
+const (
+	AnchorScroll ParallaxAnchor = iota // Scrolls vertically by the Y scroll factor
+	AnchorTop                          // Top edge at the top of the view
+	AnchorBottom                       // Bottom edge at the bottom of the view
+)
+
+// ParallaxComponent is a backdrop layer that follows the camera at a
+// fraction of its movement. A scroll factor of 0 keeps the layer fixed on
+// screen and 1 moves it with the world; with a factor of 0 Offset is
+// relative to the view centre, with 1 it is a world position.
+type ParallaxComponent struct {
+	Texture      rl.Texture2D
+	Source       rl.Rectangle
+	Scale        float32
+	ScrollFactor rl.Vector2
+	Offset       rl.Vector2
+	Anchor       ParallaxAnchor
+	Repeat       bool    // Tile horizontally across the view
+	AutoScroll   float32 // Horizontal drift in units per second (e.g. clouds)
+	Drift        float32 // Distance drifted so far
+}
+
+// DecorationComponent is a static image drawn from a region of a texture,
+// such as scenery. Its top-left corner is at the entity position.
+type DecorationComponent struct {
+	Texture rl.Texture2D
+	Source  rl.Rectangle
+	Scale   float32
+}
+
+// ParticleTrigger is the gameplay event that bursts an emitter.
+type ParticleTrigger int
+
+const (
+	TriggerNone ParticleTrigger = iota
+	TriggerLand                 // Player landings, at the lander's feet
+	TriggerHit                  // Damage, at the centre of the hurt entity
+)
+
+// Particle is one live particle of an emitter, in world space.
+type Particle struct {
+	Position rl.Vector2
+	Velocity rl.Vector2
+	Rotation float32 // Degrees
+	Spin     float32 // Degrees per second
+	Age      float32
+	Lifetime float32
+}
+
+// ParticleBurst is a queued burst of particles at a world position.
+type ParticleBurst struct {
+	Position rl.Vector2
+	Count    int
+}
+
+// ParticleEmitterComponent emits particles continuously at Rate and in
+// bursts. Particles live in a fixed pool of MaxParticles allocated with the
+// emitter, so emitting never allocates; when the pool is full new particles
+// are dropped.
+type ParticleEmitterComponent struct {
+	// Look; untextured particles are drawn as squares
+	Texture    rl.Texture2D
+	Source     rl.Rectangle
+	ColorStart rl.Color // Colour and alpha at birth, blended to ColorEnd at death
+	ColorEnd   rl.Color
+	SizeStart  float32 // Width in world units at birth, blended to SizeEnd
+	SizeEnd    float32
+
+	// Emission
+	Emitting     bool    // Continuous emission is on
+	Rate         float32 // Particles per second while Emitting
+	BurstCount   int     // Particles per triggered burst
+	Trigger      ParticleTrigger
+	Offset       rl.Vector2 // Emission centre relative to the entity position
+	Area         rl.Vector2 // Size of the box particles start in, centred on the emission centre
+	LifetimeMin  float32    // Seconds
+	LifetimeMax  float32
+	SpeedMin     float32 // Units per second
+	SpeedMax     float32
+	Direction    float32    // Degrees; 0 is right, 90 is down
+	Spread       float32    // Width of the direction cone in degrees
+	Gravity      rl.Vector2 // Acceleration in units per second squared
+	Drag         float32    // Fraction of velocity lost per second
+	SpinMax      float32    // Maximum spin in degrees per second, either way
+	MaxParticles int
+
+	// Runtime state
+	Particles   []Particle      // Live particles; capacity is the pool
+	Bursts      []ParticleBurst // Queued by Burst, emitted on the next update
+	Accumulator float32         // Fraction of a particle owed to Rate
+}
+
+// Burst queues a burst of BurstCount particles at a world position.
+func (e *ParticleEmitterComponent) Burst(position rl.Vector2) {
+	e.Bursts = append(e.Bursts, ParticleBurst{Position: position, Count: e.BurstCount})
+}
+
+// SlopeShape describes the walkable top surface of a collider.
+type SlopeShape int32
+
+const (
+	SlopeNone         SlopeShape = iota // Flat top (full box)
+	Slope45Up                           // Rises from bottom-left to top-right
+	Slope45Down                         // Falls from top-left to bottom-right
+	Slope2to1UpLow                      // Lower half of a 2:1 rise (0 to half height)
+	Slope2to1UpHigh                     // Upper half of a 2:1 rise (half to full height)
+	Slope2to1DownHigh                   // Upper half of a 2:1 fall (full to half height)
+	Slope2to1DownLow                    // Lower half of a 2:1 fall (half to 0 height)
+)
+
+// slopeShapeNames are the slope shape names used in data files.
+var slopeShapeNames = map[SlopeShape]string{
+	SlopeNone:         "none",
+	Slope45Up:         "45up",
+	Slope45Down:       "45down",
+	Slope2to1UpLow:    "2to1upLow",
+	Slope2to1UpHigh:   "2to1upHigh",
+	Slope2to1DownHigh: "2to1downHigh",
+	Slope2to1DownLow:  "2to1downLow",
+}
+
+// String returns the slope shape name used in data files.
+func (s SlopeShape) String() string {
+	return slopeShapeNames[s]
+}
+
+// ParseSlopeShape returns the slope shape with the given data file name.
+func ParseSlopeShape(name string) (SlopeShape, bool) {
+	for shape, shapeName := range slopeShapeNames {
+		if shapeName == name {
+			return shape, true
+		}
+	}
+	return SlopeNone, false
+}
+
+// EdgeHeights returns the surface height at the left and right edges as a
+// fraction of the collider height.
+func (s SlopeShape) EdgeHeights() (left, right float32) {
+	switch s {
+	case Slope45Up:
+		return 0, 1
+	case Slope45Down:
+		return 1, 0
+	case Slope2to1UpLow:
+		return 0, 0.5
+	case Slope2to1UpHigh:
+		return 0.5, 1
+	case Slope2to1DownHigh:
+		return 1, 0.5
+	case Slope2to1DownLow:
+		return 0.5, 0
+	default:
+		return 1, 1
+	}
+}
+
 // ColliderComponent holds collision detection data.
 type ColliderComponent struct {
 	// Bounds is relative to the entity's position
 	Bounds    rl.Rectangle
-	IsTrigger bool   // If true, detects overlap but doesn't block
-	Layer     string // "player", "enemy", "ground", "collectible"
+	IsTrigger bool       // If true, detects overlap but doesn't block
+	OneWay    bool       // If true, only blocks entities landing on it from above
+	Slope     SlopeShape // Shape of the top surface (SlopeNone for a full box)
+	Layer     string     // "player", "enemy", "ground", "collectible"
+
+	// StandingBounds is the full-size collider. Bounds may be shrunk from it
+	// (rolling, sliding) with the bottom edge kept in place.
+	StandingBounds rl.Rectangle
+}
+
+// SetHeight resizes the collider to the given height, keeping the bottom
+// edge of StandingBounds in place.
+func (c *ColliderComponent) SetHeight(height float32) {
+	c.Bounds = c.StandingBounds
+	c.Bounds.Y += c.StandingBounds.Height - height
+	c.Bounds.Height = height
+}
+
+// Restore returns the collider to its standing size.
+func (c *ColliderComponent) Restore() {
+	c.Bounds = c.StandingBounds
+}
+
+// IsShrunk reports whether the collider is smaller than its standing size.
+func (c *ColliderComponent) IsShrunk() bool {
+	return c.Bounds.Height < c.StandingBounds.Height
 }
 
 // GetWorldBounds returns the collider bounds in world coordinates.
@@ -64,11 +388,33 @@ func (c *ColliderComponent) GetWorldBounds(position rl.Vector2) rl.Rectangle {
 	}
 }
 
+// SurfaceY returns the world Y of the collider's top surface at world x,
+// clamped to the collider's horizontal extent.
+func (c *ColliderComponent) SurfaceY(position rl.Vector2, x float32) float32 {
+	bounds := c.GetWorldBounds(position)
+	left, right := c.Slope.EdgeHeights()
+
+	t := (x - bounds.X) / bounds.Width
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+
+	height := left + (right-left)*t
+	return bounds.Y + bounds.Height*(1-height)
+}
+
 // InputComponent holds player input state.
 type InputComponent struct {
-	MoveX       float32 // -1.0 to 1.0
-	JumpPressed bool    // True on the frame jump was pressed
-	JumpHeld    bool    // True while jump key is held
+	MoveX         float32 // -1.0 to 1.0
+	JumpPressed   bool    // True on the frame jump was pressed
+	JumpHeld      bool    // True while jump key is held
+	DownHeld      bool    // True while down key is held
+	DashPressed   bool    // True on the frame dash was pressed
+	RollPressed   bool    // True on the frame roll was pressed
+	SlidePressed  bool    // True on the frame slide was pressed
+	AttackPressed bool    // True on the frame attack was pressed
 }
 
 // PhysicsComponent holds physics simulation data.
@@ -77,12 +423,124 @@ type PhysicsComponent struct {
 	JumpForce  float32
 	MoveSpeed  float32
 	IsOnGround bool
+
+	// Horizontal movement tuning (rates in units per second squared). A zero
+	// GroundAcceleration keeps the old instant start/stop behavior.
+	GroundAcceleration float32 // Rate towards MoveSpeed while input is held
+	GroundDeceleration float32 // Rate towards rest when input is released
+	TurnAroundBraking  float32 // Rate used when input opposes current velocity
+	AirControl         float32 // Multiplier on all rates while airborne (0..1)
+	TurnAroundSpeed    float32 // Minimum speed for a reversal to count as a turn-around
+
+	// Horizontal movement runtime state
+	SurfaceFriction float32 // Friction of the tile currently stood on (1 = normal)
+	IsTurningAround bool    // True while braking against a reversal at speed
+
+	// One-way platform state
+	DropThroughTime  float32 // How long one-way platforms are ignored after dropping through
+	DropThroughTimer float32
+	OnOneWayPlatform bool // True while standing on a one-way platform
+
+	// Moving platform state
+	GroundEntityID uint32     // EntityID of the moving platform stood on (0 if none)
+	GroundVelocity rl.Vector2 // Velocity of that platform, inherited when jumping off
+
+	// Crouching (Down held on the ground). A zero CrouchHeight disables it.
+	CrouchHeight          float32 // Collider height while crouched
+	CrouchSpeedMultiplier float32 // Multiplier on MoveSpeed while crouched (0..1)
+	CrouchTransitionTime  float32 // How long the crouch/stand transition pose is shown
+	CrouchTransitionTimer float32
+	IsCrouching           bool
+
+	// Wall contact from the last collision pass: -1 wall on the left, +1 wall on the right, 0 none
+	WallContact int
+
+	// Jump feel tuning (times in seconds, speeds in units per second)
+	JumpCutMultiplier float32 // Upward velocity kept when jump is released early (0..1, 0 or 1 disables)
+	CoyoteTime        float32 // Grace window to jump after walking off a ledge
+	JumpBufferTime    float32 // Window in which a press before landing still jumps
+	MaxFallSpeed      float32 // Terminal downward velocity (0 = unlimited)
+
+	// Jump feel runtime state
+	CoyoteTimer     float32
+	JumpBufferTimer float32
+	IsJumping       bool // True while rising from a jump that can still be cut
+}
+
+// AbilitiesComponent gates optional movement abilities. Levels unlock an
+// ability by setting its flag; the tuning values live alongside it.
+type AbilitiesComponent struct {
+	// Wall slide: slower falling while pressing into a wall
+	WallSlide      bool
+	WallSlideSpeed float32 // Maximum fall speed while sliding (units per second)
+
+	// Wall jump: jump away from a wall while airborne
+	WallJump         bool
+	WallJumpForce    rl.Vector2 // Kick away from the wall (X) and upwards (Y), units per second
+	WallJumpLockTime float32    // Seconds horizontal input is ignored after a wall jump
+
+	// Dash: fixed-distance horizontal burst that ignores gravity
+	Dash         bool
+	DashDistance float32
+	DashDuration float32
+	DashCooldown float32
+
+	// Roll: grounded burst with invulnerability and a shorter hitbox
+	Roll         bool
+	RollSpeed    float32
+	RollDuration float32
+	RollCooldown float32
+	RollHeight   float32 // Collider height while rolling
+
+	// Slide: grounded low-profile burst that fits under one-tile gaps
+	Slide         bool
+	SlideSpeed    float32
+	SlideDuration float32
+	SlideCooldown float32
+	SlideHeight   float32 // Collider height while sliding
+
+	// Runtime state
+	IsWallSliding     bool
+	WallJumpLockTimer float32
+
+	Action             MovementAction // Burst action in progress
+	ActionTimer        float32        // Time left in the current action
+	ActionDirection    float32        // -1 left, +1 right
+	DashEntrySpeed     float32        // Horizontal velocity before the dash, restored when it ends
+	RollInvulnerable   bool           // Roll i-frames; hazards and attacks skip the entity while set
+	DashCooldownTimer  float32
+	RollCooldownTimer  float32
+	SlideCooldownTimer float32
+}
+
+// MovementAction is a timed movement ability that overrides normal movement.
+type MovementAction int
+
+const (
+	ActionNone MovementAction = iota
+	ActionDash
+	ActionRoll
+	ActionSlide
+)
+
+// CoinComponent marks a coin the player picks up by touching its collider.
+type CoinComponent struct{}
+
+// ScoreComponent holds what an entity (the player) has earned.
+type ScoreComponent struct {
+	Score int
+	Coins int
 }
 
 // HealthComponent holds health/damage data.
 type HealthComponent struct {
-	Current int
-	Max     int
+	Current      int
+	Max          int
+	Invulnerable bool // While set, TakeDamage has no effect
+
+	// Damaging tiles hurt at most once per HazardCooldown seconds
+	HazardCooldown float32
+	HazardTimer    float32
 }
 
 // IsDead returns true if current health is zero or less.
@@ -90,8 +548,11 @@ func (h *HealthComponent) IsDead() bool {
 	return h.Current <= 0
 }
 
-// TakeDamage reduces health by the specified amount.
+// TakeDamage reduces health by the specified amount unless invulnerable.
 func (h *HealthComponent) TakeDamage(amount int) {
+	if h.Invulnerable {
+		return
+	}
 	h.Current -= amount
 	if h.Current < 0 {
 		h.Current = 0
@@ -106,7 +567,7 @@ func (h *HealthComponent) Heal(amount int) {
 	}
 }
 
-// TileType represents the type of tile.
+// TileType represents the type of tile: its ID in the tileset.
 type TileType int32
 
 const (
@@ -115,11 +576,19 @@ const (
 	TileWater
 	TileTree
 	TileRock
+	TileIce
 )
 
-// TileComponent marks an entity as a static tile.
+// TileComponent marks an entity as a static tile. Its properties come from
+// the tileset; non-solid tiles have a trigger collider.
 type TileComponent struct {
 	TileType TileType
+	Shape    SlopeShape   // Slope piece drawn for this tile (SlopeNone for a full tile)
+	Friction float32      // Surface friction (1 = normal, <1 slippery, >1 grippy)
+	Damage   int          // Health lost by entities touching the tile
+	Texture  rl.Texture2D // Tileset atlas
+	Source   rl.Rectangle // Piece of the atlas drawn over the collider
+	Tint     rl.Color
 }
 
 // AIBehavior represents the type of AI behavior.
@@ -131,10 +600,381 @@ const (
 	AIIdle
 )
 
+// AttackHitbox is a damaging area that is active for a range of frames of an
+// attack animation. Rect is in unscaled sprite-frame pixels for an entity
+// facing right and is mirrored when it faces left.
+type AttackHitbox struct {
+	StartFrame int32
+	EndFrame   int32 // Inclusive
+	Rect       rl.Rectangle
+}
+
+// AttackDefinition describes one melee attack. Definitions are loaded from
+// data (see core.LoadAttackSet) rather than built in code.
+type AttackDefinition struct {
+	Name        string
+	Animation   AnimationState
+	Damage      int
+	Hitboxes    []AttackHitbox
+	Knockback   rl.Vector2 // Velocity given to the target; X points away from the attacker
+	HitStop     float32    // Seconds the simulation freezes when the attack lands
+	ComboWindow float32    // Seconds after the attack in which a press chains the next one
+}
+
+// MeleeComponent gives an entity melee attacks: a combo chain played in
+// order on timely presses, and an optional attack used while crouching.
+type MeleeComponent struct {
+	Combo        []AttackDefinition
+	CrouchAttack *AttackDefinition // nil disables attacking while crouched
+	TargetTag    string            // Only entities with this tag are hit
+
+	// Runtime state
+	Current          *AttackDefinition // Attack in progress (nil if none)
+	ComboIndex       int               // Index of the current or last attack in Combo (-1 none)
+	ComboQueued      bool              // A press during the attack chains the next one
+	ComboWindowTimer float32
+	ActiveHitbox     int             // Index of the hitbox hits are tracked for (-1 none)
+	HitTargets       map[uint32]bool // Entities already hit by ActiveHitbox
+}
+
+// IsAttacking reports whether an attack is in progress.
+func (m *MeleeComponent) IsAttacking() bool {
+	return m.Current != nil
+}
+
+// AnimatorCondition is a named predicate evaluated by AnimationSystem (for
+// example "onGround" or "action:dash"), optionally negated.
+type AnimatorCondition struct {
+	Name   string
+	Negate bool
+}
+
+// AnimatorState is a named state of an animation state machine.
+type AnimatorState struct {
+	Name    string
+	Clip    AnimationState
+	OneShot bool   // Plays once; only interrupts can leave it before it finishes
+	Next    string // State entered when a one-shot finishes ("" = the default state)
+	Hold    bool   // One-shot that stays on its last frame; only transitions from it by name leave it
+
+	// Playback
+	Mode           PlaybackMode
+	Speed          float32              // Speed multiplier (0 = 1)
+	SpeedBy        string               // Optional value scaling the speed, e.g. "speedX"
+	SpeedReference float32              // SpeedBy value that plays at Speed
+	Events         []AnimatorFrameEvent // Published when their frame is entered
+}
+
+// AnimatorFrameEvent names a frame of a state's clip. Entering the frame
+// publishes an ecs.EventAnimationFrame, e.g. for footstep sounds.
+type AnimatorFrameEvent struct {
+	Frame int32
+	Name  string
+}
+
+// AnimatorTransition moves the machine to To when all its conditions hold.
+type AnimatorTransition struct {
+	From       string // "" matches any state
+	To         string
+	Conditions []AnimatorCondition
+	Interrupt  bool // May cut a one-shot short
+}
+
+// AnimatorController is an animation state machine definition. Entities can
+// share one; per-entity state lives in AnimatorComponent.
+type AnimatorController struct {
+	Default     string
+	States      map[string]*AnimatorState
+	Transitions []AnimatorTransition // In priority order; the first match wins
+}
+
+// AnimatorComponent runs an AnimatorController for one entity. Entities
+// without one keep playing their sprite's current animation.
+type AnimatorComponent struct {
+	Controller *AnimatorController
+	Current    string          // Name of the current state ("" before the first update)
+	Finished   bool            // The current one-shot has played through
+	Triggers   map[string]bool // One-frame signals from gameplay, e.g. "hit"
+}
+
+// SetTrigger raises a trigger for the next animation update.
+func (a *AnimatorComponent) SetTrigger(name string) {
+	if a.Triggers == nil {
+		a.Triggers = make(map[string]bool)
+	}
+	a.Triggers[name] = true
+}
+
 // AIComponent holds AI behavior data.
 type AIComponent struct {
 	Behavior   AIBehavior
 	PatrolPath []rl.Vector2
 	PathIndex  int
-	TargetID   uint32 // EntityID of target (0 if none)
+	SightRange float32 // Distance at which a target is noticed
+	TargetID   uint32  // EntityID of target (0 if none)
+}
+
+// EasingType selects how a moving platform accelerates between waypoints.
+type EasingType int
+
+const (
+	EaseLinear EasingType = iota
+	EaseInOutSine
+	EaseInOutCubic
+)
+
+// Apply maps linear progress t (0..1) to eased progress.
+func (e EasingType) Apply(t float32) float32 {
+	switch e {
+	case EaseInOutSine:
+		return float32(-(math.Cos(math.Pi*float64(t)) - 1) / 2)
+	case EaseInOutCubic:
+		if t < 0.5 {
+			return 4 * t * t * t
+		}
+		f := -2*t + 2
+		return 1 - f*f*f/2
+	default:
+		return t
+	}
+}
+
+// PlatformComponent makes an entity a kinematic platform that follows a
+// waypoint path. Entities standing on it are carried along.
+type PlatformComponent struct {
+	Waypoints []rl.Vector2
+	Speed     float32    // Travel speed in units per second
+	WaitTime  float32    // Pause at each waypoint in seconds
+	Easing    EasingType // Easing applied to each segment
+	PingPong  bool       // Reverse at the ends instead of looping back to the start
+
+	// Drawn by repeating a tileset piece across the collider, one piece
+	// every PieceWidth units
+	Texture    rl.Texture2D
+	Source     rl.Rectangle
+	Tint       rl.Color
+	PieceWidth float32
+
+	// Runtime state
+	FromIndex int     // Waypoint the current segment starts at
+	Direction int     // +1 forwards, -1 backwards along the path (ping-pong)
+	Progress  float32 // Linear progress along the current segment (0..1)
+	WaitTimer float32
+}
+
+// CameraZoneMode selects how a camera zone holds the camera.
+type CameraZoneMode int
+
+const (
+	// ZoneConstrain keeps following the target but only inside the zone.
+	ZoneConstrain CameraZoneMode = iota
+	// ZoneLock fixes the view on the centre of the zone (e.g. a boss room).
+	ZoneLock
+)
+
+// CameraZone is a level area that takes over the camera while the followed
+// entity is inside it.
+type CameraZone struct {
+	Area rl.Rectangle
+	Mode CameraZoneMode
+	Zoom float32 // Zoom inside the zone (0 keeps the default zoom)
+}
+
+// CameraComponent is a 2D camera that follows an entity. CameraSystem moves
+// it and RenderSystem draws the world through it. Effects (screen shake,
+// zoom) compose on top of the follow and are driven through AddTrauma,
+// SetZoom and ZoomPulse.
+type CameraComponent struct {
+	Camera   rl.Camera2D // Target is the world point drawn at Offset (the screen centre)
+	TargetID uint32      // EntityID of the followed entity (0 if none)
+
+	DeadzoneWidth  float32      // World units the target moves freely around the focus
+	DeadzoneHeight float32      // before the camera follows
+	Smoothing      float32      // Follow rate in 1/s; 0 snaps to the focus
+	LookAhead      float32      // World units shown ahead in the facing direction
+	LookAheadSpeed float32      // Rate in 1/s at which the look-ahead swings around
+	Bounds         rl.Rectangle // Level area the view stays inside (empty = unbounded)
+	Zones          []CameraZone
+	ZoneBlendTime  float32 // Seconds to ease into a zone's zoom and back
+
+	// Screen shake: strength is Trauma squared, so small knocks stay subtle
+	TraumaDecay    float32 // Trauma lost per second
+	ShakeOffset    float32 // Maximum offset in world units at full trauma
+	ShakeAngle     float32 // Maximum rotation in degrees at full trauma
+	ShakeFrequency float32 // Speed of the shake noise
+	DamageTrauma   float32 // Trauma added when damage is dealt
+	LandTrauma     float32 // Trauma added by a landing at LandFullSpeed
+	LandMinSpeed   float32 // Slowest landing that shakes
+	LandFullSpeed  float32 // Landing speed giving the full LandTrauma
+
+	DefaultZoom float32 // Zoom outside zones and effects
+
+	// Runtime state
+	Focus           rl.Vector2 // Deadzone centre the camera moves towards
+	Center          rl.Vector2 // Followed view centre before shake
+	LookAheadOffset float32    // Current horizontal look-ahead
+	Snap            bool       // Jump straight to the target on the next update
+	ActiveZone      int        // Index into Zones, -1 outside every zone
+	Trauma          float32    // 0..1
+	ShakeTime       float32
+
+	ZoomFrom, ZoomTo          float32 // Base zoom transition (ZoomTo 0 = DefaultZoom)
+	ZoomTimer, ZoomDuration   float32
+	PulseZoom                 float32 // Zoom factor at the peak of a pulse
+	PulseTimer, PulseDuration float32
+}
+
+// AddTrauma adds screen shake; trauma is capped at 1 and decays over time.
+func (c *CameraComponent) AddTrauma(amount float32) {
+	c.Trauma = min(1, c.Trauma+amount)
+}
+
+// SetZoom eases the base zoom to zoom over duration seconds.
+func (c *CameraComponent) SetZoom(zoom, duration float32) {
+	c.ZoomFrom = c.BaseZoom()
+	c.ZoomTo = zoom
+	c.ZoomDuration = duration
+	c.ZoomTimer = 0
+}
+
+// ZoomPulse scales the zoom by factor and eases it back over duration
+// seconds, on top of the base zoom.
+func (c *CameraComponent) ZoomPulse(factor, duration float32) {
+	c.PulseZoom = factor
+	c.PulseDuration = duration
+	c.PulseTimer = 0
+}
+
+// BaseZoom returns the zoom without pulses.
+func (c *CameraComponent) BaseZoom() float32 {
+	if c.ZoomTo <= 0 {
+		if c.DefaultZoom > 0 {
+			return c.DefaultZoom
+		}
+		return 1
+	}
+	if c.ZoomTimer >= c.ZoomDuration {
+		return c.ZoomTo
+	}
+	t := EaseInOutSine.Apply(c.ZoomTimer / c.ZoomDuration)
+	return c.ZoomFrom + (c.ZoomTo-c.ZoomFrom)*t
+}
+
+// PulseFactor returns the current zoom pulse multiplier (1 when idle). The
+// pulse rises and falls along a sine arc.
+func (c *CameraComponent) PulseFactor() float32 {
+	if c.PulseDuration <= 0 || c.PulseTimer >= c.PulseDuration {
+		return 1
+	}
+	arc := float32(math.Sin(math.Pi * float64(c.PulseTimer/c.PulseDuration)))
+	return 1 + (c.PulseZoom-1)*arc
+}
+
+// AdvanceEffects runs the zoom timers by dt.
+func (c *CameraComponent) AdvanceEffects(dt float32) {
+	if c.ZoomTimer < c.ZoomDuration {
+		c.ZoomTimer += dt
+	}
+	if c.PulseTimer < c.PulseDuration {
+		c.PulseTimer += dt
+	}
+}
+
+// ViewSize returns the size of the visible world area.
+func (c *CameraComponent) ViewSize() rl.Vector2 {
+	zoom := c.Camera.Zoom
+	if zoom <= 0 {
+		zoom = 1
+	}
+	return rl.Vector2{X: c.Camera.Offset.X * 2 / zoom, Y: c.Camera.Offset.Y * 2 / zoom}
+}
+
+// View returns the visible world area.
+func (c *CameraComponent) View() rl.Rectangle {
+	size := c.ViewSize()
+	return rl.Rectangle{
+		X:      c.Camera.Target.X - size.X/2,
+		Y:      c.Camera.Target.Y - size.Y/2,
+		Width:  size.X,
+		Height: size.Y,
+	}
+}
+
+// WorldToScreen converts a world position to screen pixels.
+func (c *CameraComponent) WorldToScreen(position rl.Vector2) rl.Vector2 {
+	return rl.GetWorldToScreen2D(position, c.Camera)
+}
+
+// ScreenToWorld converts a screen position (e.g. the mouse) to the world.
+func (c *CameraComponent) ScreenToWorld(position rl.Vector2) rl.Vector2 {
+	return rl.GetScreenToWorld2D(position, c.Camera)
+}
+
+// DebugShapeKind is the kind of shape queued for the debug overlay.
+type DebugShapeKind int
+
+const (
+	DebugLine DebugShapeKind = iota
+	DebugArrow
+	DebugRect
+	DebugCircle
+	DebugText
+)
+
+// DebugShape is a shape queued for the debug overlay, in world units. Lines
+// and arrows run From To; circles and text are placed at From.
+type DebugShape struct {
+	Kind   DebugShapeKind
+	From   rl.Vector2
+	To     rl.Vector2
+	Rect   rl.Rectangle
+	Radius float32
+	Text   string
+	Color  rl.Color
+}
+
+// DebugDrawComponent is the queue of the debug overlay. Systems queue shapes
+// into it during their update (see systems.FindDebugDraw) and RenderSystem
+// draws them over the world at the end of the frame, then clears the queue.
+// Queueing into a nil queue does nothing, so callers need not check whether
+// the overlay is on.
+type DebugDrawComponent struct {
+	Enabled bool
+	Shapes  []DebugShape
+}
+
+// Line queues a line.
+func (d *DebugDrawComponent) Line(from, to rl.Vector2, color rl.Color) {
+	if d != nil {
+		d.Shapes = append(d.Shapes, DebugShape{Kind: DebugLine, From: from, To: to, Color: color})
+	}
+}
+
+// Arrow queues a line with a head at to, e.g. a velocity or a normal.
+func (d *DebugDrawComponent) Arrow(from, to rl.Vector2, color rl.Color) {
+	if d != nil {
+		d.Shapes = append(d.Shapes, DebugShape{Kind: DebugArrow, From: from, To: to, Color: color})
+	}
+}
+
+// Rect queues a rectangle outline.
+func (d *DebugDrawComponent) Rect(rect rl.Rectangle, color rl.Color) {
+	if d != nil {
+		d.Shapes = append(d.Shapes, DebugShape{Kind: DebugRect, Rect: rect, Color: color})
+	}
+}
+
+// Circle queues a circle outline.
+func (d *DebugDrawComponent) Circle(centre rl.Vector2, radius float32, color rl.Color) {
+	if d != nil {
+		d.Shapes = append(d.Shapes, DebugShape{Kind: DebugCircle, From: centre, Radius: radius, Color: color})
+	}
+}
+
+// Text queues a label with its top-left corner at position. Labels keep
+// their size on screen whatever the zoom.
+func (d *DebugDrawComponent) Text(position rl.Vector2, text string, color rl.Color) {
+	if d != nil {
+		d.Shapes = append(d.Shapes, DebugShape{Kind: DebugText, From: position, Text: text, Color: color})
+	}
 }
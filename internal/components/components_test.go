@@ -0,0 +1,68 @@
+package components
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestSurfaceY(t *testing.T) {
+	// A 16x16 tile at (32, 64): surface Y at its left edge, centre and
+	// right edge, plus one point beyond each edge to check the clamp
+	tests := []struct {
+		shape SlopeShape
+		want  [3]float32 // Left, centre, right
+	}{
+		{SlopeNone, [3]float32{64, 64, 64}},
+		{Slope45Up, [3]float32{80, 72, 64}},
+		{Slope45Down, [3]float32{64, 72, 80}},
+		{Slope2to1UpLow, [3]float32{80, 76, 72}},
+		{Slope2to1UpHigh, [3]float32{72, 68, 64}},
+		{Slope2to1DownHigh, [3]float32{64, 68, 72}},
+		{Slope2to1DownLow, [3]float32{72, 76, 80}},
+	}
+	position := rl.Vector2{X: 32, Y: 64}
+	for _, tt := range tests {
+		t.Run(tt.shape.String(), func(t *testing.T) {
+			collider := &ColliderComponent{Bounds: rl.Rectangle{Width: 16, Height: 16}, Slope: tt.shape}
+			for i, x := range []float32{32, 40, 48} {
+				if got := collider.SurfaceY(position, x); got != tt.want[i] {
+					t.Errorf("SurfaceY(%v) = %v, want %v", x, got, tt.want[i])
+				}
+			}
+			if got := collider.SurfaceY(position, 20); got != tt.want[0] {
+				t.Errorf("SurfaceY left of the tile = %v, want %v", got, tt.want[0])
+			}
+			if got := collider.SurfaceY(position, 60); got != tt.want[2] {
+				t.Errorf("SurfaceY right of the tile = %v, want %v", got, tt.want[2])
+			}
+		})
+	}
+}
+
+func TestSlopePairsJoin(t *testing.T) {
+	// The halves of a 2:1 ramp meet at half height, so two tiles side by
+	// side form one continuous surface
+	pairs := [][2]SlopeShape{
+		{Slope2to1UpLow, Slope2to1UpHigh},
+		{Slope2to1DownHigh, Slope2to1DownLow},
+	}
+	for _, pair := range pairs {
+		_, right := pair[0].EdgeHeights()
+		left, _ := pair[1].EdgeHeights()
+		if right != left {
+			t.Errorf("%v ends at %v but %v starts at %v", pair[0], right, pair[1], left)
+		}
+	}
+}
+
+func TestParseSlopeShape(t *testing.T) {
+	for shape, name := range slopeShapeNames {
+		if got, ok := ParseSlopeShape(name); !ok || got != shape {
+			t.Errorf("ParseSlopeShape(%q) = %v, %v; want %v", name, got, ok, shape)
+		}
+	}
+	if _, ok := ParseSlopeShape("22upLow"); ok {
+		t.Error("ParseSlopeShape accepted an unknown name")
+	}
+}
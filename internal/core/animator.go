@@ -0,0 +1,151 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"fire/internal/components"
+)
+
+// AnimatorStateJSON represents an animation state in the JSON format.
+type AnimatorStateJSON struct {
+	Name    string `json:"name"`
+	Clip    string `json:"clip"` // Clip name, see components.ParseAnimationState
+	OneShot bool   `json:"oneShot,omitempty"`
+	Next    string `json:"next,omitempty"` // State after a one-shot (default state if empty)
+	Hold    bool   `json:"hold,omitempty"` // Stay on the last frame of a one-shot
+
+	Mode           string           `json:"mode,omitempty"`           // "forward" (default), "reverse" or "pingPong"
+	Speed          float32          `json:"speed,omitempty"`          // Playback speed multiplier (default 1)
+	SpeedBy        string           `json:"speedBy,omitempty"`        // Scale speed by a value, e.g. "speedX"
+	SpeedReference float32          `json:"speedReference,omitempty"` // SpeedBy value that plays at Speed
+	Events         []FrameEventJSON `json:"events,omitempty"`
+}
+
+// FrameEventJSON names a frame of a state's clip in the JSON format.
+type FrameEventJSON struct {
+	Frame int32  `json:"frame"`
+	Name  string `json:"name"`
+}
+
+// playbackModes maps the playback mode names used in animator data.
+var playbackModes = map[string]components.PlaybackMode{
+	"":         components.PlayForward,
+	"forward":  components.PlayForward,
+	"reverse":  components.PlayReverse,
+	"pingPong": components.PlayPingPong,
+}
+
+// AnimatorTransitionJSON represents a state transition in the JSON format.
+// Conditions prefixed with "!" are negated.
+type AnimatorTransitionJSON struct {
+	From      string   `json:"from,omitempty"` // Empty or "*" for any state
+	To        string   `json:"to"`
+	When      []string `json:"when,omitempty"`
+	Interrupt bool     `json:"interrupt,omitempty"`
+}
+
+// AnimatorJSON is an animation state machine in the JSON format.
+// Transitions are listed in priority order.
+type AnimatorJSON struct {
+	Default     string                   `json:"default"`
+	States      []AnimatorStateJSON      `json:"states"`
+	Transitions []AnimatorTransitionJSON `json:"transitions"`
+}
+
+// LoadAnimatorController loads and validates an animation state machine
+// from a JSON file.
+func LoadAnimatorController(path string) (*components.AnimatorController, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var animator AnimatorJSON
+	if err := json.Unmarshal(data, &animator); err != nil {
+		return nil, err
+	}
+
+	return animator.ToController()
+}
+
+// ToController converts the JSON state machine into a controller. Unknown
+// clips and references to undefined states are errors.
+func (a AnimatorJSON) ToController() (*components.AnimatorController, error) {
+	controller := &components.AnimatorController{
+		Default:     a.Default,
+		States:      make(map[string]*components.AnimatorState, len(a.States)),
+		Transitions: make([]components.AnimatorTransition, 0, len(a.Transitions)),
+	}
+
+	for _, state := range a.States {
+		clip, ok := components.ParseAnimationState(state.Clip)
+		if !ok {
+			return nil, fmt.Errorf("state %q: unknown clip %q", state.Name, state.Clip)
+		}
+		mode, ok := playbackModes[state.Mode]
+		if !ok {
+			return nil, fmt.Errorf("state %q: unknown playback mode %q", state.Name, state.Mode)
+		}
+		if _, exists := controller.States[state.Name]; exists {
+			return nil, fmt.Errorf("state %q defined twice", state.Name)
+		}
+
+		events := make([]components.AnimatorFrameEvent, 0, len(state.Events))
+		for _, event := range state.Events {
+			events = append(events, components.AnimatorFrameEvent{Frame: event.Frame, Name: event.Name})
+		}
+
+		controller.States[state.Name] = &components.AnimatorState{
+			Name:           state.Name,
+			Clip:           clip,
+			OneShot:        state.OneShot,
+			Next:           state.Next,
+			Hold:           state.Hold,
+			Mode:           mode,
+			Speed:          state.Speed,
+			SpeedBy:        state.SpeedBy,
+			SpeedReference: state.SpeedReference,
+			Events:         events,
+		}
+	}
+
+	if _, ok := controller.States[a.Default]; !ok {
+		return nil, fmt.Errorf("default state %q is not defined", a.Default)
+	}
+	for _, state := range controller.States {
+		if _, ok := controller.States[state.Next]; state.Next != "" && !ok {
+			return nil, fmt.Errorf("state %q: next state %q is not defined", state.Name, state.Next)
+		}
+	}
+
+	for _, transition := range a.Transitions {
+		from := transition.From
+		if from == "*" {
+			from = ""
+		}
+		if _, ok := controller.States[from]; from != "" && !ok {
+			return nil, fmt.Errorf("transition from undefined state %q", transition.From)
+		}
+		if _, ok := controller.States[transition.To]; !ok {
+			return nil, fmt.Errorf("transition to undefined state %q", transition.To)
+		}
+
+		conditions := make([]components.AnimatorCondition, 0, len(transition.When))
+		for _, when := range transition.When {
+			name, negate := strings.CutPrefix(when, "!")
+			conditions = append(conditions, components.AnimatorCondition{Name: name, Negate: negate})
+		}
+
+		controller.Transitions = append(controller.Transitions, components.AnimatorTransition{
+			From:       from,
+			To:         transition.To,
+			Conditions: conditions,
+			Interrupt:  transition.Interrupt,
+		})
+	}
+
+	return controller, nil
+}
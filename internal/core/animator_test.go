@@ -0,0 +1,63 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadHeroAnimator(t *testing.T) {
+	controller, err := LoadAnimatorController(ResourcePath(DefaultHeroAnimatorPath))
+	if err != nil {
+		t.Fatalf("shipped hero animator is invalid: %v", err)
+	}
+	if _, ok := controller.States[controller.Default]; !ok {
+		t.Errorf("default state %q missing", controller.Default)
+	}
+}
+
+func TestAnimatorToController(t *testing.T) {
+	valid := func() AnimatorJSON {
+		return AnimatorJSON{
+			Default: "idle",
+			States: []AnimatorStateJSON{
+				{Name: "idle", Clip: "idle"},
+				{Name: "attack", Clip: "attack", OneShot: true, Next: "idle"},
+			},
+			Transitions: []AnimatorTransitionJSON{
+				{From: "*", To: "attack", When: []string{"attacking", "!dead"}},
+			},
+		}
+	}
+
+	controller, err := valid().ToController()
+	if err != nil {
+		t.Fatalf("valid animator rejected: %v", err)
+	}
+	conditions := controller.Transitions[0].Conditions
+	if controller.Transitions[0].From != "" || len(conditions) != 2 || !conditions[1].Negate || conditions[1].Name != "dead" {
+		t.Errorf("transition parsed as %+v", controller.Transitions[0])
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*AnimatorJSON)
+		want   string
+	}{
+		{"unknown clip", func(a *AnimatorJSON) { a.States[0].Clip = "fly" }, "unknown clip"},
+		{"unknown mode", func(a *AnimatorJSON) { a.States[0].Mode = "sideways" }, "unknown playback mode"},
+		{"duplicate state", func(a *AnimatorJSON) { a.States[1].Name = "idle" }, "defined twice"},
+		{"missing default", func(a *AnimatorJSON) { a.Default = "run" }, "default state"},
+		{"missing next", func(a *AnimatorJSON) { a.States[1].Next = "recover" }, "next state"},
+		{"missing target", func(a *AnimatorJSON) { a.Transitions[0].To = "run" }, "transition to"},
+		{"missing source", func(a *AnimatorJSON) { a.Transitions[0].From = "run" }, "transition from"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			animator := valid()
+			tt.modify(&animator)
+			if _, err := animator.ToController(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+
+	"fire/internal/aseprite"
+	"fire/internal/components"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// AsepriteAllFrames is the clip covering every frame of an Aseprite file. A
+// tag of the same name replaces it.
+const AsepriteAllFrames = "all"
+
+// asepriteAtlasMaxWidth caps the width of the texture frames are packed into.
+const asepriteAtlasMaxWidth = 4096
+
+// asepriteModes maps tag directions to playback modes. Ping-pong reverse
+// plays as ping-pong.
+var asepriteModes = map[aseprite.Direction]components.PlaybackMode{
+	aseprite.Forward:         components.PlayForward,
+	aseprite.Reverse:         components.PlayReverse,
+	aseprite.PingPong:        components.PlayPingPong,
+	aseprite.PingPongReverse: components.PlayPingPong,
+}
+
+// loadAsepriteData loads an Aseprite file, packs its frames into a single
+// texture and returns an animation clip per tag, plus AsepriteAllFrames.
+// Frame durations, playback direction and slices come from the file, so art
+// changes need no code change. The clips share one texture; unload it once.
+func loadAsepriteData(path string) (map[string]AnimationDataLegacy, error) {
+	file, err := aseprite.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Frames) == 0 {
+		return nil, fmt.Errorf("%s: no frames", path)
+	}
+
+	texture, rects, err := packAsepriteFrames(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	clips := map[string]AnimationDataLegacy{
+		AsepriteAllFrames: asepriteClip(file, texture, rects, aseprite.Tag{From: 0, To: len(file.Frames) - 1}),
+	}
+	for _, tag := range file.Tags {
+		if tag.From < 0 || tag.To >= len(file.Frames) || tag.From > tag.To {
+			return nil, fmt.Errorf("%s: tag %q has bad frame range %d-%d", path, tag.Name, tag.From, tag.To)
+		}
+		clips[tag.Name] = asepriteClip(file, texture, rects, tag)
+	}
+	return clips, nil
+}
+
+// packAsepriteFrames draws the frames into a grid, uploads it as a texture
+// and returns the source rectangle of each frame.
+func packAsepriteFrames(file *aseprite.File) (rl.Texture2D, []rl.Rectangle, error) {
+	columns := len(file.Frames)
+	if file.Width*columns > asepriteAtlasMaxWidth {
+		columns = max(1, asepriteAtlasMaxWidth/file.Width)
+	}
+	rows := (len(file.Frames) + columns - 1) / columns
+
+	atlas := image.NewNRGBA(image.Rect(0, 0, file.Width*columns, file.Height*rows))
+	rects := make([]rl.Rectangle, len(file.Frames))
+	for i, frame := range file.Frames {
+		x, y := (i%columns)*file.Width, (i/columns)*file.Height
+		draw.Draw(atlas, frame.Image.Bounds().Add(image.Pt(x, y)), frame.Image, image.Point{}, draw.Src)
+		rects[i] = rl.Rectangle{X: float32(x), Y: float32(y), Width: float32(file.Width), Height: float32(file.Height)}
+	}
+
+	// Round-trip through PNG so raylib copies the pixels in one call
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, atlas); err != nil {
+		return rl.Texture2D{}, nil, err
+	}
+	img := rl.LoadImageFromMemory(".png", encoded.Bytes(), int32(encoded.Len()))
+	defer rl.UnloadImage(img)
+	return rl.LoadTextureFromImage(img), rects, nil
+}
+
+// asepriteClip builds the animation data of a tag's frame range.
+func asepriteClip(file *aseprite.File, texture rl.Texture2D, rects []rl.Rectangle, tag aseprite.Tag) AnimationDataLegacy {
+	count := tag.To - tag.From + 1
+	durations := make([]float32, 0, count)
+	for i := tag.From; i <= tag.To; i++ {
+		durations = append(durations, file.Frames[i].Duration)
+	}
+
+	slices := make(map[string][]components.FrameSlice, len(file.Slices))
+	for _, slice := range file.Slices {
+		frames := make([]components.FrameSlice, count)
+		for i := range frames {
+			key, ok := slice.KeyAt(tag.From + i)
+			if !ok || key.Bounds.Empty() {
+				continue
+			}
+			frames[i] = components.FrameSlice{
+				Bounds: rl.Rectangle{
+					X:      float32(key.Bounds.Min.X),
+					Y:      float32(key.Bounds.Min.Y),
+					Width:  float32(key.Bounds.Dx()),
+					Height: float32(key.Bounds.Dy()),
+				},
+				Pivot:    rl.Vector2{X: float32(key.Bounds.Min.X + key.Pivot.X), Y: float32(key.Bounds.Min.Y + key.Pivot.Y)},
+				HasPivot: key.HasPivot,
+			}
+		}
+		slices[slice.Name] = frames
+	}
+
+	return AnimationDataLegacy{
+		Texture:        texture,
+		Frames:         rects[tag.From : tag.To+1],
+		FrameCount:     int32(count),
+		FrameDurations: durations,
+		Mode:           asepriteModes[tag.Direction],
+		Slices:         slices,
+	}
+}
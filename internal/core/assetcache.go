@@ -0,0 +1,245 @@
+package core
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// AnimationSet is a loaded animation set: clips by name. Clips may share
+// textures; the cache owns them.
+type AnimationSet struct {
+	Clips map[string]AnimationDataLegacy
+}
+
+// Region is a loaded texture region.
+type Region struct {
+	Texture rl.Texture2D
+	Source  rl.Rectangle
+}
+
+// assetEntry is a loaded asset with its reference count and the function
+// that frees it.
+type assetEntry struct {
+	refs   int
+	value  any
+	unload func()
+}
+
+// AssetCache loads the assets of a manifest by key and keeps them while they
+// are referenced. Every asset is freed through the cache: when its last
+// reference is released, or by UnloadAll.
+type AssetCache struct {
+	manifest AssetManifestJSON
+	entries  map[string]*assetEntry
+}
+
+// NewAssetCache creates an empty cache for a manifest.
+func NewAssetCache(manifest AssetManifestJSON) *AssetCache {
+	return &AssetCache{
+		manifest: manifest,
+		entries:  make(map[string]*assetEntry),
+	}
+}
+
+// Acquire adds a reference to an asset, loading it on first use.
+func (c *AssetCache) Acquire(key string) error {
+	if entry, ok := c.entries[key]; ok {
+		entry.refs++
+		return nil
+	}
+
+	entry, err := c.load(key)
+	if err != nil {
+		return err
+	}
+	entry.refs = 1
+	c.entries[key] = entry
+	return nil
+}
+
+// Release drops a reference to an asset and unloads it when none are left.
+func (c *AssetCache) Release(key string) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	entry.refs--
+	if entry.refs > 0 {
+		return
+	}
+	delete(c.entries, key)
+	entry.unload()
+}
+
+// UnloadAll unloads every asset regardless of references.
+func (c *AssetCache) UnloadAll() {
+	for len(c.entries) > 0 {
+		for key, entry := range c.entries {
+			delete(c.entries, key)
+			entry.unload()
+			break
+		}
+	}
+}
+
+// Texture returns a loaded texture, or a zero texture if key is not loaded.
+func (c *AssetCache) Texture(key string) rl.Texture2D {
+	texture, _ := c.value(key).(rl.Texture2D)
+	return texture
+}
+
+// Font returns a loaded font, or raylib's default font if key is not loaded.
+func (c *AssetCache) Font(key string) rl.Font {
+	if font, ok := c.value(key).(rl.Font); ok {
+		return font
+	}
+	return rl.GetFontDefault()
+}
+
+// Animations returns a loaded animation set, or nil if key is not loaded.
+func (c *AssetCache) Animations(key string) *AnimationSet {
+	set, _ := c.value(key).(*AnimationSet)
+	return set
+}
+
+// Tileset returns a loaded tileset, or nil if key is not loaded.
+func (c *AssetCache) Tileset(key string) *Tileset {
+	tileset, _ := c.value(key).(*Tileset)
+	return tileset
+}
+
+// Region returns a loaded texture region, or nil if key is not loaded.
+func (c *AssetCache) Region(key string) *Region {
+	region, _ := c.value(key).(*Region)
+	return region
+}
+
+// Mob returns the manifest's mob data for an animation set key.
+func (c *AssetCache) Mob(key string) (MobJSON, bool) {
+	mob, ok := c.manifest.Mobs[key]
+	return mob, ok
+}
+
+func (c *AssetCache) value(key string) any {
+	if entry, ok := c.entries[key]; ok {
+		return entry.value
+	}
+	return nil
+}
+
+// load loads the asset the manifest declares under key.
+func (c *AssetCache) load(key string) (*assetEntry, error) {
+	if path, ok := c.manifest.Textures[key]; ok {
+		texture := rl.LoadTexture(ResourcePath(path))
+		if texture.ID == 0 {
+			return nil, fmt.Errorf("unable to load texture %s", path)
+		}
+		return &assetEntry{value: texture, unload: func() { rl.UnloadTexture(texture) }}, nil
+	}
+
+	if path, ok := c.manifest.Fonts[key]; ok {
+		font := rl.LoadFont(ResourcePath(path))
+		return &assetEntry{value: font, unload: func() { rl.UnloadFont(font) }}, nil
+	}
+
+	if set, ok := c.manifest.Animations[key]; ok {
+		return loadAnimationSet(set)
+	}
+
+	if path, ok := c.manifest.Tilesets[key]; ok {
+		tileset, err := LoadTilesetJSON(ResourcePath(path))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := c.manifest.Textures[tileset.Texture]; !ok {
+			return nil, fmt.Errorf("tileset %s: unknown texture %q", path, tileset.Texture)
+		}
+
+		// The tileset holds a reference to its texture
+		if err := c.Acquire(tileset.Texture); err != nil {
+			return nil, err
+		}
+		value := newTileset(tileset, c.Texture(tileset.Texture))
+		return &assetEntry{value: value, unload: func() { c.Release(tileset.Texture) }}, nil
+	}
+
+	if region, ok := c.manifest.Regions[key]; ok {
+		// The region holds a reference to its texture
+		if err := c.Acquire(region.Texture); err != nil {
+			return nil, err
+		}
+		value := &Region{
+			Texture: c.Texture(region.Texture),
+			Source:  rl.Rectangle{X: region.X, Y: region.Y, Width: region.Width, Height: region.Height},
+		}
+		return &assetEntry{value: value, unload: func() { c.Release(region.Texture) }}, nil
+	}
+
+	return nil, fmt.Errorf("no asset with key %q", key)
+}
+
+// loadAnimationSet loads every clip of a set. Clips reading the same file
+// share its texture.
+func loadAnimationSet(set AnimationSetJSON) (*assetEntry, error) {
+	sheets := make(map[string]rl.Texture2D)
+	asepriteFiles := make(map[string]map[string]AnimationDataLegacy)
+	unload := func() {
+		for _, texture := range sheets {
+			rl.UnloadTexture(texture)
+		}
+		for _, clips := range asepriteFiles {
+			rl.UnloadTexture(clips[AsepriteAllFrames].Texture)
+		}
+	}
+
+	value := &AnimationSet{Clips: make(map[string]AnimationDataLegacy, len(set.Clips))}
+	for name, clip := range set.Clips {
+		if clip.Aseprite != "" {
+			clips, ok := asepriteFiles[clip.Aseprite]
+			if !ok {
+				loaded, err := loadAsepriteData(ResourcePath(clip.Aseprite))
+				if err != nil {
+					unload()
+					return nil, fmt.Errorf("clip %q: %w", name, err)
+				}
+				clips = loaded
+				asepriteFiles[clip.Aseprite] = clips
+			}
+
+			tag := clip.Tag
+			if tag == "" {
+				tag = AsepriteAllFrames
+			}
+			data, ok := clips[tag]
+			if !ok {
+				unload()
+				return nil, fmt.Errorf("clip %q: %s has no tag %q", name, clip.Aseprite, tag)
+			}
+			value.Clips[name] = data
+			continue
+		}
+
+		texture, ok := sheets[clip.Sheet]
+		if !ok {
+			texture = rl.LoadTexture(ResourcePath(clip.Sheet))
+			if texture.ID == 0 {
+				unload()
+				return nil, fmt.Errorf("clip %q: unable to load sheet %s", name, clip.Sheet)
+			}
+			sheets[clip.Sheet] = texture
+		}
+
+		frameWidth, frameHeight := clip.FrameWidth, clip.FrameHeight
+		if frameWidth == 0 || frameHeight == 0 {
+			frameWidth, frameHeight = set.FrameWidth, set.FrameHeight
+		}
+		data := spriteSheetData(texture, frameWidth, frameHeight, clip.FrameTime)
+		if clip.GifTiming != "" {
+			data = data.withGifTiming(ResourcePath(clip.GifTiming))
+		}
+		value.Clips[name] = data
+	}
+
+	return &assetEntry{value: value, unload: unload}, nil
+}
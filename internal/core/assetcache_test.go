@@ -0,0 +1,59 @@
+package core
+
+import "testing"
+
+// fakeEntry returns a loaded entry that counts its unloads.
+func fakeEntry(unloads *int) *assetEntry {
+	return &assetEntry{value: "loaded", unload: func() { *unloads++ }}
+}
+
+func TestAssetCacheRefCounts(t *testing.T) {
+	cache := NewAssetCache(AssetManifestJSON{})
+	var unloads int
+	entry := fakeEntry(&unloads)
+	entry.refs = 1
+	cache.entries["sprite"] = entry
+
+	if err := cache.Acquire("sprite"); err != nil {
+		t.Fatal(err)
+	}
+	cache.Release("sprite")
+	if unloads != 0 || cache.value("sprite") == nil {
+		t.Fatal("unloaded while a reference was left")
+	}
+	cache.Release("sprite")
+	if unloads != 1 || cache.value("sprite") != nil {
+		t.Fatalf("after the last release: %d unloads, still cached %v", unloads, cache.value("sprite") != nil)
+	}
+
+	// Releasing an asset that is not loaded does nothing
+	cache.Release("sprite")
+	if unloads != 1 {
+		t.Errorf("released twice: %d unloads", unloads)
+	}
+}
+
+func TestAssetCacheUnloadAll(t *testing.T) {
+	cache := NewAssetCache(AssetManifestJSON{})
+	var unloads int
+	for _, key := range []string{"a", "b", "c"} {
+		entry := fakeEntry(&unloads)
+		entry.refs = 2
+		cache.entries[key] = entry
+	}
+
+	cache.UnloadAll()
+	if unloads != 3 || len(cache.entries) != 0 {
+		t.Errorf("%d unloads, %d entries left, want 3 and 0", unloads, len(cache.entries))
+	}
+}
+
+func TestAssetCacheUnknownKey(t *testing.T) {
+	cache := NewAssetCache(AssetManifestJSON{})
+	if err := cache.Acquire("missing"); err == nil {
+		t.Error("acquired a key the manifest does not declare")
+	}
+	if cache.Animations("missing") != nil || cache.Tileset("missing") != nil || cache.Region("missing") != nil {
+		t.Error("lookup of a missing key returned a value")
+	}
+}
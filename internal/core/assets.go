@@ -1,77 +1,259 @@
 package core
 
 import (
+	"encoding/json"
+	"fmt"
+	"image/gif"
+	"log"
+	"os"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-func (g *Game) LoadAssets() {
-	g.FontEmoji = rl.LoadFont(ResourcePath("resources/fonts/dejavu.fnt"))
-	g.Bg = rl.LoadTexture(ResourcePath("resources/background/Background.png"))
-	g.GrassTile = rl.LoadTexture(ResourcePath("resources/assets/tiles/grass.png"))
-	g.HealthHeart = rl.LoadTexture(ResourcePath("resources/assets/heart.png"))
+// Keys of the assets the game itself uses, as declared in the asset manifest.
+const (
+	AssetBackground  = "background"
+	AssetTerrain     = "terrain"
+	AssetHealthHeart = "heart"
+	AssetFont        = "dejavu"
+	AssetHero        = "hero"
+	AssetSnail       = "snail"
+	AssetCoin        = "coin"
+)
+
+// AssetManifestJSON declares every asset by key. Paths are relative to the
+// project root; keys are unique across all kinds.
+type AssetManifestJSON struct {
+	Preload    []string                    `json:"preload"` // Keys acquired by LoadAssets
+	Textures   map[string]string           `json:"textures"`
+	Fonts      map[string]string           `json:"fonts"`
+	Animations map[string]AnimationSetJSON `json:"animations"`
+	Tilesets   map[string]string           `json:"tilesets"` // Tileset file paths (see TilesetJSON)
+	Regions    map[string]RegionJSON       `json:"regions"`
+	Mobs       map[string]MobJSON          `json:"mobs"` // By animation set key
+}
 
-	gifDir := ResourcePath("resources/character/colour2/no_outline/120x80_gifs/")
+// AnimationSetJSON is a character's animation clips, by clip name (see
+// components.ParseAnimationState). The frame size applies to every sheet
+// clip that does not set its own.
+type AnimationSetJSON struct {
+	FrameWidth  int32               `json:"frameWidth,omitempty"`
+	FrameHeight int32               `json:"frameHeight,omitempty"`
+	Clips       map[string]ClipJSON `json:"clips"`
+}
 
-	// Load mob sprite sheet
-	g.MobAnimation = loadSpriteSheetData(ResourcePath("resources/mob/Snail/walk-Sheet.png"), 8, 8, 48*32)
+// ClipJSON is one animation clip: either a PNG sprite sheet of equally
+// sized frames or a tag of an Aseprite file.
+type ClipJSON struct {
+	Sheet       string  `json:"sheet,omitempty"`
+	FrameWidth  int32   `json:"frameWidth,omitempty"`
+	FrameHeight int32   `json:"frameHeight,omitempty"`
+	FrameTime   float32 `json:"frameTime,omitempty"` // Seconds per frame
+	GifTiming   string  `json:"gifTiming,omitempty"` // GIF whose frame delays replace FrameTime
 
-	// Load hero animations
-	g.HeroAnimations[int(IdleLegacy)] = loadAnimatedGifData(gifDir+"/__Idle.gif", 8)
-	g.HeroAnimations[int(RunningLegacy)] = loadAnimatedGifData(gifDir+"/__Run.gif", 6)
-	g.HeroAnimations[int(JumpingLegacy)] = loadAnimatedGifData(gifDir+"/__Jump.gif", 6)
-	g.HeroAnimations[int(FallingLegacy)] = loadAnimatedGifData(gifDir+"/__Fall.gif", 6)
+	Aseprite string `json:"aseprite,omitempty"`
+	Tag      string `json:"tag,omitempty"` // Aseprite tag; AsepriteAllFrames if empty
 }
 
-func (g *Game) UnloadAssets() {
-	rl.UnloadFont(g.FontEmoji)
-	rl.UnloadTexture(g.Bg)
-	rl.UnloadTexture(g.GrassTile)
-	rl.UnloadTexture(g.HealthHeart)
+// MobJSON is the body and speed of a mob, declared under the key of the
+// animation set it is drawn with.
+type MobJSON struct {
+	ColliderWidth  float32 `json:"colliderWidth"`
+	ColliderHeight float32 `json:"colliderHeight"`
+	MoveSpeed      float32 `json:"moveSpeed"`
+}
+
+// RegionJSON is a named rectangle of a texture (by key), such as one piece
+// of a scenery sheet.
+type RegionJSON struct {
+	Texture string  `json:"texture"`
+	X       float32 `json:"x"`
+	Y       float32 `json:"y"`
+	Width   float32 `json:"width"`
+	Height  float32 `json:"height"`
+}
+
+// LoadAssetManifest loads and validates an asset manifest from a JSON file.
+func LoadAssetManifest(path string) (AssetManifestJSON, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AssetManifestJSON{}, err
+	}
+
+	var manifest AssetManifestJSON
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return AssetManifestJSON{}, err
+	}
+
+	if err := manifest.validate(); err != nil {
+		return AssetManifestJSON{}, err
+	}
+	return manifest, nil
+}
 
-	// Unload hero animations
-	for _, animData := range g.HeroAnimations {
-		rl.UnloadTexture(animData.Texture)
-		if animData.Image != nil {
-			rl.UnloadImage(animData.Image)
+// validate checks that keys are unique and references resolve.
+func (m AssetManifestJSON) validate() error {
+	kinds := make(map[string]string)
+	add := func(kind, key string) error {
+		if other, exists := kinds[key]; exists {
+			return fmt.Errorf("key %q declared as both %s and %s", key, other, kind)
+		}
+		kinds[key] = kind
+		return nil
+	}
+	for key := range m.Textures {
+		if err := add("texture", key); err != nil {
+			return err
+		}
+	}
+	for key := range m.Fonts {
+		if err := add("font", key); err != nil {
+			return err
+		}
+	}
+	for key, set := range m.Animations {
+		if err := add("animation set", key); err != nil {
+			return err
+		}
+		for name, clip := range set.Clips {
+			if (clip.Sheet == "") == (clip.Aseprite == "") {
+				return fmt.Errorf("animation set %q clip %q: needs exactly one of sheet or aseprite", key, name)
+			}
+			if clip.Sheet != "" && clip.FrameTime <= 0 {
+				return fmt.Errorf("animation set %q clip %q: frameTime must be positive", key, name)
+			}
+		}
+	}
+	for key := range m.Tilesets {
+		if err := add("tileset", key); err != nil {
+			return err
+		}
+	}
+	for key, region := range m.Regions {
+		if err := add("region", key); err != nil {
+			return err
+		}
+		if _, ok := m.Textures[region.Texture]; !ok {
+			return fmt.Errorf("region %q: unknown texture %q", key, region.Texture)
+		}
+		if region.Width <= 0 || region.Height <= 0 {
+			return fmt.Errorf("region %q: width and height must be positive", key)
 		}
 	}
+	for key, mob := range m.Mobs {
+		if _, ok := m.Animations[key]; !ok {
+			return fmt.Errorf("mob %q: unknown animation set", key)
+		}
+		if mob.ColliderWidth <= 0 || mob.ColliderHeight <= 0 {
+			return fmt.Errorf("mob %q: collider width and height must be positive", key)
+		}
+		if mob.MoveSpeed < 0 {
+			return fmt.Errorf("mob %q: moveSpeed must not be negative", key)
+		}
+	}
+	for _, key := range m.Preload {
+		if _, ok := kinds[key]; !ok {
+			return fmt.Errorf("preload: unknown key %q", key)
+		}
+	}
+	return nil
+}
 
-	// Unload mob animation
-	rl.UnloadTexture(g.MobAnimation.Texture)
-	if g.MobAnimation.Image != nil {
-		rl.UnloadImage(g.MobAnimation.Image)
+// LoadAssets loads the asset manifest into a new asset cache and acquires
+// the assets it lists for preloading. Failures are logged; an asset that
+// failed to load is looked up as a zero value.
+func (g *Game) LoadAssets() {
+	manifestPath := ResourcePath(DefaultAssetManifestPath)
+	manifest, err := LoadAssetManifest(manifestPath)
+	if err != nil {
+		log.Printf("Unable to load asset manifest %s: %v", manifestPath, err)
+	}
+
+	g.Assets = NewAssetCache(manifest)
+	for _, key := range manifest.Preload {
+		if err := g.Assets.Acquire(key); err != nil {
+			log.Printf("Unable to load asset %q: %v", key, err)
+		}
 	}
 }
 
-// loadAnimatedGifData loads a GIF animation and returns the data.
-func loadAnimatedGifData(imagePath string, frameDelay int32) AnimationDataLegacy {
-	var frames int32 = 0
-	image := rl.LoadImageAnim(imagePath, &frames)
-	texture := rl.LoadTextureFromImage(image)
+// UnloadAssets unloads every asset still held by the cache.
+func (g *Game) UnloadAssets() {
+	g.Assets.UnloadAll()
+}
+
+// spriteSheetData returns the animation data of a sprite sheet of equally
+// sized frames, read left to right and top to bottom. Every frame is shown
+// for frameTime seconds.
+func spriteSheetData(texture rl.Texture2D, frameWidth, frameHeight int32, frameTime float32) AnimationDataLegacy {
+	frames := gridFrames(texture, frameWidth, frameHeight)
 	return AnimationDataLegacy{
-		Image:         image,
-		Texture:       texture,
-		FrameCount:    frames,
-		CurrentFrame:  0,
-		FrameDelay:    frameDelay,
-		FrameCounter:  0,
-		FrameSize:     image.Width * image.Height,
-		IsSpriteSheet: false,
+		Texture:        texture,
+		Frames:         frames,
+		FrameCount:     int32(len(frames)),
+		CurrentFrame:   0,
+		FrameDurations: []float32{frameTime},
 	}
 }
 
-// loadSpriteSheetData loads a sprite sheet and returns the data.
-func loadSpriteSheetData(imagePath string, frameCount int32, frameDelay int32, frameSize int32) AnimationDataLegacy {
-	image := rl.LoadImage(imagePath)
-	texture := rl.LoadTextureFromImage(image)
-	return AnimationDataLegacy{
-		Image:         image,
-		Texture:       texture,
-		FrameCount:    frameCount,
-		CurrentFrame:  0,
-		FrameDelay:    frameDelay,
-		FrameCounter:  0,
-		FrameSize:     frameSize,
-		IsSpriteSheet: true,
+// gridFrames returns the source rectangles of a texture cut into a grid of
+// frameWidth x frameHeight cells. A texture smaller than one cell is a
+// single frame.
+func gridFrames(texture rl.Texture2D, frameWidth, frameHeight int32) []rl.Rectangle {
+	if frameWidth <= 0 || frameHeight <= 0 || texture.Width < frameWidth || texture.Height < frameHeight {
+		return []rl.Rectangle{{Width: float32(texture.Width), Height: float32(texture.Height)}}
+	}
+
+	columns := texture.Width / frameWidth
+	rows := texture.Height / frameHeight
+	frames := make([]rl.Rectangle, 0, columns*rows)
+	for row := int32(0); row < rows; row++ {
+		for column := int32(0); column < columns; column++ {
+			frames = append(frames, rl.Rectangle{
+				X:      float32(column * frameWidth),
+				Y:      float32(row * frameHeight),
+				Width:  float32(frameWidth),
+				Height: float32(frameHeight),
+			})
+		}
+	}
+	return frames
+}
+
+// withGifTiming returns the animation with per-frame durations read from the
+// GIF export of the same clip. The sheet's frame time is the fallback.
+func (a AnimationDataLegacy) withGifTiming(gifPath string) AnimationDataLegacy {
+	fallback := float32(0.1)
+	if len(a.FrameDurations) > 0 {
+		fallback = a.FrameDurations[0]
+	}
+	a.FrameDurations = gifFrameDurations(gifPath, a.FrameCount, fallback)
+	return a
+}
+
+// gifFrameDurations reads the per-frame delays of a GIF in seconds. Frames
+// without a delay, or all frames if the file cannot be decoded, use fallback.
+func gifFrameDurations(imagePath string, frames int32, fallback float32) []float32 {
+	durations := make([]float32, frames)
+	for i := range durations {
+		durations[i] = fallback
+	}
+
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return durations
+	}
+	defer file.Close()
+
+	decoded, err := gif.DecodeAll(file)
+	if err != nil {
+		log.Printf("Unable to read frame delays of %s: %v", imagePath, err)
+		return durations
+	}
+	for i, delay := range decoded.Delay {
+		if i < len(durations) && delay > 0 {
+			durations[i] = float32(delay) / 100 // GIF delays are in 1/100 s
+		}
 	}
+	return durations
 }
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestGridFrames(t *testing.T) {
+	tests := []struct {
+		name      string
+		texture   rl.Texture2D
+		cellW     int32
+		cellH     int32
+		wantCount int
+		wantLast  rl.Rectangle
+	}{
+		{"one row", rl.Texture2D{Width: 1200, Height: 80}, 120, 80, 10, rl.Rectangle{X: 1080, Width: 120, Height: 80}},
+		{"rows left to right, top to bottom", rl.Texture2D{Width: 240, Height: 160}, 120, 80, 4, rl.Rectangle{X: 120, Y: 80, Width: 120, Height: 80}},
+		{"partial cells are dropped", rl.Texture2D{Width: 300, Height: 80}, 120, 80, 2, rl.Rectangle{X: 120, Width: 120, Height: 80}},
+		{"smaller than a cell is one frame", rl.Texture2D{Width: 64, Height: 32}, 120, 80, 1, rl.Rectangle{Width: 64, Height: 32}},
+		{"no cell size is one frame", rl.Texture2D{Width: 64, Height: 32}, 0, 0, 1, rl.Rectangle{Width: 64, Height: 32}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := gridFrames(tt.texture, tt.cellW, tt.cellH)
+			if len(frames) != tt.wantCount {
+				t.Fatalf("%d frames, want %d", len(frames), tt.wantCount)
+			}
+			if last := frames[len(frames)-1]; last != tt.wantLast {
+				t.Errorf("last frame %v, want %v", last, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestGifFrameDurations(t *testing.T) {
+	gifPath := ResourcePath("resources/character/colour2/no_outline/120x80_gifs/__Run.gif")
+	durations := gifFrameDurations(gifPath, 10, 0.5)
+	if len(durations) != 10 {
+		t.Fatalf("%d durations, want 10", len(durations))
+	}
+	for i, duration := range durations {
+		if duration <= 0 || duration == 0.5 {
+			t.Errorf("frame %d: duration %v, want the GIF's delay", i, duration)
+		}
+	}
+
+	missing := gifFrameDurations(filepath.Join(t.TempDir(), "missing.gif"), 3, 0.25)
+	for i, duration := range missing {
+		if duration != 0.25 {
+			t.Errorf("missing file frame %d: duration %v, want the fallback", i, duration)
+		}
+	}
+}
+
+func TestShippedAssetManifestIsValid(t *testing.T) {
+	manifest, err := LoadAssetManifest(ResourcePath(DefaultAssetManifestPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{AssetSnail, "boar", "bee"} {
+		if _, ok := manifest.Mobs[key]; !ok {
+			t.Errorf("manifest has no mob data for %q", key)
+		}
+	}
+}
+
+func TestAssetManifestValidate(t *testing.T) {
+	valid := func() AssetManifestJSON {
+		return AssetManifestJSON{
+			Preload:  []string{"tiles", "snail"},
+			Textures: map[string]string{"tiles": "tiles.png"},
+			Animations: map[string]AnimationSetJSON{
+				"snail": {Clips: map[string]ClipJSON{"run": {Sheet: "walk.png", FrameTime: 0.1}}},
+			},
+			Regions: map[string]RegionJSON{"tree": {Texture: "tiles", Width: 16, Height: 16}},
+			Mobs:    map[string]MobJSON{"snail": {ColliderWidth: 48, ColliderHeight: 32, MoveSpeed: 60}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(*AssetManifestJSON)
+	}{
+		{"duplicate key", func(m *AssetManifestJSON) { m.Fonts = map[string]string{"tiles": "font.fnt"} }},
+		{"clip with two sources", func(m *AssetManifestJSON) {
+			m.Animations["snail"].Clips["run"] = ClipJSON{Sheet: "walk.png", Aseprite: "walk.aseprite", FrameTime: 0.1}
+		}},
+		{"clip without a source", func(m *AssetManifestJSON) { m.Animations["snail"].Clips["run"] = ClipJSON{} }},
+		{"sheet without a frame time", func(m *AssetManifestJSON) { m.Animations["snail"].Clips["run"] = ClipJSON{Sheet: "walk.png"} }},
+		{"region of an unknown texture", func(m *AssetManifestJSON) { m.Regions["tree"] = RegionJSON{Texture: "trees", Width: 16, Height: 16} }},
+		{"empty region", func(m *AssetManifestJSON) { m.Regions["tree"] = RegionJSON{Texture: "tiles"} }},
+		{"mob without animations", func(m *AssetManifestJSON) { m.Mobs["bee"] = MobJSON{ColliderWidth: 16, ColliderHeight: 16} }},
+		{"mob without a collider", func(m *AssetManifestJSON) { m.Mobs["snail"] = MobJSON{MoveSpeed: 60} }},
+		{"mob with a negative speed", func(m *AssetManifestJSON) {
+			m.Mobs["snail"] = MobJSON{ColliderWidth: 48, ColliderHeight: 32, MoveSpeed: -1}
+		}},
+		{"unknown preload key", func(m *AssetManifestJSON) { m.Preload = append(m.Preload, "missing") }},
+	}
+
+	if err := valid().validate(); err != nil {
+		t.Fatalf("valid manifest: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := valid()
+			tt.corrupt(&manifest)
+			if err := manifest.validate(); err == nil {
+				t.Error("validated")
+			}
+		})
+	}
+}
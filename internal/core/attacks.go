@@ -0,0 +1,110 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"fire/internal/components"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// HitboxJSON represents an attack hitbox in the JSON format. The rectangle is
+// in unscaled sprite-frame pixels for a character facing right.
+type HitboxJSON struct {
+	Frames [2]int32 `json:"frames"` // First and last active animation frame (inclusive)
+	X      float32  `json:"x"`
+	Y      float32  `json:"y"`
+	Width  float32  `json:"width"`
+	Height float32  `json:"height"`
+}
+
+// AttackJSON represents a melee attack in the JSON format.
+type AttackJSON struct {
+	Name        string       `json:"name"`
+	Animation   string       `json:"animation"` // Clip name, see components.ParseAnimationState
+	Damage      int          `json:"damage"`
+	Hitboxes    []HitboxJSON `json:"hitboxes"`
+	Knockback   PointJSON    `json:"knockback"`             // Units per second; negative Y is up
+	HitStop     float32      `json:"hitStop,omitempty"`     // Seconds
+	ComboWindow float32      `json:"comboWindow,omitempty"` // Seconds
+}
+
+// AttackSetJSON is the melee move set of a character: the combo chain in
+// order and an optional crouching attack.
+type AttackSetJSON struct {
+	Combo  []AttackJSON `json:"combo"`
+	Crouch *AttackJSON  `json:"crouch,omitempty"`
+}
+
+// LoadAttackSet loads a melee move set from a JSON file.
+func LoadAttackSet(path string) (AttackSetJSON, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AttackSetJSON{}, err
+	}
+
+	var set AttackSetJSON
+	if err := json.Unmarshal(data, &set); err != nil {
+		return AttackSetJSON{}, err
+	}
+
+	return set, nil
+}
+
+// ToMeleeComponent converts the move set into a MeleeComponent that hits
+// entities tagged targetTag.
+func (s AttackSetJSON) ToMeleeComponent(targetTag string) (*components.MeleeComponent, error) {
+	melee := &components.MeleeComponent{
+		Combo:        make([]components.AttackDefinition, 0, len(s.Combo)),
+		TargetTag:    targetTag,
+		ComboIndex:   -1,
+		ActiveHitbox: -1,
+	}
+
+	for _, attack := range s.Combo {
+		definition, err := attack.toDefinition()
+		if err != nil {
+			return nil, err
+		}
+		melee.Combo = append(melee.Combo, definition)
+	}
+
+	if s.Crouch != nil {
+		definition, err := s.Crouch.toDefinition()
+		if err != nil {
+			return nil, err
+		}
+		melee.CrouchAttack = &definition
+	}
+
+	return melee, nil
+}
+
+// toDefinition converts a JSON attack into a components.AttackDefinition.
+func (a AttackJSON) toDefinition() (components.AttackDefinition, error) {
+	animation, ok := components.ParseAnimationState(a.Animation)
+	if !ok {
+		return components.AttackDefinition{}, fmt.Errorf("attack %q: unknown animation %q", a.Name, a.Animation)
+	}
+
+	hitboxes := make([]components.AttackHitbox, 0, len(a.Hitboxes))
+	for _, hitbox := range a.Hitboxes {
+		hitboxes = append(hitboxes, components.AttackHitbox{
+			StartFrame: hitbox.Frames[0],
+			EndFrame:   hitbox.Frames[1],
+			Rect:       rl.Rectangle{X: hitbox.X, Y: hitbox.Y, Width: hitbox.Width, Height: hitbox.Height},
+		})
+	}
+
+	return components.AttackDefinition{
+		Name:        a.Name,
+		Animation:   animation,
+		Damage:      a.Damage,
+		Hitboxes:    hitboxes,
+		Knockback:   rl.Vector2{X: a.Knockback.X, Y: a.Knockback.Y},
+		HitStop:     a.HitStop,
+		ComboWindow: a.ComboWindow,
+	}, nil
+}
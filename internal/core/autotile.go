@@ -0,0 +1,218 @@
+package core
+
+import (
+	"fmt"
+	"math"
+
+	"fire/internal/components"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Neighbour bits of an autotile mask, clockwise from north. A bit is set
+// when the neighbouring cell holds the same tile type.
+const (
+	NeighbourN uint8 = 1 << iota
+	NeighbourNE
+	NeighbourE
+	NeighbourSE
+	NeighbourS
+	NeighbourSW
+	NeighbourW
+	NeighbourNW
+)
+
+// neighbourEdges are the edge bits of a mask.
+const neighbourEdges = NeighbourN | NeighbourE | NeighbourS | NeighbourW
+
+// neighbourNames maps tileset neighbour names to mask bits.
+var neighbourNames = map[string]uint8{
+	"n":  NeighbourN,
+	"ne": NeighbourNE,
+	"e":  NeighbourE,
+	"se": NeighbourSE,
+	"s":  NeighbourS,
+	"sw": NeighbourSW,
+	"w":  NeighbourW,
+	"nw": NeighbourNW,
+}
+
+// neighbourOffsets are the grid offsets of each mask bit, in bit order.
+var neighbourOffsets = [8]gridCell{
+	{X: 0, Y: -1},
+	{X: 1, Y: -1},
+	{X: 1, Y: 0},
+	{X: 1, Y: 1},
+	{X: 0, Y: 1},
+	{X: -1, Y: 1},
+	{X: -1, Y: 0},
+	{X: -1, Y: -1},
+}
+
+// AutotileMode is which neighbours pick an autotile piece.
+type AutotileMode int
+
+const (
+	AutotileEdges AutotileMode = iota // 4-bit: the four edge neighbours
+	AutotileBlob                      // 8-bit: edges and corners (47 distinct pieces)
+)
+
+// autotileModes maps the tileset "mode" names to autotile modes.
+var autotileModes = map[string]AutotileMode{
+	"4bit": AutotileEdges,
+	"8bit": AutotileBlob,
+}
+
+// Autotile holds a tile's atlas pieces by reduced neighbour mask.
+type Autotile struct {
+	Mode   AutotileMode
+	Pieces map[uint8]rl.Rectangle
+}
+
+// reduce keeps the bits of a mask the mode looks at. Blob tiles only look at
+// a corner when both edges next to it are set, since otherwise the edge
+// pieces already hide it.
+func (a *Autotile) reduce(mask uint8) uint8 {
+	edges := mask & neighbourEdges
+	if a.Mode == AutotileEdges {
+		return edges
+	}
+	corners := uint8(0)
+	for _, corner := range [4]struct{ bit, sides uint8 }{
+		{NeighbourNE, NeighbourN | NeighbourE},
+		{NeighbourSE, NeighbourS | NeighbourE},
+		{NeighbourSW, NeighbourS | NeighbourW},
+		{NeighbourNW, NeighbourN | NeighbourW},
+	} {
+		if mask&corner.bit != 0 && edges&corner.sides == corner.sides {
+			corners |= corner.bit
+		}
+	}
+	return edges | corners
+}
+
+// Source returns the piece for a neighbour mask. Blob tiles without a piece
+// for the exact mask fall back to the piece for its edges alone.
+func (a *Autotile) Source(mask uint8) (rl.Rectangle, bool) {
+	mask = a.reduce(mask)
+	if source, ok := a.Pieces[mask]; ok {
+		return source, true
+	}
+	source, ok := a.Pieces[mask&neighbourEdges]
+	return source, ok
+}
+
+// parseNeighbours converts a list of neighbour names to a mask valid for
+// the mode.
+func parseNeighbours(names []string, mode AutotileMode) (uint8, error) {
+	mask := uint8(0)
+	for _, name := range names {
+		bit, ok := neighbourNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown neighbour %q", name)
+		}
+		mask |= bit
+	}
+	if (&Autotile{Mode: mode}).reduce(mask) != mask {
+		return 0, fmt.Errorf("neighbours %v include corners the mode ignores", names)
+	}
+	return mask, nil
+}
+
+// neighbourList converts a mask to neighbour names, clockwise from north.
+func neighbourList(mask uint8) []string {
+	names := make([]string, 0, 8)
+	for _, name := range []string{"n", "ne", "e", "se", "s", "sw", "w", "nw"} {
+		if mask&neighbourNames[name] != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// gridCell is a map tile position in tiles.
+type gridCell struct {
+	X, Y int
+}
+
+// AutotileGrid indexes map tiles by grid cell and keeps each tile's
+// neighbour mask up to date. Adding or removing a tile only recomputes the
+// masks around it.
+type AutotileGrid struct {
+	tileWidth  float32
+	tileHeight float32
+	types      map[gridCell]components.TileType
+	masks      map[gridCell]uint8
+}
+
+// NewAutotileGrid builds the grid for a map's tiles.
+func NewAutotileGrid(tiles []TileJSON, tileWidth, tileHeight float32) *AutotileGrid {
+	g := &AutotileGrid{
+		tileWidth:  tileWidth,
+		tileHeight: tileHeight,
+		types:      make(map[gridCell]components.TileType, len(tiles)),
+		masks:      make(map[gridCell]uint8, len(tiles)),
+	}
+	for _, tile := range tiles {
+		g.types[g.cellAt(tile.X, tile.Y)] = components.TileType(tile.TileType)
+	}
+	for cell := range g.types {
+		g.masks[cell] = g.computeMask(cell)
+	}
+	return g
+}
+
+// Set places or replaces a tile.
+func (g *AutotileGrid) Set(tile TileJSON) {
+	cell := g.cellAt(tile.X, tile.Y)
+	g.types[cell] = components.TileType(tile.TileType)
+	g.refresh(cell)
+}
+
+// Remove clears the tile at a map position.
+func (g *AutotileGrid) Remove(x, y float32) {
+	cell := g.cellAt(x, y)
+	delete(g.types, cell)
+	delete(g.masks, cell)
+	g.refresh(cell)
+}
+
+// Mask returns the neighbour mask of the tile at a map position.
+func (g *AutotileGrid) Mask(x, y float32) uint8 {
+	return g.masks[g.cellAt(x, y)]
+}
+
+// cellAt returns the grid cell of a map position.
+func (g *AutotileGrid) cellAt(x, y float32) gridCell {
+	return gridCell{
+		X: int(math.Round(float64(x / g.tileWidth))),
+		Y: int(math.Round(float64(y / g.tileHeight))),
+	}
+}
+
+// refresh recomputes the masks of a cell and its neighbours.
+func (g *AutotileGrid) refresh(cell gridCell) {
+	if _, ok := g.types[cell]; ok {
+		g.masks[cell] = g.computeMask(cell)
+	}
+	for _, offset := range neighbourOffsets {
+		neighbour := gridCell{X: cell.X + offset.X, Y: cell.Y + offset.Y}
+		if _, ok := g.types[neighbour]; ok {
+			g.masks[neighbour] = g.computeMask(neighbour)
+		}
+	}
+}
+
+// computeMask returns the bits of the neighbours holding the cell's tile
+// type.
+func (g *AutotileGrid) computeMask(cell gridCell) uint8 {
+	tileType := g.types[cell]
+	mask := uint8(0)
+	for bit, offset := range neighbourOffsets {
+		neighbour, ok := g.types[gridCell{X: cell.X + offset.X, Y: cell.Y + offset.Y}]
+		if ok && neighbour == tileType {
+			mask |= 1 << bit
+		}
+	}
+	return mask
+}
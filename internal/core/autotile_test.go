@@ -0,0 +1,156 @@
+package core
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestParseNeighbours(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		mode    AutotileMode
+		want    uint8
+		wantErr bool
+	}{
+		{"none", nil, AutotileEdges, 0, false},
+		{"edges", []string{"n", "w"}, AutotileEdges, NeighbourN | NeighbourW, false},
+		{"corner in 4-bit", []string{"n", "ne", "e"}, AutotileEdges, 0, true},
+		{"corner between its edges", []string{"n", "ne", "e"}, AutotileBlob, NeighbourN | NeighbourNE | NeighbourE, false},
+		{"corner without both edges", []string{"n", "ne"}, AutotileBlob, 0, true},
+		{"unknown name", []string{"up"}, AutotileEdges, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNeighbours(tt.names, tt.mode)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseNeighbours(%v) = %08b, %v; want %08b, error %v", tt.names, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAutotileReduce(t *testing.T) {
+	const all = 0xFF
+	tests := []struct {
+		name string
+		mode AutotileMode
+		mask uint8
+		want uint8
+	}{
+		{"4-bit drops corners", AutotileEdges, all, neighbourEdges},
+		{"blob keeps everything when surrounded", AutotileBlob, all, all},
+		{"blob drops a corner missing an edge", AutotileBlob, NeighbourN | NeighbourNE, NeighbourN},
+		{"blob keeps a corner between its edges", AutotileBlob, NeighbourS | NeighbourSW | NeighbourW, NeighbourS | NeighbourSW | NeighbourW},
+		{"blob drops lone corners", AutotileBlob, NeighbourNE | NeighbourSE | NeighbourSW | NeighbourNW, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&Autotile{Mode: tt.mode}).reduce(tt.mask); got != tt.want {
+				t.Errorf("reduce(%08b) = %08b, want %08b", tt.mask, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutotileSourceFallsBackToEdges(t *testing.T) {
+	edgesOnly := rl.Rectangle{X: 1}
+	surrounded := rl.Rectangle{X: 2}
+	autotile := &Autotile{Mode: AutotileBlob, Pieces: map[uint8]rl.Rectangle{
+		neighbourEdges: edgesOnly,
+		0xFF:           surrounded,
+	}}
+
+	if got, _ := autotile.Source(0xFF); got != surrounded {
+		t.Errorf("surrounded drew %v, want %v", got, surrounded)
+	}
+	// All edges but one corner has no piece of its own
+	if got, ok := autotile.Source(0xFF &^ NeighbourNE); !ok || got != edgesOnly {
+		t.Errorf("missing corner drew %v (found %v), want the edge piece %v", got, ok, edgesOnly)
+	}
+	if _, ok := autotile.Source(NeighbourN); ok {
+		t.Error("found a piece for a mask without one")
+	}
+}
+
+func TestAutotileGridSetRemove(t *testing.T) {
+	// A 2x2 block of grass with a stone tile to its right
+	grid := NewAutotileGrid([]TileJSON{
+		{X: 0, Y: 0}, {X: 32, Y: 0},
+		{X: 0, Y: 32}, {X: 32, Y: 32},
+		{X: 64, Y: 0, TileType: 1},
+	}, 32, 32)
+
+	if got, want := grid.Mask(0, 0), NeighbourE|NeighbourSE|NeighbourS; got != want {
+		t.Errorf("top left mask %08b, want %08b", got, want)
+	}
+	if got := grid.Mask(64, 0); got != 0 {
+		t.Errorf("stone mask %08b, want 0 (no stone neighbours)", got)
+	}
+
+	// Removing a tile updates every neighbour that saw it
+	grid.Remove(32, 32)
+	if got, want := grid.Mask(0, 0), NeighbourE|NeighbourS; got != want {
+		t.Errorf("top left after remove %08b, want %08b", got, want)
+	}
+	if got, want := grid.Mask(32, 0), NeighbourW|NeighbourSW; got != want {
+		t.Errorf("top right after remove %08b, want %08b", got, want)
+	}
+	if got := grid.Mask(32, 32); got != 0 {
+		t.Errorf("removed tile still has mask %08b", got)
+	}
+
+	// Setting a tile of another type changes its neighbours' masks too
+	grid.Set(TileJSON{X: 32, Y: 0, TileType: 1})
+	if got, want := grid.Mask(64, 0), NeighbourW; got != want {
+		t.Errorf("stone after set %08b, want %08b", got, want)
+	}
+	if got, want := grid.Mask(0, 0), NeighbourS; got != want {
+		t.Errorf("top left after set %08b, want %08b", got, want)
+	}
+
+	// Positions off the grid snap to the nearest cell, negative ones too
+	grid.Set(TileJSON{X: -30, Y: 2})
+	if got, want := grid.Mask(-32, 0), NeighbourE|NeighbourSE; got != want {
+		t.Errorf("snapped tile mask %08b, want %08b", got, want)
+	}
+}
+
+func TestValidateAutotile(t *testing.T) {
+	complete := func() AutotileJSON {
+		autotile := AutotileJSON{Mode: "4bit"}
+		for mask := 0; mask < 16; mask++ {
+			var names []string
+			for bit, name := range []string{"n", "e", "s", "w"} {
+				if mask&(1<<bit) != 0 {
+					names = append(names, name)
+				}
+			}
+			autotile.Pieces = append(autotile.Pieces, AutotilePieceJSON{Neighbours: names})
+		}
+		return autotile
+	}
+	if err := validateAutotile(complete()); err != nil {
+		t.Fatalf("complete set: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(*AutotileJSON)
+	}{
+		{"unknown mode", func(a *AutotileJSON) { a.Mode = "6bit" }},
+		{"missing the isolated piece", func(a *AutotileJSON) { a.Pieces = a.Pieces[1:] }},
+		{"duplicate piece", func(a *AutotileJSON) { a.Pieces = append(a.Pieces, a.Pieces[3]) }},
+		{"corner in 4-bit", func(a *AutotileJSON) { a.Pieces[15].Neighbours = append(a.Pieces[15].Neighbours, "ne") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			autotile := complete()
+			tt.corrupt(&autotile)
+			if err := validateAutotile(autotile); err == nil {
+				t.Error("validated")
+			}
+		})
+	}
+}
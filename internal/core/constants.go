@@ -1,20 +1,127 @@
 package core
 
-// Player spawn and physics
+// Player spawn and physics (speeds in world units per second)
 const (
-	PlayerStartX     = 100
-	PlayerStartY     = 300
-	PlayerJumpForce  = 12.0
-	PlayerMoveSpeed  = 4.0
-	PlayerHealthMax  = 5
+	PlayerStartX    = 100
+	PlayerStartY    = 300
+	PlayerJumpForce = 600.0
+	PlayerMoveSpeed = 240.0
+	PlayerHealthMax = 5
+
+	PlayerHazardCooldown = 1.0 // Seconds between hurts from damaging tiles
+)
+
+// Player jump feel (times in seconds)
+const (
+	PlayerJumpCutMultiplier = 0.45
+	PlayerCoyoteTime        = 0.1
+	PlayerJumpBufferTime    = 0.12
+	PlayerMaxFallSpeed      = 900.0
+	PlayerDropThroughTime   = 0.2
+)
+
+// Player horizontal movement (rates in world units per second squared)
+const (
+	PlayerGroundAcceleration = 2400.0
+	PlayerGroundDeceleration = 3000.0
+	PlayerTurnAroundBraking  = 4200.0
+	PlayerAirControl         = 0.6
+	PlayerTurnAroundSpeed    = 150.0
+)
+
+// Player wall abilities (speeds in world units per second)
+const (
+	PlayerWallSlideSpeed   = 120.0
+	PlayerWallJumpForceX   = 300.0
+	PlayerWallJumpForceY   = 560.0
+	PlayerWallJumpLockTime = 0.15
 )
 
-// Mob dimensions and behavior
+// Hero sprite frame size in pixels, used for the collider when the idle clip
+// is missing (frame sizes of the clips themselves are in the asset manifest)
+const (
+	HeroFrameWidth  = 120
+	HeroFrameHeight = 80
+)
+
+// Map tile size in world units when the tileset is missing
+const (
+	DefaultTileWidth  = 78
+	DefaultTileHeight = 70
+)
+
+// Player draw order within the entity layer
+const PlayerRenderOrder = 1.0
+
+// Player crouch (height is a fraction of the standing collider)
+const (
+	PlayerCrouchHeightRatio     = 0.6
+	PlayerCrouchSpeedMultiplier = 0.4
+	PlayerCrouchTransitionTime  = 0.08
+)
+
+// Player dash, roll and slide (times in seconds; heights are a fraction of
+// the standing collider)
+const (
+	PlayerDashDistance     = 160.0
+	PlayerDashDuration     = 0.15
+	PlayerDashCooldown     = 0.6
+	PlayerRollSpeed        = 360.0
+	PlayerRollDuration     = 0.35
+	PlayerRollCooldown     = 0.5
+	PlayerRollHeightRatio  = 0.5
+	PlayerSlideSpeed       = 420.0
+	PlayerSlideDuration    = 0.45
+	PlayerSlideCooldown    = 0.5
+	PlayerSlideHeightRatio = 0.4
+)
+
+// Mob behavior shared by every kind; each kind's size and speed are in the
+// asset manifest (see MobJSON)
 const (
-	MobColliderWidth   = 48
-	MobColliderHeight = 32
-	MobMoveSpeed      = 1.0
 	MobPatrolDistance = 100
+	MobSightRange     = 200
+	MobMaxFallSpeed   = 900.0
+	MobHealthMax      = 3
+	MobDeceleration   = 1200.0 // Ground friction on knockback
+	MobHazardCooldown = 1.0    // Seconds between hurts from damaging tiles
+)
+
+// Follow camera (distances in world units, rates in 1/s)
+const (
+	CameraDeadzoneWidth  = 120.0
+	CameraDeadzoneHeight = 90.0
+	CameraSmoothing      = 6.0
+	CameraLookAhead      = 80.0
+	CameraLookAheadSpeed = 2.0
+	CameraZoneBlendTime  = 0.6 // Seconds to ease into and out of a zone's zoom
+)
+
+// Camera shake (trauma is 0..1; shake strength is trauma squared)
+const (
+	CameraTraumaDecay    = 1.5
+	CameraShakeOffset    = 14.0 // World units at full trauma
+	CameraShakeAngle     = 2.0  // Degrees at full trauma
+	CameraShakeFrequency = 25.0
+	CameraDamageTrauma   = 0.35
+	CameraLandTrauma     = 0.4
+	CameraLandMinSpeed   = 650.0 // Landing speeds (units per second)
+	CameraLandFullSpeed  = 900.0
+)
+
+// HUD (sizes in layout units, scaled from HUDReferenceHeight to the screen)
+const (
+	HUDReferenceHeight = 720.0
+	HUDHealthPerHeart  = 2   // Odd health shows a half heart
+	HUDDamageFlashTime = 0.5 // Seconds the HUD flashes after the player is hurt
+	HUDShowTimer       = true
+)
+
+// Score
+const (
+	ScorePerKill = 100
+	ScorePerCoin = 10
+	CoinScale    = 1.5 // Coin image scale (the collider matches it)
 )
 
 // Designer UI
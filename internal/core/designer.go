@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"fire/internal/components"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -19,13 +21,27 @@ type Designer struct {
 	SaveButton    Button
 	BackButton    Button
 	Map           LevelMap
-	GrassTile     rl.Texture2D
+	Tileset       *Tileset
+	Autotile      *AutotileGrid         // Neighbours of the map's tiles, for autotiled types
+	TileBrush     components.TileType   // Tile type given to painted tiles
+	OneWayBrush   bool                  // When set, painted tiles are jump-through platforms
+	SlopeBrush    components.SlopeShape // Slope shape given to painted tiles
+}
+
+// slopeBrushNames labels each slope shape in the designer, in cycling order.
+var slopeBrushNames = []string{
+	components.SlopeNone:         "full",
+	components.Slope45Up:         "45 up",
+	components.Slope45Down:       "45 down",
+	components.Slope2to1UpLow:    "2:1 up (low)",
+	components.Slope2to1UpHigh:   "2:1 up (high)",
+	components.Slope2to1DownHigh: "2:1 down (high)",
+	components.Slope2to1DownLow:  "2:1 down (low)",
 }
 
 // NewDesigner creates a new designer instance
 func NewDesigner(game *Game) *Designer {
-	tileWidth := float32(game.GrassTile.Width)
-	tileHeight := float32(game.GrassTile.Height)
+	tileset := game.Terrain()
 
 	saveBtn := NewButton(20, 20, 140, 44, "Save Map")
 	saveBtn.NormalColor = rl.Color{R: 34, G: 139, B: 34, A: 220}
@@ -35,14 +51,17 @@ func NewDesigner(game *Game) *Designer {
 	backBtn.NormalColor = rl.Color{R: 70, G: 70, B: 90, A: 220}
 	backBtn.HoverColor = rl.Color{R: 100, G: 100, B: 130, A: 220}
 
+	levelMap := InitMapForDesigner()
 	return &Designer{
 		MapPath:    ResourcePath(DefaultMapPath),
-		TileWidth:  tileWidth,
-		TileHeight: tileHeight,
+		TileWidth:  tileset.TileWidth,
+		TileHeight: tileset.TileHeight,
 		SaveButton: saveBtn,
 		BackButton: backBtn,
-		Map:        InitMapForDesigner(),
-		GrassTile:  game.GrassTile,
+		Map:        levelMap,
+		Tileset:    tileset,
+		Autotile:   NewAutotileGrid(levelMap.Tiles, tileset.TileWidth, tileset.TileHeight),
+		TileBrush:  components.TileGrass,
 	}
 }
 
@@ -52,6 +71,20 @@ func (d *Designer) Update(game *Game) GameMode {
 		return ModeMainMenu
 	}
 
+	if rl.IsKeyPressed(rl.KeyTab) {
+		d.ToggleOneWayBrush()
+	}
+
+	if rl.IsKeyPressed(rl.KeyT) {
+		d.TileBrush = d.nextTileType()
+		d.SetStatus(fmt.Sprintf("Tile: %s", d.Tileset.Tile(d.TileBrush).Name), rl.DarkBlue)
+	}
+
+	if rl.IsKeyPressed(rl.KeyS) {
+		d.SlopeBrush = (d.SlopeBrush + 1) % components.SlopeShape(len(slopeBrushNames))
+		d.SetStatus(fmt.Sprintf("Shape: %s", slopeBrushNames[d.SlopeBrush]), rl.DarkBlue)
+	}
+
 	d.SaveButton.Update()
 	d.BackButton.Update()
 
@@ -83,7 +116,7 @@ func (d *Designer) Update(game *Game) GameMode {
 // Draw renders the designer UI
 func (d *Designer) Draw(game *Game) {
 	rl.ClearBackground(rl.RayWhite)
-	DrawScreenBackground(game.Bg, 0)
+	DrawScreenBackground(game.Assets.Texture(AssetBackground), 0)
 
 	d.DrawGrid(game)
 	d.DrawMap()
@@ -92,29 +125,76 @@ func (d *Designer) Draw(game *Game) {
 	d.DrawStatus()
 }
 
-// DrawMap draws the tiles in the designer
+// DrawMap draws the tiles in the designer through the tileset, as the game
+// does
 func (d *Designer) DrawMap() {
 	for _, tile := range d.Map.Tiles {
-		texture := d.GrassTile
-		tileWidth := float32(texture.Width)
-		tileHeight := float32(texture.Height)
+		def := d.Tileset.Tile(components.TileType(tile.TileType))
+		shape, _ := tile.Shape()
+		sourceRec := def.SourceFor(shape, d.Autotile.Mask(tile.X, tile.Y))
+		destRec := rl.Rectangle{X: tile.X, Y: tile.Y, Width: d.TileWidth, Height: d.TileHeight}
+
+		rl.DrawTexturePro(d.Tileset.Texture, sourceRec, destRec, rl.Vector2{X: 0, Y: 0}, 0, def.Tint)
 
-		sourceRec := rl.Rectangle{X: 0, Y: 0, Width: tileWidth, Height: tileHeight}
-		destRec := rl.Rectangle{X: tile.X, Y: tile.Y, Width: tileWidth, Height: tileHeight}
-		rl.DrawTexturePro(texture, sourceRec, destRec, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+		// Mark one-way platforms with a tint and a top edge
+		if tile.OneWay {
+			rl.DrawRectangleRec(destRec, rl.Color{R: 80, G: 160, B: 255, A: 90})
+			rl.DrawRectangle(int32(tile.X), int32(tile.Y), int32(d.TileWidth), 4, rl.SkyBlue)
+		}
 	}
 }
 
+// ToggleOneWayBrush switches the brush between solid tiles and one-way
+// platforms
+func (d *Designer) ToggleOneWayBrush() {
+	d.OneWayBrush = !d.OneWayBrush
+	if d.OneWayBrush {
+		d.SetStatus("Brush: one-way platform", rl.DarkBlue)
+	} else {
+		d.SetStatus("Brush: solid tile", rl.DarkBlue)
+	}
+}
+
+// nextTileType returns the tileset's tile type after the current brush,
+// wrapping around.
+func (d *Designer) nextTileType() components.TileType {
+	types := d.Tileset.TileTypes()
+	if len(types) == 0 {
+		return d.TileBrush
+	}
+	for _, tileType := range types {
+		if tileType > d.TileBrush {
+			return tileType
+		}
+	}
+	return types[0]
+}
+
 // AddTileAt adds a tile at the given mouse position
 func (d *Designer) AddTileAt(pos rl.Vector2) {
 	snapped := d.SnapToGrid(pos)
-	d.Map.AddTile(snapped.X, snapped.Y, 0) // 0 = Grass
+	tile := TileJSON{
+		X:        snapped.X,
+		Y:        snapped.Y,
+		TileType: int32(d.TileBrush),
+		OneWay:   d.OneWayBrush,
+	}
+	if d.SlopeBrush != components.SlopeNone {
+		tile.Slope = d.SlopeBrush.String()
+	}
+	if d.Map.AddTile(tile) {
+		d.Autotile.Set(tile)
+	}
 }
 
 // RemoveTileAt removes a tile at the given mouse position
 func (d *Designer) RemoveTileAt(pos rl.Vector2) bool {
 	snapped := d.SnapToGrid(pos)
-	return d.Map.RemoveTileAt(snapped.X, snapped.Y)
+	if !d.Map.RemoveTileAt(snapped.X, snapped.Y) {
+		return false
+	}
+	d.Autotile.Remove(snapped.X, snapped.Y)
+	return true
 }
 
 // SnapToGrid snaps a position to the tile grid
@@ -147,9 +227,14 @@ func (d *Designer) DrawButtons() {
 
 // DrawInstructions draws the help text
 func (d *Designer) DrawInstructions() {
-	instruction := "Left-click: add tile | Right-click: remove tile | ESC: menu"
+	instruction := "Left-click: add tile | Right-click: remove tile | T: cycle tile | Tab: toggle one-way | S: cycle shape | ESC: menu"
 	rl.DrawText(instruction, 20, 70, 14, rl.DarkGray)
-	rl.DrawText(fmt.Sprintf("Saving to %s", d.MapPath), 20, 90, 14, rl.DarkGray)
+
+	brush := "solid"
+	if d.OneWayBrush {
+		brush = "one-way"
+	}
+	rl.DrawText(fmt.Sprintf("Saving to %s | Brush: %s %s, %s", d.MapPath, brush, d.Tileset.Tile(d.TileBrush).Name, slopeBrushNames[d.SlopeBrush]), 20, 90, 14, rl.DarkGray)
 }
 
 // DrawStatus draws the status message
@@ -175,4 +260,5 @@ func (d *Designer) SaveMap() error {
 // ReloadMap reloads the map from disk
 func (d *Designer) ReloadMap() {
 	d.Map = InitMapForDesigner()
+	d.Autotile = NewAutotileGrid(d.Map.Tiles, d.TileWidth, d.TileHeight)
 }
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestDesignerOneWayBrush(t *testing.T) {
+	designer := &Designer{
+		TileWidth:  32,
+		TileHeight: 32,
+		Map:        NewLevelMap(),
+		Autotile:   NewAutotileGrid(nil, 32, 32),
+	}
+
+	designer.ToggleOneWayBrush()
+	designer.AddTileAt(rl.Vector2{X: 40, Y: 70})
+	designer.ToggleOneWayBrush()
+	designer.AddTileAt(rl.Vector2{X: 70, Y: 70})
+
+	want := []TileJSON{
+		{X: 32, Y: 64, OneWay: true},
+		{X: 64, Y: 64},
+	}
+	if len(designer.Map.Tiles) != len(want) {
+		t.Fatalf("painted %+v, want %+v", designer.Map.Tiles, want)
+	}
+	for i, tile := range designer.Map.Tiles {
+		if tile != want[i] {
+			t.Errorf("tile %d is %+v, want %+v", i, tile, want[i])
+		}
+	}
+	if designer.OneWayBrush || designer.StatusMessage != "Brush: solid tile" {
+		t.Errorf("brush one-way %v with status %q after toggling twice", designer.OneWayBrush, designer.StatusMessage)
+	}
+
+	// Painting over a solid tile with the one-way brush converts it
+	designer.ToggleOneWayBrush()
+	designer.AddTileAt(rl.Vector2{X: 70, Y: 70})
+	if !designer.Map.Tiles[1].OneWay {
+		t.Errorf("repainted tile %+v, want one-way", designer.Map.Tiles[1])
+	}
+}
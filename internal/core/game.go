@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fire/internal/components"
 	"fire/internal/ecs"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -14,18 +15,9 @@ type Game struct {
 	ScreenWidth  int32
 	ScreenHeight int32
 
-	// Assets
-	FontEmoji   rl.Font
-	Bg          rl.Texture2D
-	GrassTile   rl.Texture2D
-	HealthHeart rl.Texture2D
-
-	// Hero animation data (for spawning player entity)
-	HeroAnimations map[int]AnimationDataLegacy
-	HeroScaling    float32
-
-	// Mob animation data (for spawning mob entities)
-	MobAnimation AnimationDataLegacy
+	// Assets, by manifest key
+	Assets      *AssetCache
+	HeroScaling float32
 
 	// Mode tracking
 	Mode     GameMode
@@ -35,6 +27,7 @@ type Game struct {
 
 	// Game settings
 	HighlightBorders bool
+	DebugOverlay     bool // F3 toggles it in game
 	Gravity          float32
 
 	// ECS World (used in game mode)
@@ -44,32 +37,20 @@ type Game struct {
 // AnimationDataLegacy holds animation data for asset loading.
 // This is used during asset loading and converted to components when spawning entities.
 type AnimationDataLegacy struct {
-	Image         *rl.Image
-	Texture       rl.Texture2D
-	FrameCount    int32
-	CurrentFrame  int32
-	FrameDelay    int32
-	FrameCounter  int32
-	FrameSize     int32
-	IsSpriteSheet bool
+	Texture        rl.Texture2D
+	Frames         []rl.Rectangle // Source rectangle of each frame
+	FrameCount     int32
+	CurrentFrame   int32
+	FrameDurations []float32 // Seconds per frame
+	Mode           components.PlaybackMode
+	Slices         map[string][]components.FrameSlice // Named per-frame regions
 }
 
-// CharacterStateLegacy is used for asset loading keys.
-type CharacterStateLegacy int
-
-const (
-	IdleLegacy CharacterStateLegacy = iota
-	RunningLegacy
-	JumpingLegacy
-	FallingLegacy
-)
-
 func (g *Game) Init() {
 	g.ScreenWidth = 800
 	g.ScreenHeight = 600
-	g.Gravity = 0.6
+	g.Gravity = 1800 // world units per second squared
 	g.HeroScaling = 1.7
-	g.HeroAnimations = make(map[int]AnimationDataLegacy)
 	g.Mode = ModeMainMenu
 }
 
@@ -77,15 +58,14 @@ func (g *Game) Init() {
 func (g *Game) InitUI() {
 	g.MainMenu = NewMainMenu(g.ScreenWidth, g.ScreenHeight)
 	g.Designer = NewDesigner(g)
-	g.Settings = NewSettingsMenu(g.ScreenWidth, g.ScreenHeight, &g.HighlightBorders)
+	g.Settings = NewSettingsMenu(g.ScreenWidth, g.ScreenHeight, &g.HighlightBorders, &g.DebugOverlay)
 }
 
-// InitWorld creates a new ECS world for gameplay.
+// InitWorld creates a new ECS world for gameplay, unloading the previous
+// one.
 func (g *Game) InitWorld() {
+	if g.World != nil {
+		g.World.Unload()
+	}
 	g.World = ecs.NewWorld()
 }
-
-// GetHeroAnimationData returns animation data for a character state.
-func (g *Game) GetHeroAnimationData(state int) AnimationDataLegacy {
-	return g.HeroAnimations[state]
-}
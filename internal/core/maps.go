@@ -3,6 +3,7 @@ package core
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
@@ -18,11 +19,122 @@ type TileJSON struct {
 	X        float32 `json:"x"`
 	Y        float32 `json:"y"`
 	TileType int32   `json:"tileType"`
+	OneWay   bool    `json:"oneWay,omitempty"` // Jump-through platform
+	Slope    string  `json:"slope,omitempty"`  // Slope shape name as in tilesets, e.g. "45up" (empty = full tile)
+}
+
+// Shape returns the tile's slope shape, or an error for an unknown slope name.
+func (t TileJSON) Shape() (components.SlopeShape, error) {
+	if t.Slope == "" {
+		return components.SlopeNone, nil
+	}
+	shape, ok := components.ParseSlopeShape(t.Slope)
+	if !ok {
+		return components.SlopeNone, fmt.Errorf("unknown slope %q", t.Slope)
+	}
+	return shape, nil
+}
+
+// PointJSON represents a 2D point in the JSON format.
+type PointJSON struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+// PlatformJSON represents a moving platform in the JSON format.
+type PlatformJSON struct {
+	Waypoints []PointJSON `json:"waypoints"`
+	Width     float32     `json:"width,omitempty"`  // Defaults to one tile
+	Height    float32     `json:"height,omitempty"` // Defaults to one tile
+	Speed     float32     `json:"speed"`            // Units per second
+	Wait      float32     `json:"wait,omitempty"`   // Seconds paused at each waypoint
+	Easing    int32       `json:"easing,omitempty"` // components.EasingType
+	PingPong  bool        `json:"pingPong,omitempty"`
+	OneWay    bool        `json:"oneWay,omitempty"`
+	TileType  int32       `json:"tileType,omitempty"` // Tile drawn along the platform
+}
+
+// CameraZoneJSON represents a camera zone in the JSON format.
+type CameraZoneJSON struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+	Mode   string  `json:"mode,omitempty"` // "constrain" (default) or "lock"
+	Zoom   float32 `json:"zoom,omitempty"` // Zoom inside the zone (0 keeps the default)
+}
+
+// cameraZoneModes maps the camera zone mode names used in map files.
+var cameraZoneModes = map[string]components.CameraZoneMode{
+	"":          components.ZoneConstrain,
+	"constrain": components.ZoneConstrain,
+	"lock":      components.ZoneLock,
+}
+
+// DecorationJSON represents a piece of scenery in the JSON format.
+type DecorationJSON struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Region string  `json:"region"`          // Texture or texture region key in the asset manifest
+	Layer  string  `json:"layer,omitempty"` // "background" (default) or "foreground"
+	Order  float32 `json:"order,omitempty"` // Draw order within the layer
+	Scale  float32 `json:"scale,omitempty"` // Defaults to 1
+
+	// Particle effect emitted over the decoration, e.g. falling leaves
+	Particles string `json:"particles,omitempty"`
+}
+
+// decorationLayers maps the render layer names used for decorations in map
+// files.
+var decorationLayers = map[string]components.RenderLayer{
+	"":           components.LayerBackground,
+	"background": components.LayerBackground,
+	"foreground": components.LayerForeground,
+}
+
+// ParallaxLayerJSON represents a parallax backdrop layer in the JSON
+// format. Layers are drawn in the order they are listed, back to front.
+type ParallaxLayerJSON struct {
+	Image      string  `json:"image"`             // Texture or texture region key in the asset manifest
+	ScrollX    float32 `json:"scrollX"`           // 0 fixed on screen, 1 moves with the world
+	ScrollY    float32 `json:"scrollY,omitempty"` // Used with the "scroll" anchor
+	OffsetX    float32 `json:"offsetX,omitempty"`
+	OffsetY    float32 `json:"offsetY,omitempty"`
+	Anchor     string  `json:"anchor,omitempty"`     // "scroll" (default), "top" or "bottom"
+	Repeat     bool    `json:"repeat,omitempty"`     // Tile horizontally
+	AutoScroll float32 `json:"autoScroll,omitempty"` // Horizontal drift in units per second
+	Scale      float32 `json:"scale,omitempty"`      // Defaults to 1
+}
+
+// parallaxAnchors maps the parallax anchor names used in map files.
+var parallaxAnchors = map[string]components.ParallaxAnchor{
+	"":       components.AnchorScroll,
+	"scroll": components.AnchorScroll,
+	"top":    components.AnchorTop,
+	"bottom": components.AnchorBottom,
 }
 
 // LevelMap holds the map data for the designer mode and JSON serialization.
 type LevelMap struct {
-	Tiles []TileJSON `json:"tiles"`
+	Tiles       []TileJSON          `json:"tiles"`
+	Platforms   []PlatformJSON      `json:"platforms,omitempty"`
+	CameraZones []CameraZoneJSON    `json:"cameraZones,omitempty"`
+	Decorations []DecorationJSON    `json:"decorations,omitempty"`
+	Parallax    []ParallaxLayerJSON `json:"parallax,omitempty"`
+	Coins       []PointJSON         `json:"coins,omitempty"` // Top-left corners
+
+	// Movement abilities the level unlocks for the player, by name
+	Abilities []string `json:"abilities,omitempty"`
+}
+
+// abilityFlags maps the ability names used in map files to the
+// AbilitiesComponent flag that unlocks each.
+var abilityFlags = map[string]func(*components.AbilitiesComponent) *bool{
+	"wallSlide": func(a *components.AbilitiesComponent) *bool { return &a.WallSlide },
+	"wallJump":  func(a *components.AbilitiesComponent) *bool { return &a.WallJump },
+	"dash":      func(a *components.AbilitiesComponent) *bool { return &a.Dash },
+	"roll":      func(a *components.AbilitiesComponent) *bool { return &a.Roll },
+	"slide":     func(a *components.AbilitiesComponent) *bool { return &a.Slide },
 }
 
 // NewLevelMap creates an empty level map.
@@ -47,6 +159,11 @@ func LoadLevelMap(path string) (LevelMap, error) {
 	if err := json.Unmarshal(data, &levelMap); err != nil {
 		return LevelMap{}, err
 	}
+	for _, tile := range levelMap.Tiles {
+		if _, err := tile.Shape(); err != nil {
+			return LevelMap{}, fmt.Errorf("tile at (%.0f, %.0f): %w", tile.X, tile.Y, err)
+		}
+	}
 
 	return levelMap, nil
 }
@@ -65,20 +182,20 @@ func (m LevelMap) Save(path string) error {
 	return os.WriteFile(path, data, 0o644)
 }
 
-// AddTile adds a tile to the map if it does not already exist at the
-// provided coordinates. Returns true if the tile list changed.
-func (m *LevelMap) AddTile(x, y float32, tileType int32) bool {
+// AddTile adds a tile to the map if none exists at its coordinates, or
+// replaces the existing one. Returns true if the tile list changed.
+func (m *LevelMap) AddTile(tile TileJSON) bool {
 	for idx, existing := range m.Tiles {
-		if existing.X == x && existing.Y == y {
-			if existing.TileType == tileType {
+		if existing.X == tile.X && existing.Y == tile.Y {
+			if existing == tile {
 				return false
 			}
-			m.Tiles[idx].TileType = tileType
+			m.Tiles[idx] = tile
 			return true
 		}
 	}
 
-	m.Tiles = append(m.Tiles, TileJSON{X: x, Y: y, TileType: tileType})
+	m.Tiles = append(m.Tiles, tile)
 	return true
 }
 
@@ -95,16 +212,25 @@ func (m *LevelMap) RemoveTileAt(x, y float32) bool {
 }
 
 // SpawnTiles creates tile entities in the ECS world from the loaded map.
-func SpawnTiles(world *ecs.World, levelMap LevelMap, tileTexture rl.Texture2D) {
+// Each tile takes its look and properties from the tileset, with its piece
+// picked from its neighbours for autotiled types; non-solid tiles get a
+// trigger collider.
+func SpawnTiles(world *ecs.World, levelMap LevelMap, tileset *Tileset) {
 	// Get component stores
 	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
 	colliderStore := ecs.RegisterStore[*components.ColliderComponent](world.Components)
 	tileStore := ecs.RegisterStore[*components.TileComponent](world.Components)
 
-	tileWidth := float32(tileTexture.Width)
-	tileHeight := float32(tileTexture.Height)
-
+	grid := NewAutotileGrid(levelMap.Tiles, tileset.TileWidth, tileset.TileHeight)
 	for _, tile := range levelMap.Tiles {
+		tileType := components.TileType(tile.TileType)
+		shape, err := tile.Shape()
+		if err != nil {
+			log.Printf("Skipping tile at (%.0f, %.0f): %v", tile.X, tile.Y, err)
+			continue
+		}
+		def := tileset.Tile(tileType)
+
 		entity := world.CreateEntity("tile", "ground")
 
 		// Add transform component
@@ -115,27 +241,324 @@ func SpawnTiles(world *ecs.World, levelMap LevelMap, tileTexture rl.Texture2D) {
 
 		// Add collider component
 		colliderStore.Add(entity.ID, &components.ColliderComponent{
-			Bounds:    rl.Rectangle{X: 0, Y: 0, Width: tileWidth, Height: tileHeight},
-			IsTrigger: false,
+			Bounds:    rl.Rectangle{X: 0, Y: 0, Width: tileset.TileWidth, Height: tileset.TileHeight},
+			IsTrigger: !def.Solid,
+			OneWay:    tile.OneWay,
+			Slope:     shape,
 			Layer:     "ground",
 		})
 
 		// Add tile component
 		tileStore.Add(entity.ID, &components.TileComponent{
-			TileType: components.TileType(tile.TileType),
+			TileType: tileType,
+			Shape:    shape,
+			Friction: def.Friction,
+			Damage:   def.Damage,
+			Texture:  tileset.Texture,
+			Source:   def.SourceFor(shape, grid.Mask(tile.X, tile.Y)),
+			Tint:     def.Tint,
+		})
+	}
+}
+
+// SpawnPlatforms creates moving platform entities in the ECS world from the
+// loaded map. Platforms start at their first waypoint and are drawn with
+// their tile from the tileset.
+func SpawnPlatforms(world *ecs.World, levelMap LevelMap, tileset *Tileset) {
+	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+	colliderStore := ecs.RegisterStore[*components.ColliderComponent](world.Components)
+	platformStore := ecs.RegisterStore[*components.PlatformComponent](world.Components)
+
+	for _, platform := range levelMap.Platforms {
+		if len(platform.Waypoints) == 0 {
+			continue
+		}
+
+		width := platform.Width
+		if width <= 0 {
+			width = tileset.TileWidth
+		}
+		height := platform.Height
+		if height <= 0 {
+			height = tileset.TileHeight
+		}
+		def := tileset.Tile(components.TileType(platform.TileType))
+
+		waypoints := make([]rl.Vector2, len(platform.Waypoints))
+		for i, point := range platform.Waypoints {
+			waypoints[i] = rl.Vector2{X: point.X, Y: point.Y}
+		}
+
+		entity := world.CreateEntity("platform", "ground")
+
+		transformStore.Add(entity.ID, &components.TransformComponent{
+			Position:    waypoints[0],
+			FacingRight: true,
+		})
+
+		colliderStore.Add(entity.ID, &components.ColliderComponent{
+			Bounds:    rl.Rectangle{X: 0, Y: 0, Width: width, Height: height},
+			IsTrigger: false,
+			OneWay:    platform.OneWay,
+			Layer:     "ground",
+		})
+
+		platformStore.Add(entity.ID, &components.PlatformComponent{
+			Waypoints: waypoints,
+			Speed:     platform.Speed,
+			WaitTime:  platform.Wait,
+			Easing:    components.EasingType(platform.Easing),
+			PingPong:  platform.PingPong,
+			Direction: 1,
+
+			Texture:    tileset.Texture,
+			Source:     def.Source,
+			Tint:       def.Tint,
+			PieceWidth: tileset.TileWidth,
+		})
+	}
+}
+
+// acquireImage returns the texture and source rectangle of a texture or
+// texture region key, acquiring it from the asset cache on first use.
+func acquireImage(assets *AssetCache, key string) (rl.Texture2D, rl.Rectangle, error) {
+	if assets.Region(key) == nil && assets.Texture(key).ID == 0 {
+		if err := assets.Acquire(key); err != nil {
+			return rl.Texture2D{}, rl.Rectangle{}, err
+		}
+	}
+	if region := assets.Region(key); region != nil {
+		return region.Texture, region.Source, nil
+	}
+	if texture := assets.Texture(key); texture.ID != 0 {
+		return texture, rl.Rectangle{Width: float32(texture.Width), Height: float32(texture.Height)}, nil
+	}
+	return rl.Texture2D{}, rl.Rectangle{}, fmt.Errorf("%q is not a texture or texture region", key)
+}
+
+// SpawnDecorations creates scenery entities from the loaded map. Images are
+// acquired from the asset cache on first use; decorations with an unknown
+// image or layer are skipped with a log line.
+func SpawnDecorations(world *ecs.World, levelMap LevelMap, assets *AssetCache) {
+	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+	decorationStore := ecs.RegisterStore[*components.DecorationComponent](world.Components)
+	renderOrderStore := ecs.RegisterStore[*components.RenderOrderComponent](world.Components)
+
+	for _, decoration := range levelMap.Decorations {
+		layer, ok := decorationLayers[decoration.Layer]
+		if !ok {
+			log.Printf("Skipping decoration %q: unknown layer %q", decoration.Region, decoration.Layer)
+			continue
+		}
+		texture, source, err := acquireImage(assets, decoration.Region)
+		if err != nil {
+			log.Printf("Skipping decoration %q: %v", decoration.Region, err)
+			continue
+		}
+
+		scale := decoration.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+
+		entity := world.CreateEntity("decoration")
+
+		transformStore.Add(entity.ID, &components.TransformComponent{
+			Position:    rl.Vector2{X: decoration.X, Y: decoration.Y},
+			FacingRight: true,
+		})
+
+		decorationStore.Add(entity.ID, &components.DecorationComponent{
+			Texture: texture,
+			Source:  source,
+			Scale:   scale,
+		})
+
+		renderOrderStore.Add(entity.ID, &components.RenderOrderComponent{
+			Layer: layer,
+			Order: decoration.Order,
+		})
+	}
+}
+
+// SpawnCoins creates a coin entity at each of the map's coin positions: the
+// coin image with a trigger collider the size of the image.
+func SpawnCoins(world *ecs.World, levelMap LevelMap, assets *AssetCache) {
+	if len(levelMap.Coins) == 0 {
+		return
+	}
+	texture, source, err := acquireImage(assets, AssetCoin)
+	if err != nil {
+		log.Printf("Skipping coins: %v", err)
+		return
+	}
+
+	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+	decorationStore := ecs.RegisterStore[*components.DecorationComponent](world.Components)
+	renderOrderStore := ecs.RegisterStore[*components.RenderOrderComponent](world.Components)
+	colliderStore := ecs.RegisterStore[*components.ColliderComponent](world.Components)
+	coinStore := ecs.RegisterStore[*components.CoinComponent](world.Components)
+
+	for _, coin := range levelMap.Coins {
+		entity := world.CreateEntity("coin")
+
+		transformStore.Add(entity.ID, &components.TransformComponent{
+			Position:    rl.Vector2{X: coin.X, Y: coin.Y},
+			FacingRight: true,
+		})
+
+		decorationStore.Add(entity.ID, &components.DecorationComponent{
+			Texture: texture,
+			Source:  source,
+			Scale:   CoinScale,
+		})
+
+		renderOrderStore.Add(entity.ID, &components.RenderOrderComponent{
+			Layer: components.LayerEntities,
+		})
+
+		colliderStore.Add(entity.ID, &components.ColliderComponent{
+			Bounds:    rl.Rectangle{Width: source.Width * CoinScale, Height: source.Height * CoinScale},
+			IsTrigger: true,
+			Layer:     "pickup",
+		})
+
+		coinStore.Add(entity.ID, &components.CoinComponent{})
+	}
+}
+
+// SpawnParallax creates a parallax layer entity for each of the map's
+// backdrop layers, drawn in the order they are listed. Layers with an
+// unknown image or anchor are skipped with a log line.
+func SpawnParallax(world *ecs.World, levelMap LevelMap, assets *AssetCache) {
+	parallaxStore := ecs.RegisterStore[*components.ParallaxComponent](world.Components)
+	renderOrderStore := ecs.RegisterStore[*components.RenderOrderComponent](world.Components)
+
+	for i, layer := range levelMap.Parallax {
+		anchor, ok := parallaxAnchors[layer.Anchor]
+		if !ok {
+			log.Printf("Skipping parallax layer %q: unknown anchor %q", layer.Image, layer.Anchor)
+			continue
+		}
+		texture, source, err := acquireImage(assets, layer.Image)
+		if err != nil {
+			log.Printf("Skipping parallax layer %q: %v", layer.Image, err)
+			continue
+		}
+
+		scale := layer.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+
+		entity := world.CreateEntity("parallax")
+
+		parallaxStore.Add(entity.ID, &components.ParallaxComponent{
+			Texture:      texture,
+			Source:       source,
+			Scale:        scale,
+			ScrollFactor: rl.Vector2{X: layer.ScrollX, Y: layer.ScrollY},
+			Offset:       rl.Vector2{X: layer.OffsetX, Y: layer.OffsetY},
+			Anchor:       anchor,
+			Repeat:       layer.Repeat,
+			AutoScroll:   layer.AutoScroll,
+		})
+
+		renderOrderStore.Add(entity.ID, &components.RenderOrderComponent{
+			Layer: components.LayerParallax,
+			Order: float32(i),
+		})
+	}
+}
+
+// Bounds returns the area covered by the map's tiles and platform paths, or
+// an empty rectangle for an empty map.
+func (m LevelMap) Bounds(tileWidth, tileHeight float32) rl.Rectangle {
+	var bounds rl.Rectangle
+	add := func(area rl.Rectangle) {
+		if bounds.Width == 0 && bounds.Height == 0 {
+			bounds = area
+			return
+		}
+		bounds = unionRect(bounds, area)
+	}
+
+	for _, tile := range m.Tiles {
+		add(rl.Rectangle{X: tile.X, Y: tile.Y, Width: tileWidth, Height: tileHeight})
+	}
+	for _, platform := range m.Platforms {
+		width, height := platform.Width, platform.Height
+		if width <= 0 {
+			width = tileWidth
+		}
+		if height <= 0 {
+			height = tileHeight
+		}
+		for _, point := range platform.Waypoints {
+			add(rl.Rectangle{X: point.X, Y: point.Y, Width: width, Height: height})
+		}
+	}
+	return bounds
+}
+
+// ToCameraZones converts the map's camera zones. Zones with an unknown mode
+// are skipped with a log line.
+func (m LevelMap) ToCameraZones() []components.CameraZone {
+	zones := make([]components.CameraZone, 0, len(m.CameraZones))
+	for _, zone := range m.CameraZones {
+		mode, ok := cameraZoneModes[zone.Mode]
+		if !ok {
+			log.Printf("Skipping camera zone at (%.0f, %.0f): unknown mode %q", zone.X, zone.Y, zone.Mode)
+			continue
+		}
+		zones = append(zones, components.CameraZone{
+			Area: rl.Rectangle{X: zone.X, Y: zone.Y, Width: zone.Width, Height: zone.Height},
+			Mode: mode,
+			Zoom: zone.Zoom,
 		})
 	}
+	return zones
+}
+
+// UnlockAbilities enables the movement abilities the map lists on every
+// player entity. Unknown ability names are skipped with a log line.
+func UnlockAbilities(world *ecs.World, levelMap LevelMap) {
+	abilitiesStore, ok := ecs.GetStore[*components.AbilitiesComponent](world.Components)
+	if !ok {
+		return
+	}
+	for _, name := range levelMap.Abilities {
+		flag, ok := abilityFlags[name]
+		if !ok {
+			log.Printf("Skipping unknown ability %q", name)
+			continue
+		}
+		for _, player := range world.GetEntitiesWithTag("player") {
+			if abilities, ok := abilitiesStore.Get(player.ID); ok {
+				*flag(abilities) = true
+			}
+		}
+	}
 }
 
-// LoadAndSpawnMap loads the map from disk and spawns tile entities.
-func LoadAndSpawnMap(world *ecs.World, tileTexture rl.Texture2D) {
+// LoadAndSpawnMap loads the map from disk, spawns tile, platform,
+// decoration, coin and parallax entities, unlocks the map's abilities for the
+// player and returns the map (empty when it could not be loaded).
+func LoadAndSpawnMap(world *ecs.World, game *Game) LevelMap {
+	tileset := game.Terrain()
 	mapPath := ResourcePath(DefaultMapPath)
 	levelMap, err := LoadLevelMap(mapPath)
 	if err != nil {
 		log.Printf("Unable to load map %s, starting empty: %v", mapPath, err)
-		return
+		return NewLevelMap()
 	}
-	SpawnTiles(world, levelMap, tileTexture)
+	SpawnTiles(world, levelMap, tileset)
+	SpawnPlatforms(world, levelMap, tileset)
+	SpawnDecorations(world, levelMap, game.Assets)
+	SpawnCoins(world, levelMap, game.Assets)
+	SpawnParallax(world, levelMap, game.Assets)
+	UnlockAbilities(world, levelMap)
+	return levelMap
 }
 
 // InitMapForDesigner loads the map for the designer mode.
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestUnlockAbilities(t *testing.T) {
+	world := ecs.NewWorld()
+	abilitiesStore := ecs.RegisterStore[*components.AbilitiesComponent](world.Components)
+	player := world.CreateEntity("player")
+	abilities := &components.AbilitiesComponent{}
+	abilitiesStore.Add(player.ID, abilities)
+	mob := world.CreateEntity("enemy")
+	mobAbilities := &components.AbilitiesComponent{}
+	abilitiesStore.Add(mob.ID, mobAbilities)
+
+	UnlockAbilities(world, LevelMap{Abilities: []string{"wallSlide", "dash", "fly"}})
+
+	want := components.AbilitiesComponent{WallSlide: true, Dash: true}
+	if *abilities != want {
+		t.Errorf("player abilities %+v, want %+v", *abilities, want)
+	}
+	if *mobAbilities != (components.AbilitiesComponent{}) {
+		t.Errorf("non-player abilities unlocked: %+v", *mobAbilities)
+	}
+}
+
+func TestAbilityFlagsAreDistinct(t *testing.T) {
+	var abilities components.AbilitiesComponent
+	seen := make(map[*bool]string, len(abilityFlags))
+	for name, flag := range abilityFlags {
+		if other, ok := seen[flag(&abilities)]; ok {
+			t.Errorf("%q and %q unlock the same flag", name, other)
+		}
+		seen[flag(&abilities)] = name
+	}
+}
+
+func TestLevelMapOneWayRoundTrip(t *testing.T) {
+	levelMap := NewLevelMap()
+	levelMap.AddTile(TileJSON{X: 0, Y: 64, TileType: 1})
+	levelMap.AddTile(TileJSON{X: 32, Y: 64, TileType: 1, OneWay: true})
+	levelMap.Platforms = []PlatformJSON{
+		{Waypoints: []PointJSON{{X: 0, Y: 0}, {X: 64, Y: 0}}, Speed: 60, OneWay: true},
+		{Waypoints: []PointJSON{{X: 0, Y: 32}}, Speed: 60},
+	}
+
+	path := filepath.Join(t.TempDir(), "map.json")
+	if err := levelMap.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Solid tiles leave the flag out of the file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(data), `"oneWay"`); count != 2 {
+		t.Errorf("saved map has %d oneWay keys, want 2:\n%s", count, data)
+	}
+
+	loaded, err := LoadLevelMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Tiles) != 2 || loaded.Tiles[0].OneWay || !loaded.Tiles[1].OneWay {
+		t.Errorf("loaded tiles %+v, want the second one-way", loaded.Tiles)
+	}
+	if len(loaded.Platforms) != 2 || !loaded.Platforms[0].OneWay || loaded.Platforms[1].OneWay {
+		t.Errorf("loaded platforms %+v, want the first one-way", loaded.Platforms)
+	}
+}
+
+func TestLevelMapSlopeNames(t *testing.T) {
+	levelMap := NewLevelMap()
+	levelMap.AddTile(TileJSON{X: 0, Y: 64, TileType: 1})
+	levelMap.AddTile(TileJSON{X: 32, Y: 64, TileType: 1, Slope: components.Slope2to1UpLow.String()})
+
+	path := filepath.Join(t.TempDir(), "map.json")
+	if err := levelMap.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(data), `"slope": "2to1upLow"`); count != 1 {
+		t.Errorf("saved map has %d named slopes, want 1:\n%s", count, data)
+	}
+
+	loaded, err := LoadLevelMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []components.SlopeShape{components.SlopeNone, components.Slope2to1UpLow} {
+		if shape, err := loaded.Tiles[i].Shape(); err != nil || shape != want {
+			t.Errorf("tile %d shape %v, %v; want %v", i, shape, err, want)
+		}
+	}
+
+	// Unknown names fail the load instead of spawning full tiles
+	if err := os.WriteFile(path, []byte(`{"tiles": [{"x": 0, "y": 0, "tileType": 1, "slope": "3"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLevelMap(path); err == nil || !strings.Contains(err.Error(), `unknown slope "3"`) {
+		t.Errorf("loading an unknown slope returned %v, want an error naming it", err)
+	}
+}
+
+func TestSpawnOneWayColliders(t *testing.T) {
+	levelMap := LevelMap{
+		Tiles: []TileJSON{
+			{X: 0, Y: 64, TileType: 1},
+			{X: 32, Y: 64, TileType: 1, OneWay: true},
+		},
+		Platforms: []PlatformJSON{
+			{Waypoints: []PointJSON{{X: 0, Y: 0}}, OneWay: true},
+		},
+	}
+	tileset := newTileset(TilesetJSON{
+		GridWidth:  16,
+		GridHeight: 16,
+		TileWidth:  32,
+		TileHeight: 32,
+		Tiles:      []TileDefJSON{{ID: 1, Name: "grass"}},
+	}, rl.Texture2D{})
+
+	world := ecs.NewWorld()
+	SpawnTiles(world, levelMap, tileset)
+	SpawnPlatforms(world, levelMap, tileset)
+
+	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
+	transformStore, _ := ecs.GetStore[*components.TransformComponent](world.Components)
+	solids := world.GetEntitiesWithTag("ground")
+	if len(solids) != 3 {
+		t.Fatalf("spawned %d solids, want 3", len(solids))
+	}
+	for _, entity := range solids {
+		collider, _ := colliderStore.Get(entity.ID)
+		transform, _ := transformStore.Get(entity.ID)
+		want := entity.HasTag("platform") || transform.Position.X == 32
+		if collider.OneWay != want {
+			t.Errorf("%v at %v: one-way %v, want %v", entity.Tags, transform.Position, collider.OneWay, want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ParticleEffectJSON is a particle emitter in the particle effects file.
+// Ranges are [min, max]; size is [at birth, at death].
+type ParticleEffectJSON struct {
+	Image        string     `json:"image,omitempty"`   // Texture or texture region key; squares when empty
+	Trigger      string     `json:"trigger,omitempty"` // "land" or "hit" to burst on that event
+	Rate         float32    `json:"rate,omitempty"`    // Particles per second, emitted continuously
+	Burst        int        `json:"burst,omitempty"`   // Particles per triggered burst
+	Lifetime     [2]float32 `json:"lifetime"`          // Seconds
+	Speed        [2]float32 `json:"speed"`             // Units per second
+	Direction    float32    `json:"direction"`         // Degrees; 0 is right, 90 is down
+	Spread       float32    `json:"spread"`            // Width of the direction cone in degrees
+	Gravity      [2]float32 `json:"gravity,omitempty"`
+	Drag         float32    `json:"drag,omitempty"` // Fraction of velocity lost per second
+	Spin         float32    `json:"spin,omitempty"` // Maximum degrees per second, either way
+	Size         [2]float32 `json:"size"`
+	ColorStart   []uint8    `json:"colorStart,omitempty"` // RGB or RGBA, defaults to white
+	ColorEnd     []uint8    `json:"colorEnd,omitempty"`   // Defaults to colorStart
+	Layer        string     `json:"layer,omitempty"`      // "background", "entities" (default) or "foreground"
+	Order        float32    `json:"order,omitempty"`
+	MaxParticles int        `json:"maxParticles"` // Pool size
+}
+
+// ParticleEffectsJSON is the particle effects file: effects by name.
+type ParticleEffectsJSON struct {
+	Effects map[string]ParticleEffectJSON `json:"effects"`
+}
+
+// particleTriggers maps the trigger names used in the particle effects file.
+var particleTriggers = map[string]components.ParticleTrigger{
+	"":     components.TriggerNone,
+	"land": components.TriggerLand,
+	"hit":  components.TriggerHit,
+}
+
+// particleLayers maps the render layer names used in the particle effects
+// file.
+var particleLayers = map[string]components.RenderLayer{
+	"":           components.LayerEntities,
+	"background": components.LayerBackground,
+	"entities":   components.LayerEntities,
+	"foreground": components.LayerForeground,
+}
+
+// LoadParticleEffects loads and validates the particle effects file.
+func LoadParticleEffects(path string) (map[string]ParticleEffectJSON, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file ParticleEffectsJSON
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	for name, effect := range file.Effects {
+		if _, ok := particleTriggers[effect.Trigger]; !ok {
+			return nil, fmt.Errorf("%s: effect %q: unknown trigger %q", path, name, effect.Trigger)
+		}
+		if _, ok := particleLayers[effect.Layer]; !ok {
+			return nil, fmt.Errorf("%s: effect %q: unknown layer %q", path, name, effect.Layer)
+		}
+		if effect.MaxParticles <= 0 {
+			return nil, fmt.Errorf("%s: effect %q: maxParticles must be positive", path, name)
+		}
+		if effect.Lifetime[0] <= 0 || effect.Lifetime[1] < effect.Lifetime[0] {
+			return nil, fmt.Errorf("%s: effect %q: lifetime must be a positive range", path, name)
+		}
+		if !validColorJSON(effect.ColorStart) || !validColorJSON(effect.ColorEnd) {
+			return nil, fmt.Errorf("%s: effect %q: colours need 3 or 4 components", path, name)
+		}
+	}
+	return file.Effects, nil
+}
+
+// toEmitter builds an emitter for the effect, acquiring its image.
+func (e ParticleEffectJSON) toEmitter(assets *AssetCache) (*components.ParticleEmitterComponent, error) {
+	emitter := &components.ParticleEmitterComponent{
+		ColorStart:   colorFromJSON(e.ColorStart, rl.White),
+		SizeStart:    e.Size[0],
+		SizeEnd:      e.Size[1],
+		Emitting:     e.Rate > 0,
+		Rate:         e.Rate,
+		BurstCount:   e.Burst,
+		Trigger:      particleTriggers[e.Trigger],
+		LifetimeMin:  e.Lifetime[0],
+		LifetimeMax:  e.Lifetime[1],
+		SpeedMin:     e.Speed[0],
+		SpeedMax:     e.Speed[1],
+		Direction:    e.Direction,
+		Spread:       e.Spread,
+		Gravity:      rl.Vector2{X: e.Gravity[0], Y: e.Gravity[1]},
+		Drag:         e.Drag,
+		SpinMax:      e.Spin,
+		MaxParticles: e.MaxParticles,
+		Particles:    make([]components.Particle, 0, e.MaxParticles),
+	}
+	emitter.ColorEnd = colorFromJSON(e.ColorEnd, emitter.ColorStart)
+
+	if e.Image != "" {
+		texture, source, err := acquireImage(assets, e.Image)
+		if err != nil {
+			return nil, err
+		}
+		emitter.Texture = texture
+		emitter.Source = source
+	}
+	return emitter, nil
+}
+
+// SpawnParticles loads the particle effects and creates their emitters: one
+// for each triggered effect, which bursts wherever its event happens, and
+// one over each map decoration that names an effect. Effects that fail to
+// load are logged and skipped.
+func SpawnParticles(world *ecs.World, game *Game, levelMap LevelMap) {
+	path := ResourcePath(DefaultParticlesPath)
+	effects, err := LoadParticleEffects(path)
+	if err != nil {
+		log.Printf("Unable to load particle effects %s, no particles: %v", path, err)
+		return
+	}
+
+	// Spawn in name order so entity IDs, and so draw order, are stable
+	names := make([]string, 0, len(effects))
+	for name := range effects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if effects[name].Trigger != "" {
+			spawnEmitter(world, game.Assets, name, effects[name], rl.Vector2{}, rl.Vector2{})
+		}
+	}
+
+	for _, decoration := range levelMap.Decorations {
+		if decoration.Particles == "" {
+			continue
+		}
+		effect, ok := effects[decoration.Particles]
+		if !ok {
+			log.Printf("Skipping particles of decoration %q: unknown effect %q", decoration.Region, decoration.Particles)
+			continue
+		}
+		_, source, err := acquireImage(game.Assets, decoration.Region)
+		if err != nil {
+			continue
+		}
+		scale := decoration.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+		area := rl.Vector2{X: source.Width * scale, Y: source.Height * scale}
+		spawnEmitter(world, game.Assets, decoration.Particles, effect, rl.Vector2{X: decoration.X, Y: decoration.Y}, area)
+	}
+}
+
+// spawnEmitter creates an emitter entity for an effect, emitting over area
+// with its top-left corner at position.
+func spawnEmitter(world *ecs.World, assets *AssetCache, name string, effect ParticleEffectJSON, position, area rl.Vector2) {
+	emitter, err := effect.toEmitter(assets)
+	if err != nil {
+		log.Printf("Skipping particle effect %q: %v", name, err)
+		return
+	}
+	emitter.Offset = rl.Vector2{X: area.X / 2, Y: area.Y / 2}
+	emitter.Area = area
+
+	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+	emitterStore := ecs.RegisterStore[*components.ParticleEmitterComponent](world.Components)
+	renderOrderStore := ecs.RegisterStore[*components.RenderOrderComponent](world.Components)
+
+	entity := world.CreateEntity("particles")
+	transformStore.Add(entity.ID, &components.TransformComponent{Position: position, FacingRight: true})
+	emitterStore.Add(entity.ID, emitter)
+	renderOrderStore.Add(entity.ID, &components.RenderOrderComponent{
+		Layer: particleLayers[effect.Layer],
+		Order: effect.Order,
+	})
+}
@@ -9,6 +9,18 @@ import (
 // DefaultMapPath is the relative path to the custom map file (under project root).
 const DefaultMapPath = "maps/custom_map.json"
 
+// DefaultAttacksPath is the relative path to the player's melee move set.
+const DefaultAttacksPath = "resources/data/attacks.json"
+
+// DefaultHeroAnimatorPath is the relative path to the hero's animation state machine.
+const DefaultHeroAnimatorPath = "resources/data/hero_animator.json"
+
+// DefaultParticlesPath is the relative path to the particle effects.
+const DefaultParticlesPath = "resources/data/particles.json"
+
+// DefaultAssetManifestPath is the relative path to the asset manifest.
+const DefaultAssetManifestPath = "resources/data/assets.json"
+
 var (
 	projectRootOnce sync.Once
 	projectRoot     string
@@ -76,10 +76,11 @@ type SettingsMenu struct {
 	TitleFontSize    int32
 	BackButton       Button
 	HighlightToggle  Toggle
+	DebugToggle      Toggle
 }
 
 // NewSettingsMenu creates a new settings menu
-func NewSettingsMenu(screenWidth, screenHeight int32, highlightBorders *bool) *SettingsMenu {
+func NewSettingsMenu(screenWidth, screenHeight int32, highlightBorders, debugOverlay *bool) *SettingsMenu {
 	centerX := float32(screenWidth) / 2
 	centerY := float32(screenHeight) / 2
 
@@ -93,11 +94,15 @@ func NewSettingsMenu(screenWidth, screenHeight int32, highlightBorders *bool) *S
 	toggleY := centerY - 20
 	highlightToggle := NewToggle(toggleX, toggleY, "Highlight Object Borders", highlightBorders)
 
+	// Toggle for the debug overlay, below it
+	debugToggle := NewToggle(toggleX, toggleY+50, "Debug Overlay (F3)", debugOverlay)
+
 	return &SettingsMenu{
 		Title:           "Settings",
 		TitleFontSize:   48,
 		BackButton:      backButton,
 		HighlightToggle: highlightToggle,
+		DebugToggle:     debugToggle,
 	}
 }
 
@@ -105,6 +110,7 @@ func NewSettingsMenu(screenWidth, screenHeight int32, highlightBorders *bool) *S
 func (s *SettingsMenu) Update() GameMode {
 	s.BackButton.Update()
 	s.HighlightToggle.Update()
+	s.DebugToggle.Update()
 
 	if s.BackButton.IsClicked() || rl.IsKeyPressed(rl.KeyEscape) {
 		return ModeMainMenu
@@ -118,6 +124,7 @@ func (s *SettingsMenu) Draw(bg rl.Texture2D) {
 	DrawScreenTitleWithShadow(s.Title, s.TitleFontSize, 60, rl.Color{R: 100, G: 149, B: 237, A: 255})
 
 	s.HighlightToggle.Draw()
+	s.DebugToggle.Draw()
 	s.BackButton.Draw()
 
 	DrawScreenInstructions("Click toggles to change settings", int32(rl.GetScreenHeight())-40, 16, rl.Color{R: 150, G: 150, B: 150, A: 255})
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"log"
+
 	"fire/internal/components"
 	"fire/internal/ecs"
 
@@ -10,24 +12,34 @@ import (
 // legacyToAnimationData converts legacy asset data to a component AnimationData.
 func legacyToAnimationData(legacy AnimationDataLegacy) *components.AnimationData {
 	return &components.AnimationData{
-		Image:         legacy.Image,
-		Texture:       legacy.Texture,
-		FrameCount:    legacy.FrameCount,
-		CurrentFrame:  0,
-		FrameDelay:    legacy.FrameDelay,
-		FrameCounter:  0,
-		FrameSize:     legacy.FrameSize,
-		IsSpriteSheet: legacy.IsSpriteSheet,
+		Texture:        legacy.Texture,
+		Frames:         legacy.Frames,
+		FrameCount:     legacy.FrameCount,
+		CurrentFrame:   0,
+		FrameDurations: legacy.FrameDurations,
+		Speed:          1,
+		Mode:           legacy.Mode,
+		Direction:      1,
+		Slices:         legacy.Slices,
 	}
 }
 
-// buildHeroAnimations builds the hero animation map from game legacy data.
-func buildHeroAnimations(game *Game) map[components.AnimationState]*components.AnimationData {
+// buildAnimations builds an entity's animation map from a loaded animation
+// set. Each entity gets its own playback state; textures are shared. Clips
+// whose names are not animation states are skipped with a log line.
+func buildAnimations(set *AnimationSet) map[components.AnimationState]*components.AnimationData {
 	animations := make(map[components.AnimationState]*components.AnimationData)
-	animations[components.AnimIdle] = legacyToAnimationData(game.GetHeroAnimationData(int(IdleLegacy)))
-	animations[components.AnimRunning] = legacyToAnimationData(game.GetHeroAnimationData(int(RunningLegacy)))
-	animations[components.AnimJumping] = legacyToAnimationData(game.GetHeroAnimationData(int(JumpingLegacy)))
-	animations[components.AnimFalling] = legacyToAnimationData(game.GetHeroAnimationData(int(FallingLegacy)))
+	if set == nil {
+		return animations
+	}
+	for name, clip := range set.Clips {
+		state, ok := components.ParseAnimationState(name)
+		if !ok {
+			log.Printf("Skipping clip %q: not an animation state", name)
+			continue
+		}
+		animations[state] = legacyToAnimationData(clip)
+	}
 	return animations
 }
 
@@ -39,6 +51,11 @@ func SpawnPlayer(world *ecs.World, game *Game) *ecs.Entity {
 	inputStore := ecs.RegisterStore[*components.InputComponent](world.Components)
 	physicsStore := ecs.RegisterStore[*components.PhysicsComponent](world.Components)
 	healthStore := ecs.RegisterStore[*components.HealthComponent](world.Components)
+	abilitiesStore := ecs.RegisterStore[*components.AbilitiesComponent](world.Components)
+	scoreStore := ecs.RegisterStore[*components.ScoreComponent](world.Components)
+	meleeStore := ecs.RegisterStore[*components.MeleeComponent](world.Components)
+	animatorStore := ecs.RegisterStore[*components.AnimatorComponent](world.Components)
+	renderOrderStore := ecs.RegisterStore[*components.RenderOrderComponent](world.Components)
 
 	player := world.CreateEntity("player")
 
@@ -49,21 +66,40 @@ func SpawnPlayer(world *ecs.World, game *Game) *ecs.Entity {
 		FacingRight: true,
 	})
 
-	animations := buildHeroAnimations(game)
+	animations := buildAnimations(game.Assets.Animations(AssetHero))
 	spriteStore.Add(player.ID, &components.SpriteComponent{
 		Animations:  animations,
 		CurrentAnim: components.AnimIdle,
 		Scale:       game.HeroScaling,
 	})
 
-	idleTexture := animations[components.AnimIdle].Texture
-	colliderWidth := float32(idleTexture.Width) * game.HeroScaling
-	colliderHeight := float32(idleTexture.Height) * game.HeroScaling
+	// The player is drawn over the other characters
+	renderOrderStore.Add(player.ID, &components.RenderOrderComponent{
+		Layer: components.LayerEntities,
+		Order: PlayerRenderOrder,
+	})
+
+	// Animation state machine (states and transitions live in data)
+	animatorPath := ResourcePath(DefaultHeroAnimatorPath)
+	if controller, err := LoadAnimatorController(animatorPath); err != nil {
+		log.Printf("Unable to load animator %s, player stays idle: %v", animatorPath, err)
+	} else {
+		animatorStore.Add(player.ID, &components.AnimatorComponent{Controller: controller})
+	}
 
+	idleFrame := rl.Rectangle{Width: HeroFrameWidth, Height: HeroFrameHeight}
+	if idle, ok := animations[components.AnimIdle]; ok {
+		idleFrame = idle.FrameRect(0)
+	}
+	colliderWidth := idleFrame.Width * game.HeroScaling
+	colliderHeight := idleFrame.Height * game.HeroScaling
+
+	standingBounds := rl.Rectangle{X: 0, Y: 0, Width: colliderWidth, Height: colliderHeight}
 	colliderStore.Add(player.ID, &components.ColliderComponent{
-		Bounds:    rl.Rectangle{X: 0, Y: 0, Width: colliderWidth, Height: colliderHeight},
-		IsTrigger: false,
-		Layer:     "player",
+		Bounds:         standingBounds,
+		StandingBounds: standingBounds,
+		IsTrigger:      false,
+		Layer:          "player",
 	})
 
 	inputStore.Add(player.ID, &components.InputComponent{
@@ -73,27 +109,90 @@ func SpawnPlayer(world *ecs.World, game *Game) *ecs.Entity {
 	})
 
 	physicsStore.Add(player.ID, &components.PhysicsComponent{
-		Gravity:    game.Gravity,
-		JumpForce:  PlayerJumpForce,
-		MoveSpeed:  PlayerMoveSpeed,
-		IsOnGround: false,
+		Gravity:               game.Gravity,
+		JumpForce:             PlayerJumpForce,
+		MoveSpeed:             PlayerMoveSpeed,
+		IsOnGround:            false,
+		JumpCutMultiplier:     PlayerJumpCutMultiplier,
+		CoyoteTime:            PlayerCoyoteTime,
+		JumpBufferTime:        PlayerJumpBufferTime,
+		MaxFallSpeed:          PlayerMaxFallSpeed,
+		GroundAcceleration:    PlayerGroundAcceleration,
+		GroundDeceleration:    PlayerGroundDeceleration,
+		TurnAroundBraking:     PlayerTurnAroundBraking,
+		AirControl:            PlayerAirControl,
+		TurnAroundSpeed:       PlayerTurnAroundSpeed,
+		SurfaceFriction:       1,
+		DropThroughTime:       PlayerDropThroughTime,
+		CrouchHeight:          colliderHeight * PlayerCrouchHeightRatio,
+		CrouchSpeedMultiplier: PlayerCrouchSpeedMultiplier,
+		CrouchTransitionTime:  PlayerCrouchTransitionTime,
 	})
 
 	healthStore.Add(player.ID, &components.HealthComponent{
-		Current: PlayerHealthMax,
-		Max:     PlayerHealthMax,
+		Current:        PlayerHealthMax,
+		Max:            PlayerHealthMax,
+		HazardCooldown: PlayerHazardCooldown,
+	})
+
+	scoreStore.Add(player.ID, &components.ScoreComponent{})
+
+	// Abilities start locked; the level map unlocks them (see UnlockAbilities)
+	abilitiesStore.Add(player.ID, &components.AbilitiesComponent{
+		WallSlideSpeed:   PlayerWallSlideSpeed,
+		WallJumpForce:    rl.Vector2{X: PlayerWallJumpForceX, Y: PlayerWallJumpForceY},
+		WallJumpLockTime: PlayerWallJumpLockTime,
+		DashDistance:     PlayerDashDistance,
+		DashDuration:     PlayerDashDuration,
+		DashCooldown:     PlayerDashCooldown,
+		RollSpeed:        PlayerRollSpeed,
+		RollDuration:     PlayerRollDuration,
+		RollCooldown:     PlayerRollCooldown,
+		RollHeight:       colliderHeight * PlayerRollHeightRatio,
+		SlideSpeed:       PlayerSlideSpeed,
+		SlideDuration:    PlayerSlideDuration,
+		SlideCooldown:    PlayerSlideCooldown,
+		SlideHeight:      colliderHeight * PlayerSlideHeightRatio,
 	})
 
+	// Melee move set (hitbox timing lives in data)
+	attacksPath := ResourcePath(DefaultAttacksPath)
+	if attackSet, err := LoadAttackSet(attacksPath); err != nil {
+		log.Printf("Unable to load attacks %s, player cannot attack: %v", attacksPath, err)
+	} else if melee, err := attackSet.ToMeleeComponent("enemy"); err != nil {
+		log.Printf("Invalid attacks %s, player cannot attack: %v", attacksPath, err)
+	} else {
+		meleeStore.Add(player.ID, melee)
+	}
+
 	return player
 }
 
-// SpawnMob creates a mob entity.
-func SpawnMob(world *ecs.World, game *Game, x, y float32) *ecs.Entity {
+// SpawnMob creates a mob entity drawn with the animation set key, with the
+// collider and speed the asset manifest declares for it. An animation set
+// that was not preloaded is acquired here. It returns nil if the manifest
+// has no mob under key or its animations fail to load.
+func SpawnMob(world *ecs.World, game *Game, key string, x, y float32) *ecs.Entity {
+	data, ok := game.Assets.Mob(key)
+	if !ok {
+		log.Printf("Unable to spawn mob %q: not in the asset manifest", key)
+		return nil
+	}
+	// Mobs that are not preloaded load with the first one spawned and stay
+	// cached until UnloadAll
+	if game.Assets.Animations(key) == nil {
+		if err := game.Assets.Acquire(key); err != nil {
+			log.Printf("Unable to spawn mob %q: %v", key, err)
+			return nil
+		}
+	}
+
 	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
 	spriteStore := ecs.RegisterStore[*components.SpriteComponent](world.Components)
 	colliderStore := ecs.RegisterStore[*components.ColliderComponent](world.Components)
 	physicsStore := ecs.RegisterStore[*components.PhysicsComponent](world.Components)
 	aiStore := ecs.RegisterStore[*components.AIComponent](world.Components)
+	healthStore := ecs.RegisterStore[*components.HealthComponent](world.Components)
 
 	mob := world.CreateEntity("enemy", "mob")
 
@@ -104,12 +203,7 @@ func SpawnMob(world *ecs.World, game *Game, x, y float32) *ecs.Entity {
 		FacingRight: false,
 	})
 
-	animData := legacyToAnimationData(game.MobAnimation)
-	animations := map[components.AnimationState]*components.AnimationData{
-		components.AnimIdle:    animData,
-		components.AnimRunning: animData,
-	}
-
+	animations := buildAnimations(game.Assets.Animations(key))
 	spriteStore.Add(mob.ID, &components.SpriteComponent{
 		Animations:  animations,
 		CurrentAnim: components.AnimRunning,
@@ -117,27 +211,103 @@ func SpawnMob(world *ecs.World, game *Game, x, y float32) *ecs.Entity {
 	})
 
 	colliderStore.Add(mob.ID, &components.ColliderComponent{
-		Bounds:    rl.Rectangle{X: 0, Y: 0, Width: MobColliderWidth, Height: MobColliderHeight},
+		Bounds:    rl.Rectangle{X: 0, Y: 0, Width: data.ColliderWidth, Height: data.ColliderHeight},
 		IsTrigger: false,
 		Layer:     "enemy",
 	})
 
 	physicsStore.Add(mob.ID, &components.PhysicsComponent{
-		Gravity:    game.Gravity,
-		JumpForce:  0,
-		MoveSpeed:  MobMoveSpeed,
-		IsOnGround: false,
+		Gravity:            game.Gravity,
+		JumpForce:          0,
+		MoveSpeed:          data.MoveSpeed,
+		IsOnGround:         false,
+		MaxFallSpeed:       MobMaxFallSpeed,
+		GroundDeceleration: MobDeceleration,
+	})
+
+	healthStore.Add(mob.ID, &components.HealthComponent{
+		Current:        MobHealthMax,
+		Max:            MobHealthMax,
+		HazardCooldown: MobHazardCooldown,
 	})
 
 	aiStore.Add(mob.ID, &components.AIComponent{
 		Behavior:   components.AIPatrol,
 		PatrolPath: []rl.Vector2{{X: x, Y: y}, {X: x - MobPatrolDistance, Y: y}},
 		PathIndex:  0,
+		SightRange: MobSightRange,
 	})
 
 	return mob
 }
 
+// SpawnCamera creates the camera entity following target. The view is kept
+// inside the level bounds, grown to at least the screen so levels that fit
+// the window do not scroll, and the map's camera zones take over inside them.
+func SpawnCamera(world *ecs.World, game *Game, target *ecs.Entity, levelMap LevelMap) *ecs.Entity {
+	cameraStore := ecs.RegisterStore[*components.CameraComponent](world.Components)
+
+	tileset := game.Terrain()
+	levelBounds := levelMap.Bounds(tileset.TileWidth, tileset.TileHeight)
+
+	screen := rl.Rectangle{X: 0, Y: 0, Width: float32(game.ScreenWidth), Height: float32(game.ScreenHeight)}
+	bounds := screen
+	if levelBounds.Width > 0 && levelBounds.Height > 0 {
+		bounds = unionRect(screen, levelBounds)
+	}
+
+	camera := world.CreateEntity("camera")
+	cameraStore.Add(camera.ID, &components.CameraComponent{
+		Camera: rl.Camera2D{
+			Offset: rl.Vector2{X: screen.Width / 2, Y: screen.Height / 2},
+			Target: rl.Vector2{X: screen.Width / 2, Y: screen.Height / 2},
+			Zoom:   1,
+		},
+		TargetID:       uint32(target.ID),
+		DeadzoneWidth:  CameraDeadzoneWidth,
+		DeadzoneHeight: CameraDeadzoneHeight,
+		Smoothing:      CameraSmoothing,
+		LookAhead:      CameraLookAhead,
+		LookAheadSpeed: CameraLookAheadSpeed,
+		Bounds:         bounds,
+		Zones:          levelMap.ToCameraZones(),
+		ZoneBlendTime:  CameraZoneBlendTime,
+		TraumaDecay:    CameraTraumaDecay,
+		ShakeOffset:    CameraShakeOffset,
+		ShakeAngle:     CameraShakeAngle,
+		ShakeFrequency: CameraShakeFrequency,
+		DamageTrauma:   CameraDamageTrauma,
+		LandTrauma:     CameraLandTrauma,
+		LandMinSpeed:   CameraLandMinSpeed,
+		LandFullSpeed:  CameraLandFullSpeed,
+		DefaultZoom:    1,
+		ActiveZone:     -1,
+		Snap:           true,
+	})
+
+	return camera
+}
+
+// SpawnDebugDraw creates the entity holding the debug overlay's shape
+// queue, on if the game's DebugOverlay setting is.
+func SpawnDebugDraw(world *ecs.World, game *Game) *ecs.Entity {
+	debugStore := ecs.RegisterStore[*components.DebugDrawComponent](world.Components)
+
+	debug := world.CreateEntity("debug")
+	debugStore.Add(debug.ID, &components.DebugDrawComponent{Enabled: game.DebugOverlay})
+
+	return debug
+}
+
+// unionRect returns the smallest rectangle containing a and b.
+func unionRect(a, b rl.Rectangle) rl.Rectangle {
+	left := min(a.X, b.X)
+	top := min(a.Y, b.Y)
+	right := max(a.X+a.Width, b.X+b.Width)
+	bottom := max(a.Y+a.Height, b.Y+b.Height)
+	return rl.Rectangle{X: left, Y: top, Width: right - left, Height: bottom - top}
+}
+
 // ResetPlayerPosition resets the player's position and state.
 func ResetPlayerPosition(world *ecs.World) {
 	transformStore, ok := ecs.GetStore[*components.TransformComponent](world.Components)
@@ -145,6 +315,11 @@ func ResetPlayerPosition(world *ecs.World) {
 		return
 	}
 	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
+	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
+	abilitiesStore, _ := ecs.GetStore[*components.AbilitiesComponent](world.Components)
+	meleeStore, _ := ecs.GetStore[*components.MeleeComponent](world.Components)
+	animatorStore, _ := ecs.GetStore[*components.AnimatorComponent](world.Components)
+	cameraStore, _ := ecs.GetStore[*components.CameraComponent](world.Components)
 
 	for _, entity := range world.GetEntitiesWithTag("player") {
 		if transform, ok := transformStore.Get(entity.ID); ok {
@@ -155,7 +330,50 @@ func ResetPlayerPosition(world *ecs.World) {
 		if physicsStore != nil {
 			if physics, ok := physicsStore.Get(entity.ID); ok {
 				physics.IsOnGround = false
+				physics.IsJumping = false
+				physics.CoyoteTimer = 0
+				physics.JumpBufferTimer = 0
+				physics.IsTurningAround = false
+				physics.DropThroughTimer = 0
+				physics.IsCrouching = false
+				physics.CrouchTransitionTimer = 0
+			}
+		}
+		if abilitiesStore != nil {
+			if abilities, ok := abilitiesStore.Get(entity.ID); ok {
+				abilities.Action = components.ActionNone
+				abilities.ActionTimer = 0
+				abilities.RollInvulnerable = false
+				abilities.IsWallSliding = false
+				abilities.WallJumpLockTimer = 0
+			}
+		}
+		if colliderStore != nil {
+			if collider, ok := colliderStore.Get(entity.ID); ok && collider.IsShrunk() {
+				collider.Restore()
+			}
+		}
+		if meleeStore != nil {
+			if melee, ok := meleeStore.Get(entity.ID); ok {
+				melee.Current = nil
+				melee.ComboIndex = -1
+				melee.ComboQueued = false
+				melee.ComboWindowTimer = 0
+			}
+		}
+		if animatorStore != nil {
+			if animator, ok := animatorStore.Get(entity.ID); ok {
+				animator.Current = ""
+				animator.Finished = false
 			}
 		}
 	}
+
+	// Cut straight to the respawned player instead of panning across
+	if cameraStore != nil {
+		for _, id := range cameraStore.All() {
+			camera, _ := cameraStore.Get(id)
+			camera.Snap = true
+		}
+	}
 }
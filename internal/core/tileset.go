@@ -0,0 +1,303 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"fire/internal/components"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TilesetJSON is a tileset file: an atlas texture (by key in the asset
+// manifest) cut into a grid, and the tiles maps can place, by ID.
+type TilesetJSON struct {
+	Texture    string        `json:"texture"`
+	GridWidth  int32         `json:"gridWidth"`  // Atlas cell size in pixels
+	GridHeight int32         `json:"gridHeight"` // Atlas cell size in pixels
+	TileWidth  float32       `json:"tileWidth"`  // Map tile size in world units
+	TileHeight float32       `json:"tileHeight"` // Map tile size in world units
+	Tiles      []TileDefJSON `json:"tiles"`
+}
+
+// TileDefJSON is one tile of a tileset: the atlas cells it is drawn from and
+// its gameplay properties.
+type TileDefJSON struct {
+	ID       int32               `json:"id"` // components.TileType used in maps
+	Name     string              `json:"name"`
+	Column   int32               `json:"column"`
+	Row      int32               `json:"row"`
+	Columns  int32               `json:"columns,omitempty"`  // Width in cells, defaults to 1
+	Rows     int32               `json:"rows,omitempty"`     // Height in cells, defaults to 1
+	Solid    *bool               `json:"solid,omitempty"`    // Defaults to true
+	Friction float32             `json:"friction,omitempty"` // Defaults to 1
+	Damage   int                 `json:"damage,omitempty"`   // Health lost on contact
+	Tint     []uint8             `json:"tint,omitempty"`     // RGB or RGBA, defaults to white
+	Slopes   map[string]RectJSON `json:"slopes,omitempty"`   // Atlas pieces for slope shapes, by name
+	Autotile *AutotileJSON       `json:"autotile,omitempty"` // Pieces picked by neighbours
+
+	// Name of another tile whose autotile rules this one shares, for
+	// variants drawn from the same pieces (e.g. tinted ice over stone)
+	AutotileFrom string `json:"autotileFrom,omitempty"`
+}
+
+// AutotileJSON picks a tile's atlas piece from which of its neighbours hold
+// the same tile type.
+type AutotileJSON struct {
+	Mode   string              `json:"mode"` // "4bit" (edges) or "8bit" (edges and corners)
+	Pieces []AutotilePieceJSON `json:"pieces"`
+}
+
+// AutotilePieceJSON is the atlas piece drawn when exactly the listed
+// neighbours ("n", "ne", "e", "se", "s", "sw", "w", "nw") are the same tile
+// type.
+type AutotilePieceJSON struct {
+	Neighbours []string `json:"neighbours"`
+	RectJSON
+}
+
+// RectJSON is a rectangle in atlas pixels.
+type RectJSON struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+// validColorJSON reports whether a colour is empty, RGB or RGBA.
+func validColorJSON(values []uint8) bool {
+	return len(values) == 0 || len(values) == 3 || len(values) == 4
+}
+
+// colorFromJSON converts an RGB or RGBA colour, or returns fallback when it
+// is empty.
+func colorFromJSON(values []uint8, fallback rl.Color) rl.Color {
+	if len(values) < 3 {
+		return fallback
+	}
+	color := rl.Color{R: values[0], G: values[1], B: values[2], A: 255}
+	if len(values) == 4 {
+		color.A = values[3]
+	}
+	return color
+}
+
+// Rectangle converts the rectangle for drawing.
+func (r RectJSON) Rectangle() rl.Rectangle {
+	return rl.Rectangle{X: r.X, Y: r.Y, Width: r.Width, Height: r.Height}
+}
+
+// Tileset is a loaded tileset.
+type Tileset struct {
+	Texture    rl.Texture2D
+	TileWidth  float32
+	TileHeight float32
+	Tiles      map[components.TileType]TileDef
+}
+
+// TileDef is a tile of a loaded tileset.
+type TileDef struct {
+	Name     string
+	Source   rl.Rectangle
+	Slopes   map[components.SlopeShape]rl.Rectangle
+	Autotile *Autotile // Nil when the tile always uses Source
+	Solid    bool
+	Friction float32
+	Damage   int
+	Tint     rl.Color
+}
+
+// LoadTilesetJSON loads and validates a tileset file.
+func LoadTilesetJSON(path string) (TilesetJSON, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TilesetJSON{}, err
+	}
+
+	var tileset TilesetJSON
+	if err := json.Unmarshal(data, &tileset); err != nil {
+		return TilesetJSON{}, err
+	}
+
+	if tileset.GridWidth <= 0 || tileset.GridHeight <= 0 {
+		return TilesetJSON{}, fmt.Errorf("%s: grid size must be positive", path)
+	}
+	if tileset.TileWidth <= 0 || tileset.TileHeight <= 0 {
+		return TilesetJSON{}, fmt.Errorf("%s: tile size must be positive", path)
+	}
+	ids := make(map[int32]bool, len(tileset.Tiles))
+	for _, tile := range tileset.Tiles {
+		if ids[tile.ID] {
+			return TilesetJSON{}, fmt.Errorf("%s: tile id %d declared twice", path, tile.ID)
+		}
+		ids[tile.ID] = true
+		if !validColorJSON(tile.Tint) {
+			return TilesetJSON{}, fmt.Errorf("%s: tile %q: tint needs 3 or 4 components", path, tile.Name)
+		}
+		for name := range tile.Slopes {
+			if _, ok := components.ParseSlopeShape(name); !ok {
+				return TilesetJSON{}, fmt.Errorf("%s: tile %q: unknown slope %q", path, tile.Name, name)
+			}
+		}
+		if tile.Autotile != nil && tile.AutotileFrom != "" {
+			return TilesetJSON{}, fmt.Errorf("%s: tile %q: has both autotile and autotileFrom", path, tile.Name)
+		}
+		if tile.Autotile != nil {
+			if err := validateAutotile(*tile.Autotile); err != nil {
+				return TilesetJSON{}, fmt.Errorf("%s: tile %q: %w", path, tile.Name, err)
+			}
+		}
+	}
+	if err := resolveAutotileFrom(tileset.Tiles); err != nil {
+		return TilesetJSON{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return tileset, nil
+}
+
+// resolveAutotileFrom gives each tile with autotileFrom the autotile rules
+// of the tile it names. The named tile must be the only one of that name and
+// declare its own rules.
+func resolveAutotileFrom(tiles []TileDefJSON) error {
+	byName := make(map[string]int, len(tiles))
+	for i, tile := range tiles {
+		if _, ok := byName[tile.Name]; ok {
+			byName[tile.Name] = -1
+			continue
+		}
+		byName[tile.Name] = i
+	}
+	for i, tile := range tiles {
+		if tile.AutotileFrom == "" {
+			continue
+		}
+		source, ok := byName[tile.AutotileFrom]
+		switch {
+		case !ok:
+			return fmt.Errorf("tile %q: autotileFrom names unknown tile %q", tile.Name, tile.AutotileFrom)
+		case source < 0:
+			return fmt.Errorf("tile %q: autotileFrom names %q, which more than one tile uses", tile.Name, tile.AutotileFrom)
+		case tiles[source].Autotile == nil:
+			return fmt.Errorf("tile %q: autotileFrom names %q, which has no autotile rules", tile.Name, tile.AutotileFrom)
+		}
+		tiles[i].Autotile = tiles[source].Autotile
+	}
+	return nil
+}
+
+// validateAutotile checks an autotile's mode, that each neighbour set has
+// one piece, and that every combination of edge neighbours has a piece, as
+// blob tiles fall back to those.
+func validateAutotile(autotile AutotileJSON) error {
+	mode, ok := autotileModes[autotile.Mode]
+	if !ok {
+		return fmt.Errorf("unknown autotile mode %q", autotile.Mode)
+	}
+	masks := make(map[uint8]bool, len(autotile.Pieces))
+	for _, piece := range autotile.Pieces {
+		mask, err := parseNeighbours(piece.Neighbours, mode)
+		if err != nil {
+			return err
+		}
+		if masks[mask] {
+			return fmt.Errorf("autotile neighbours %v declared twice", piece.Neighbours)
+		}
+		masks[mask] = true
+	}
+	for mask := 0; mask < 256; mask++ {
+		edges := uint8(mask)
+		if edges&^neighbourEdges == 0 && !masks[edges] {
+			return fmt.Errorf("autotile has no piece for neighbours %v", neighbourList(edges))
+		}
+	}
+	return nil
+}
+
+// newTileset builds a loaded tileset drawn from texture.
+func newTileset(data TilesetJSON, texture rl.Texture2D) *Tileset {
+	tileset := &Tileset{
+		Texture:    texture,
+		TileWidth:  data.TileWidth,
+		TileHeight: data.TileHeight,
+		Tiles:      make(map[components.TileType]TileDef, len(data.Tiles)),
+	}
+
+	for _, tile := range data.Tiles {
+		columns, rows := max(tile.Columns, 1), max(tile.Rows, 1)
+		def := TileDef{
+			Name: tile.Name,
+			Source: rl.Rectangle{
+				X:      float32(tile.Column * data.GridWidth),
+				Y:      float32(tile.Row * data.GridHeight),
+				Width:  float32(columns * data.GridWidth),
+				Height: float32(rows * data.GridHeight),
+			},
+			Slopes:   make(map[components.SlopeShape]rl.Rectangle, len(tile.Slopes)),
+			Solid:    tile.Solid == nil || *tile.Solid,
+			Friction: tile.Friction,
+			Damage:   tile.Damage,
+			Tint:     colorFromJSON(tile.Tint, rl.White),
+		}
+		if def.Friction <= 0 {
+			def.Friction = 1
+		}
+		for name, rect := range tile.Slopes {
+			shape, _ := components.ParseSlopeShape(name)
+			def.Slopes[shape] = rect.Rectangle()
+		}
+		if tile.Autotile != nil {
+			mode := autotileModes[tile.Autotile.Mode]
+			def.Autotile = &Autotile{Mode: mode, Pieces: make(map[uint8]rl.Rectangle, len(tile.Autotile.Pieces))}
+			for _, piece := range tile.Autotile.Pieces {
+				mask, _ := parseNeighbours(piece.Neighbours, mode)
+				def.Autotile.Pieces[mask] = piece.Rectangle()
+			}
+		}
+		tileset.Tiles[components.TileType(tile.ID)] = def
+	}
+	return tileset
+}
+
+// Tile returns the definition of a tile type. Unknown types get a solid,
+// invisible tile so maps using them still play.
+func (t *Tileset) Tile(tileType components.TileType) TileDef {
+	if def, ok := t.Tiles[tileType]; ok {
+		return def
+	}
+	return TileDef{Solid: true, Friction: 1, Tint: rl.Blank}
+}
+
+// TileTypes returns the tile types of the tileset in ID order.
+func (t *Tileset) TileTypes() []components.TileType {
+	types := make([]components.TileType, 0, len(t.Tiles))
+	for tileType := range t.Tiles {
+		types = append(types, tileType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// SourceFor returns the atlas piece drawn for a tile of the given shape with
+// the given neighbour mask (see AutotileGrid): its slope piece if it has
+// one, else its autotile piece for the neighbours, else the full tile.
+func (d TileDef) SourceFor(shape components.SlopeShape, neighbours uint8) rl.Rectangle {
+	if source, ok := d.Slopes[shape]; ok {
+		return source
+	}
+	if d.Autotile != nil {
+		if source, ok := d.Autotile.Source(neighbours); ok {
+			return source
+		}
+	}
+	return d.Source
+}
+
+// Terrain returns the level tileset, or an empty one of the default tile
+// size if it is not loaded.
+func (g *Game) Terrain() *Tileset {
+	if tileset := g.Assets.Tileset(AssetTerrain); tileset != nil {
+		return tileset
+	}
+	return &Tileset{TileWidth: DefaultTileWidth, TileHeight: DefaultTileHeight}
+}
@@ -0,0 +1,215 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fire/internal/aseprite"
+	"fire/internal/components"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestShippedTilesetLoads(t *testing.T) {
+	manifest, err := LoadAssetManifest(ResourcePath(DefaultAssetManifestPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTilesetJSON(ResourcePath(manifest.Tilesets[AssetTerrain])); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewTileset(t *testing.T) {
+	notSolid := false
+	tileset := newTileset(TilesetJSON{
+		GridWidth:  16,
+		GridHeight: 16,
+		TileWidth:  32,
+		TileHeight: 32,
+		Tiles: []TileDefJSON{
+			{ID: 1, Name: "grass", Column: 2, Row: 1},
+			{ID: 2, Name: "sign", Column: 0, Row: 3, Columns: 2, Rows: 3, Solid: &notSolid, Friction: 0.2, Damage: 1, Tint: []uint8{10, 20, 30, 40}},
+			{ID: 3, Name: "ramp", Slopes: map[string]RectJSON{"45up": {X: 64, Width: 16, Height: 16}}},
+		},
+	}, rl.Texture2D{})
+
+	grass := tileset.Tile(1)
+	if want := (rl.Rectangle{X: 32, Y: 16, Width: 16, Height: 16}); grass.Source != want {
+		t.Errorf("grass source %v, want %v", grass.Source, want)
+	}
+	if !grass.Solid || grass.Friction != 1 || grass.Tint != rl.White {
+		t.Errorf("grass defaults: solid %v, friction %v, tint %v", grass.Solid, grass.Friction, grass.Tint)
+	}
+
+	sign := tileset.Tile(2)
+	if want := (rl.Rectangle{X: 0, Y: 48, Width: 32, Height: 48}); sign.Source != want {
+		t.Errorf("sign source %v, want %v", sign.Source, want)
+	}
+	if sign.Solid || sign.Friction != 0.2 || sign.Damage != 1 || sign.Tint != (rl.Color{R: 10, G: 20, B: 30, A: 40}) {
+		t.Errorf("sign properties %+v", sign)
+	}
+
+	ramp := tileset.Tile(3)
+	slope, _ := components.ParseSlopeShape("45up")
+	if got := ramp.SourceFor(slope, 0); got != (rl.Rectangle{X: 64, Width: 16, Height: 16}) {
+		t.Errorf("ramp slope piece %v", got)
+	}
+	if got := ramp.SourceFor(components.SlopeNone, 0); got != ramp.Source {
+		t.Errorf("ramp without a slope piece drew %v, want the full tile", got)
+	}
+
+	if unknown := tileset.Tile(99); !unknown.Solid || unknown.Tint != rl.Blank {
+		t.Errorf("unknown tile %+v, want solid and invisible", unknown)
+	}
+	if types := tileset.TileTypes(); len(types) != 3 || types[0] != 1 || types[2] != 3 {
+		t.Errorf("tile types %v, want [1 2 3]", types)
+	}
+}
+
+func TestLoadTilesetJSONRejects(t *testing.T) {
+	tests := map[string]string{
+		"no grid":                    `{"gridWidth": 0, "gridHeight": 16, "tileWidth": 32, "tileHeight": 32}`,
+		"no tile size":               `{"gridWidth": 16, "gridHeight": 16, "tileWidth": 0, "tileHeight": 32}`,
+		"duplicate id":               `{"gridWidth": 16, "gridHeight": 16, "tileWidth": 32, "tileHeight": 32, "tiles": [{"id": 1}, {"id": 1}]}`,
+		"bad tint":                   `{"gridWidth": 16, "gridHeight": 16, "tileWidth": 32, "tileHeight": 32, "tiles": [{"id": 1, "tint": [1, 2]}]}`,
+		"unknown slope":              `{"gridWidth": 16, "gridHeight": 16, "tileWidth": 32, "tileHeight": 32, "tiles": [{"id": 1, "slopes": {"30up": {}}}]}`,
+		"autotile and autotileFrom":  `{"gridWidth": 16, "gridHeight": 16, "tileWidth": 32, "tileHeight": 32, "tiles": [{"id": 1, "autotile": {"mode": "4bit"}, "autotileFrom": "stone"}]}`,
+		"autotileFrom unknown tile":  `{"gridWidth": 16, "gridHeight": 16, "tileWidth": 32, "tileHeight": 32, "tiles": [{"id": 1, "name": "ice", "autotileFrom": "stone"}]}`,
+		"autotileFrom without rules": `{"gridWidth": 16, "gridHeight": 16, "tileWidth": 32, "tileHeight": 32, "tiles": [{"id": 1, "name": "stone"}, {"id": 2, "name": "ice", "autotileFrom": "stone"}]}`,
+		"autotileFrom ambiguous":     `{"gridWidth": 16, "gridHeight": 16, "tileWidth": 32, "tileHeight": 32, "tiles": [{"id": 1, "name": "stone"}, {"id": 2, "name": "stone"}, {"id": 3, "name": "ice", "autotileFrom": "stone"}]}`,
+		"not json":                   `{`,
+	}
+	dir := t.TempDir()
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "tileset.json")
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadTilesetJSON(path); err == nil {
+				t.Error("loaded")
+			}
+		})
+	}
+}
+
+func TestLoadTilesetJSONAutotileFrom(t *testing.T) {
+	pieces := make([]AutotilePieceJSON, 0, 16)
+	for mask := 0; mask < 256; mask++ {
+		if edges := uint8(mask); edges&^neighbourEdges == 0 {
+			pieces = append(pieces, AutotilePieceJSON{Neighbours: neighbourList(edges), RectJSON: RectJSON{X: float32(mask) * 16, Width: 16, Height: 16}})
+		}
+	}
+	data, err := json.Marshal(TilesetJSON{
+		GridWidth:  16,
+		GridHeight: 16,
+		TileWidth:  32,
+		TileHeight: 32,
+		Tiles: []TileDefJSON{
+			{ID: 1, Name: "stone", Autotile: &AutotileJSON{Mode: "4bit", Pieces: pieces}},
+			{ID: 5, Name: "ice", Friction: 0.15, AutotileFrom: "stone"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "tileset.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadTilesetJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tileset := newTileset(loaded, rl.Texture2D{})
+	stone, ice := tileset.Tile(1), tileset.Tile(5)
+	if ice.Autotile == nil {
+		t.Fatal("ice has no autotile rules")
+	}
+	for _, piece := range pieces {
+		mask, _ := parseNeighbours(piece.Neighbours, AutotileEdges)
+		if got, want := ice.SourceFor(components.SlopeNone, mask), stone.SourceFor(components.SlopeNone, mask); got != want || got != piece.Rectangle() {
+			t.Errorf("neighbours %v: ice drew %v, stone %v", piece.Neighbours, got, want)
+		}
+	}
+	if ice.Friction != 0.15 {
+		t.Errorf("ice friction %v, want its own 0.15", ice.Friction)
+	}
+}
+
+func TestTerrainAtlasMatchesSource(t *testing.T) {
+	manifest, err := LoadAssetManifest(ResourcePath(DefaultAssetManifestPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tileset, err := LoadTilesetJSON(ResourcePath(manifest.Tilesets[AssetTerrain]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	atlasPath := ResourcePath(manifest.Textures[tileset.Texture])
+	source, err := aseprite.Load(strings.TrimSuffix(atlasPath, filepath.Ext(atlasPath)) + ".aseprite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(atlasPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	atlas, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The atlas is an export of its source; pieces painted into the PNG
+	// alone would be lost on the next export
+	sourceImage := source.Frames[0].Image
+	if atlas.Bounds() != sourceImage.Bounds() {
+		t.Fatalf("atlas is %v, source is %v", atlas.Bounds(), sourceImage.Bounds())
+	}
+	for y := 0; y < source.Height; y++ {
+		for x := 0; x < source.Width; x++ {
+			want := sourceImage.NRGBAAt(x, y)
+			got := color.NRGBAModel.Convert(atlas.At(x, y)).(color.NRGBA)
+			if got != want && (got.A != 0 || want.A != 0) {
+				t.Fatalf("atlas pixel (%d, %d) is %v, source has %v", x, y, got, want)
+			}
+		}
+	}
+
+	// Every piece the tileset draws has something in it
+	opaque := func(rect RectJSON) bool {
+		for y := int(rect.Y); y < int(rect.Y+rect.Height); y++ {
+			for x := int(rect.X); x < int(rect.X+rect.Width); x++ {
+				if sourceImage.NRGBAAt(x, y).A != 0 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, tile := range tileset.Tiles {
+		pieces := make(map[string]RectJSON)
+		for name, rect := range tile.Slopes {
+			pieces["slope "+name] = rect
+		}
+		if tile.Autotile != nil {
+			for _, piece := range tile.Autotile.Pieces {
+				pieces[fmt.Sprintf("autotile %v", piece.Neighbours)] = piece.RectJSON
+			}
+		}
+		for name, rect := range pieces {
+			if !opaque(rect) {
+				t.Errorf("%s %s piece %+v is blank in the atlas source", tile.Name, name, rect)
+			}
+		}
+	}
+}
@@ -39,6 +39,11 @@ func (cs *ComponentStore[T]) Has(id EntityID) bool {
 	return ok
 }
 
+// Len returns the number of entities that have this component.
+func (cs *ComponentStore[T]) Len() int {
+	return len(cs.components)
+}
+
 // All returns all entity IDs that have this component.
 func (cs *ComponentStore[T]) All() []EntityID {
 	ids := make([]EntityID, 0, len(cs.components))
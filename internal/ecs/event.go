@@ -14,8 +14,12 @@ const (
 	EventDamage
 	// EventDeath is fired when an entity's health reaches zero.
 	EventDeath
-	// EventCoinCollected is fired when a coin is collected.
+	// EventCoinCollected is fired when a coin is collected; Source is the collector.
 	EventCoinCollected
+	// EventAnimationFinished is fired when a one-shot animation state finishes.
+	EventAnimationFinished
+	// EventAnimationFrame is fired when an animation enters a frame that has a frame event.
+	EventAnimationFrame
 )
 
 // Event represents a game event with source, target, and data.
@@ -31,8 +35,9 @@ type EventHandler func(Event)
 
 // EventBus manages event publication and subscription.
 type EventBus struct {
-	handlers map[EventType][]EventHandler
-	queue    []Event
+	handlers   map[EventType][]EventHandler
+	queue      []Event
+	processing []Event // Queue being dispatched, swapped with queue by Process
 }
 
 // NewEventBus creates a new EventBus.
@@ -53,16 +58,18 @@ func (eb *EventBus) Publish(event Event) {
 	eb.queue = append(eb.queue, event)
 }
 
-// Process processes all queued events and clears the queue.
+// Process processes all queued events and clears the queue. Events that
+// handlers publish while it runs are queued for the next call.
 func (eb *EventBus) Process() {
-	for _, event := range eb.queue {
+	eb.processing, eb.queue = eb.queue, eb.processing[:0]
+	for _, event := range eb.processing {
 		if handlers, ok := eb.handlers[event.Type]; ok {
 			for _, handler := range handlers {
 				handler(event)
 			}
 		}
 	}
-	eb.queue = eb.queue[:0]
+	eb.processing = eb.processing[:0]
 }
 
 // PublishImmediate publishes and immediately processes an event.
@@ -0,0 +1,61 @@
+package ecs
+
+import "testing"
+
+func TestEventBusProcess(t *testing.T) {
+	bus := NewEventBus()
+	var got []EventType
+	bus.Subscribe(EventDamage, func(event Event) {
+		got = append(got, event.Type)
+		// A handler reacting with a new event, as a death after damage
+		bus.Publish(Event{Type: EventDeath, Target: event.Target})
+	})
+	bus.Subscribe(EventDeath, func(event Event) {
+		got = append(got, event.Type)
+	})
+
+	bus.Publish(Event{Type: EventDamage, Target: 7})
+	bus.Process()
+	if len(got) != 1 || got[0] != EventDamage {
+		t.Fatalf("first Process dispatched %v, want [damage]", got)
+	}
+
+	bus.Process()
+	if len(got) != 2 || got[1] != EventDeath {
+		t.Fatalf("event published during Process was lost: dispatched %v", got)
+	}
+
+	bus.Process()
+	if len(got) != 2 {
+		t.Errorf("events dispatched twice: %v", got)
+	}
+}
+
+func TestEventBusPublishImmediate(t *testing.T) {
+	bus := NewEventBus()
+	calls := 0
+	bus.Subscribe(EventPlayerJump, func(Event) { calls++ })
+
+	bus.PublishImmediate(Event{Type: EventPlayerJump})
+	if calls != 1 {
+		t.Errorf("PublishImmediate ran %d handlers, want 1", calls)
+	}
+	bus.Process()
+	if calls != 1 {
+		t.Error("PublishImmediate also queued the event")
+	}
+}
+
+func TestEventBusClear(t *testing.T) {
+	bus := NewEventBus()
+	calls := 0
+	bus.Subscribe(EventCollision, func(Event) { calls++ })
+	bus.Publish(Event{Type: EventCollision})
+
+	bus.Clear()
+	bus.Publish(Event{Type: EventCollision})
+	bus.Process()
+	if calls != 0 {
+		t.Errorf("handlers ran %d times after Clear", calls)
+	}
+}
@@ -5,12 +5,22 @@ type System interface {
 	Update(world *World, dt float32)
 }
 
+// Unloader is implemented by systems that hold resources (such as GPU
+// textures) which must be freed when their world is discarded.
+type Unloader interface {
+	Unload()
+}
+
 // World is the main container for all entities, components, and systems.
 type World struct {
 	Entities   *EntityManager
 	Components *ComponentRegistry
 	Systems    []System
 	Events     *EventBus
+
+	// HitStop freezes the simulation for this many seconds: systems keep
+	// running (so the frame is still drawn) but receive a zero dt.
+	HitStop float32
 }
 
 // NewWorld creates a new World instance.
@@ -28,11 +38,35 @@ func (w *World) AddSystem(s System) {
 	w.Systems = append(w.Systems, s)
 }
 
-// Update runs all systems in order.
+// Update runs all systems in order, then processes the events they queued.
 func (w *World) Update(dt float32) {
+	if w.HitStop > 0 {
+		w.HitStop -= dt
+		dt = 0
+	}
+
 	for _, s := range w.Systems {
 		s.Update(w, dt)
 	}
+
+	w.Events.Process()
+}
+
+// Unload frees the resources held by the world's systems.
+func (w *World) Unload() {
+	for _, s := range w.Systems {
+		if unloader, ok := s.(Unloader); ok {
+			unloader.Unload()
+		}
+	}
+}
+
+// FreezeFor starts a hit-stop of the given length. Overlapping hit-stops do
+// not stack; the longer one wins.
+func (w *World) FreezeFor(seconds float32) {
+	if seconds > w.HitStop {
+		w.HitStop = seconds
+	}
 }
 
 // CreateEntity creates a new entity with the given tags.
@@ -0,0 +1,197 @@
+package systems
+
+import (
+	"fire/internal/components"
+	"fire/internal/ecs"
+)
+
+// AbilitySystem starts, runs and ends timed movement abilities (dash, roll
+// and slide). While an ability is active it owns the entity's horizontal
+// velocity; PhysicsSystem skips normal movement and jumping.
+type AbilitySystem struct {
+	collision *CollisionSystem // Finds ceilings over a roll or slide, if set
+}
+
+// NewAbilitySystem creates a new AbilitySystem. collision should be the one
+// the PhysicsSystem resolves with, so its broadphase is shared; nil checks
+// every solid instead.
+func NewAbilitySystem(collision *CollisionSystem) *AbilitySystem {
+	return &AbilitySystem{collision: collision}
+}
+
+// Update advances ability timers and cooldowns for all entities with
+// AbilitiesComponent, and starts new abilities from input.
+func (s *AbilitySystem) Update(world *ecs.World, dt float32) {
+	abilitiesStore, ok1 := ecs.GetStore[*components.AbilitiesComponent](world.Components)
+	transformStore, ok2 := ecs.GetStore[*components.TransformComponent](world.Components)
+	inputStore, _ := ecs.GetStore[*components.InputComponent](world.Components)
+	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
+	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
+	healthStore, _ := ecs.GetStore[*components.HealthComponent](world.Components)
+
+	if !ok1 || !ok2 || dt <= 0 {
+		return
+	}
+
+	if dt > MaxPhysicsStep {
+		dt = MaxPhysicsStep
+	}
+
+	for _, id := range abilitiesStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+
+		transform, ok := transformStore.Get(id)
+		if !ok {
+			continue
+		}
+		abilities, _ := abilitiesStore.Get(id)
+
+		var collider *components.ColliderComponent
+		if colliderStore != nil {
+			collider, _ = colliderStore.Get(id)
+		}
+
+		tickCooldown(&abilities.DashCooldownTimer, dt)
+		tickCooldown(&abilities.RollCooldownTimer, dt)
+		tickCooldown(&abilities.SlideCooldownTimer, dt)
+
+		if abilities.Action == components.ActionNone && inputStore != nil && !isDead(healthStore, id) {
+			if input, ok := inputStore.Get(id); ok {
+				onGround := false
+				if physicsStore != nil {
+					if physics, ok := physicsStore.Get(id); ok {
+						onGround = physics.IsOnGround
+					}
+				}
+				startAction(abilities, transform, input, collider, onGround)
+			}
+		}
+
+		if abilities.Action != components.ActionNone {
+			runAction(world, s.collision, abilities, transform, collider, dt)
+		}
+	}
+}
+
+// startAction begins the first requested ability that is unlocked, off
+// cooldown and allowed in the current state. Roll and slide need the ground.
+func startAction(abilities *components.AbilitiesComponent, transform *components.TransformComponent, input *components.InputComponent, collider *components.ColliderComponent, onGround bool) {
+	switch {
+	case input.DashPressed && abilities.Dash && abilities.DashCooldownTimer <= 0 && abilities.DashDuration > 0:
+		abilities.Action = components.ActionDash
+		abilities.ActionTimer = abilities.DashDuration
+		abilities.DashCooldownTimer = abilities.DashCooldown
+		abilities.DashEntrySpeed = transform.Velocity.X
+
+	case input.RollPressed && abilities.Roll && abilities.RollCooldownTimer <= 0 && onGround:
+		abilities.Action = components.ActionRoll
+		abilities.ActionTimer = abilities.RollDuration
+		abilities.RollCooldownTimer = abilities.RollCooldown
+		if collider != nil && abilities.RollHeight > 0 {
+			collider.SetHeight(abilities.RollHeight)
+		}
+		abilities.RollInvulnerable = true
+
+	case input.SlidePressed && abilities.Slide && abilities.SlideCooldownTimer <= 0 && onGround:
+		abilities.Action = components.ActionSlide
+		abilities.ActionTimer = abilities.SlideDuration
+		abilities.SlideCooldownTimer = abilities.SlideCooldown
+		if collider != nil && abilities.SlideHeight > 0 {
+			collider.SetHeight(abilities.SlideHeight)
+		}
+
+	default:
+		return
+	}
+
+	// Go in the held direction, or the facing direction when none is held
+	abilities.ActionDirection = -1
+	if input.MoveX > 0 || (input.MoveX == 0 && transform.FacingRight) {
+		abilities.ActionDirection = 1
+	}
+	abilities.IsWallSliding = false
+	abilities.WallJumpLockTimer = 0
+}
+
+// runAction drives the entity's velocity for the active ability. An ability
+// whose time ran out on the previous frame is ended first, so PhysicsSystem
+// integrates every frame of it.
+func runAction(world *ecs.World, collision *CollisionSystem, abilities *components.AbilitiesComponent, transform *components.TransformComponent, collider *components.ColliderComponent, dt float32) {
+	if abilities.ActionTimer <= 0 && endAction(world, collision, abilities, transform, collider) {
+		return
+	}
+
+	// Only cover the time left on the final frame so dash distance is exact
+	step := dt
+	if abilities.ActionTimer > 0 && abilities.ActionTimer < step {
+		step = abilities.ActionTimer
+	}
+
+	direction := abilities.ActionDirection
+	transform.FacingRight = direction > 0
+
+	switch abilities.Action {
+	case components.ActionDash:
+		speed := float32(0)
+		if abilities.DashDuration > 0 {
+			speed = abilities.DashDistance / abilities.DashDuration
+		}
+		transform.Velocity.X = direction * speed * step / dt
+		transform.Velocity.Y = 0
+	case components.ActionRoll:
+		transform.Velocity.X = direction * abilities.RollSpeed
+	case components.ActionSlide:
+		transform.Velocity.X = direction * abilities.SlideSpeed
+	}
+
+	abilities.ActionTimer -= dt
+	if abilities.ActionTimer < 0 {
+		abilities.ActionTimer = 0
+	}
+}
+
+// endAction finishes the active ability and restores the collider. A dash
+// hands back the horizontal velocity it started with, so none of its burst
+// is left for deceleration to bleed off. Roll and slide keep going while a
+// ceiling blocks the standing collider; it returns false in that case.
+func endAction(world *ecs.World, collision *CollisionSystem, abilities *components.AbilitiesComponent, transform *components.TransformComponent, collider *components.ColliderComponent) bool {
+	if collider != nil && collider.IsShrunk() {
+		if !hasHeadroom(world, collision, collider, transform.Position) {
+			return false
+		}
+		collider.Restore()
+	}
+
+	if abilities.Action == components.ActionRoll {
+		abilities.RollInvulnerable = false
+	}
+	if abilities.Action == components.ActionDash {
+		transform.Velocity.X = abilities.DashEntrySpeed
+	}
+	abilities.Action = components.ActionNone
+	return true
+}
+
+// tickCooldown counts a cooldown timer down to zero.
+func tickCooldown(timer *float32, dt float32) {
+	if *timer > 0 {
+		*timer -= dt
+		if *timer < 0 {
+			*timer = 0
+		}
+	}
+}
+
+// isRolling reports whether an entity is inside its roll i-frames. Hazards
+// and melee hits skip it, leaving HealthComponent.Invulnerable to whoever
+// else sets it.
+func isRolling(abilitiesStore *ecs.ComponentStore[*components.AbilitiesComponent], id ecs.EntityID) bool {
+	if abilitiesStore == nil {
+		return false
+	}
+	abilities, ok := abilitiesStore.Get(id)
+	return ok && abilities.RollInvulnerable
+}
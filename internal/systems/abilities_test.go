@@ -0,0 +1,186 @@
+package systems
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// newAbilityTestWorld adds an AbilitiesComponent with every ability
+// unlocked, a collider and health to the physics test entity.
+func newAbilityTestWorld() (*ecs.World, *components.TransformComponent, *components.AbilitiesComponent, *components.InputComponent, *components.ColliderComponent, *components.HealthComponent) {
+	world, transform, _, input := newPhysicsTestWorld()
+	id := world.GetEntitiesWithTag("player")[0].ID
+
+	abilities := &components.AbilitiesComponent{
+		Dash:          true,
+		DashDistance:  160,
+		DashDuration:  0.15,
+		DashCooldown:  0.6,
+		Roll:          true,
+		RollSpeed:     360,
+		RollDuration:  0.35,
+		RollCooldown:  0.5,
+		RollHeight:    20,
+		Slide:         true,
+		SlideSpeed:    420,
+		SlideDuration: 0.45,
+		SlideCooldown: 0.5,
+		SlideHeight:   16,
+	}
+	standing := rl.Rectangle{Width: 20, Height: 40}
+	collider := &components.ColliderComponent{Bounds: standing, StandingBounds: standing}
+	health := &components.HealthComponent{Current: 3, Max: 3}
+
+	ecs.RegisterStore[*components.AbilitiesComponent](world.Components).Add(id, abilities)
+	ecs.RegisterStore[*components.ColliderComponent](world.Components).Add(id, collider)
+	ecs.RegisterStore[*components.HealthComponent](world.Components).Add(id, health)
+	return world, transform, abilities, input, collider, health
+}
+
+func TestDashCoversItsDistance(t *testing.T) {
+	tests := []struct {
+		name       string
+		entrySpeed float32 // Running speed when the dash starts
+		moveX      float32 // Held during and after the dash
+	}{
+		{"from rest", 0, 0},
+		{"from a run", 240, 1},
+	}
+	for _, tt := range tests {
+		for _, rate := range testFrameRates {
+			t.Run(fmt.Sprintf("%s at %v Hz", tt.name, rate), func(t *testing.T) {
+				world, transform, abilities, input, _, _ := newAbilityTestWorld()
+				abilitySystem := NewAbilitySystem(nil)
+				physicsSystem := NewPhysicsSystem(nil, nil)
+				dt := 1 / rate
+
+				// Dash in mid-jump, where the jump and gravity would
+				// otherwise move the body vertically
+				id := world.GetEntitiesWithTag("player")[0].ID
+				physics, _ := ecs.RegisterStore[*components.PhysicsComponent](world.Components).Get(id)
+				physics.IsOnGround = false
+				transform.Velocity.Y = -200
+
+				transform.Velocity.X = tt.entrySpeed
+				input.MoveX = tt.moveX
+				input.DashPressed = true
+				dashFrames := 0
+				fellAfter := false
+				for frame := 0; frame < int(rate); frame++ {
+					abilitySystem.Update(world, dt)
+					input.DashPressed = false
+					dashing := abilities.Action == components.ActionDash
+					if dashing {
+						dashFrames++
+					}
+					physicsSystem.Update(world, dt)
+					if dashing && transform.Position.Y != 0 {
+						t.Fatalf("frame %d: dash moved vertically to %.2f", frame, transform.Position.Y)
+					}
+					if !dashing && dashFrames > 0 && frame == dashFrames {
+						fellAfter = transform.Position.Y > 0
+					}
+				}
+
+				// Whatever the dash leaves over is the running speed it
+				// started with; one second later the dash's 160 units are on
+				// top of the distance run at that speed. The fixed physics
+				// step may carry up to one step of the dash over.
+				if abilities.Action != components.ActionNone || transform.Velocity.X != tt.entrySpeed {
+					t.Fatalf("action %v moving %.2f after a second, want the dash over at %v", abilities.Action, transform.Velocity.X, tt.entrySpeed)
+				}
+				dashTime := float32(dashFrames) * dt
+				want := 160 + tt.entrySpeed*(1-dashTime)
+				tolerance := 160 / 0.15 * PhysicsStep
+				if math.Abs(float64(transform.Position.X-want)) > tolerance {
+					t.Errorf("travelled %.2f, want %.2f", transform.Position.X, want)
+				}
+				if !fellAfter {
+					t.Errorf("not falling the frame after the dash ended")
+				}
+			})
+		}
+	}
+}
+
+func TestRollShrinksAndProtects(t *testing.T) {
+	world, transform, abilities, input, collider, health := newAbilityTestWorld()
+	system := NewAbilitySystem(nil)
+	transform.FacingRight = false
+	// Invulnerability from elsewhere must outlive the roll
+	health.Invulnerable = true
+
+	input.RollPressed = true
+	system.Update(world, 0.01)
+	input.RollPressed = false
+
+	if abilities.Action != components.ActionRoll {
+		t.Fatalf("action %v, want roll", abilities.Action)
+	}
+	if collider.Bounds.Height != 20 || collider.Bounds.Y != 20 {
+		t.Errorf("rolling collider %v, want height 20 with the bottom kept", collider.Bounds)
+	}
+	if !abilities.RollInvulnerable {
+		t.Error("rolling is not invulnerable")
+	}
+	if transform.Velocity.X != -360 {
+		t.Errorf("roll velocity %v, want -360 in the facing direction", transform.Velocity.X)
+	}
+
+	for i := 0; i < 40; i++ {
+		system.Update(world, 0.01)
+	}
+	if abilities.Action != components.ActionNone {
+		t.Fatalf("roll still active after its duration")
+	}
+	if collider.IsShrunk() || abilities.RollInvulnerable {
+		t.Errorf("roll ended without restoring the collider (%v) or vulnerability", collider.Bounds)
+	}
+	if !health.Invulnerable {
+		t.Error("roll cleared invulnerability it did not set")
+	}
+}
+
+func TestAbilityStartRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		press    func(*components.InputComponent)
+		onGround bool
+		cooldown bool
+		locked   bool
+		want     components.MovementAction
+	}{
+		{"dash in the air", func(i *components.InputComponent) { i.DashPressed = true }, false, false, false, components.ActionDash},
+		{"roll needs the ground", func(i *components.InputComponent) { i.RollPressed = true }, false, false, false, components.ActionNone},
+		{"slide on the ground", func(i *components.InputComponent) { i.SlidePressed = true }, true, false, false, components.ActionSlide},
+		{"dash on cooldown", func(i *components.InputComponent) { i.DashPressed = true }, true, true, false, components.ActionNone},
+		{"locked slide", func(i *components.InputComponent) { i.SlidePressed = true }, true, false, true, components.ActionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world, _, abilities, input, _, _ := newAbilityTestWorld()
+			physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
+			physics, _ := physicsStore.Get(world.GetEntitiesWithTag("player")[0].ID)
+			physics.IsOnGround = tt.onGround
+			if tt.cooldown {
+				abilities.DashCooldownTimer = 0.5
+			}
+			if tt.locked {
+				abilities.Slide = false
+			}
+			tt.press(input)
+
+			NewAbilitySystem(nil).Update(world, 0.01)
+
+			if abilities.Action != tt.want {
+				t.Errorf("action %v, want %v", abilities.Action, tt.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,8 @@
 package systems
 
 import (
-	"image/color"
-	"unsafe"
-
 	"fire/internal/components"
 	"fire/internal/ecs"
-
-	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 // AnimationSystem updates sprite animations based on entity state.
@@ -18,16 +13,15 @@ func NewAnimationSystem() *AnimationSystem {
 	return &AnimationSystem{}
 }
 
-// Update advances animation frames for all entities with SpriteComponent.
+// Update runs each entity's animator (if any) to pick its clip, then advances
+// the clip for all entities with SpriteComponent.
 func (s *AnimationSystem) Update(world *ecs.World, dt float32) {
 	spriteStore, ok := ecs.GetStore[*components.SpriteComponent](world.Components)
 	if !ok {
 		return
 	}
 
-	transformStore, _ := ecs.GetStore[*components.TransformComponent](world.Components)
-	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
-	inputStore, _ := ecs.GetStore[*components.InputComponent](world.Components)
+	animatorStore, _ := ecs.GetStore[*components.AnimatorComponent](world.Components)
 
 	for _, id := range spriteStore.All() {
 		entity := world.GetEntity(id)
@@ -37,67 +31,177 @@ func (s *AnimationSystem) Update(world *ecs.World, dt float32) {
 
 		sprite, _ := spriteStore.Get(id)
 
-		// Determine animation state based on physics and input
-		newAnim := components.AnimIdle
-
-		hasPhysics := physicsStore != nil && physicsStore.Has(id)
-		hasInput := inputStore != nil && inputStore.Has(id)
-
-		if hasPhysics && hasInput {
-			physics, _ := physicsStore.Get(id)
-			input, _ := inputStore.Get(id)
-
-			if !physics.IsOnGround {
-				if transformStore != nil {
-					transform, ok := transformStore.Get(id)
-					if ok && transform.Velocity.Y > 0 {
-						newAnim = components.AnimFalling
-					} else {
-						newAnim = components.AnimJumping
-					}
-				} else {
-					newAnim = components.AnimFalling
+		// Entities without an animator keep their current clip, looping
+		newAnim := sprite.CurrentAnim
+		var state *components.AnimatorState
+		var animator *components.AnimatorComponent
+		var facts animationFacts
+		if animatorStore != nil {
+			animator, _ = animatorStore.Get(id)
+		}
+		if animator != nil && animator.Controller != nil {
+			previous := animator.Current
+			facts = gatherAnimationFacts(world, id, animator)
+			state = stepAnimator(animator, facts)
+			if state != nil {
+				newAnim = state.Clip
+				if animator.Current != previous {
+					sprite.Restart = true
 				}
-			} else if input.MoveX != 0 {
-				newAnim = components.AnimRunning
 			}
 		}
 
-		// Update animation state
+		// Update animation state, starting a new animation from its first frame
+		restart := sprite.Restart || newAnim != sprite.CurrentAnim
 		sprite.CurrentAnim = newAnim
+		sprite.Restart = false
 
-		// Advance animation frame
 		animData := sprite.GetCurrentAnimation()
-		if animData != nil {
-			updateAnimationFrame(animData)
+		if animData == nil {
+			continue
+		}
+
+		// The animator state sets the playback mode and speed
+		if state != nil {
+			animData.Mode = state.Mode
+			animData.Speed = animatorSpeed(state, facts)
+		}
+
+		var entered []int32
+		if restart {
+			resetAnimation(animData)
+			entered = append(entered, animData.CurrentFrame)
+		}
+
+		// Advance the animation clock (frozen during a hit-stop)
+		if dt > 0 {
+			entered = append(entered, advanceAnimation(animData, dt, state == nil || !state.OneShot)...)
+		}
+
+		if state == nil {
+			continue
+		}
+
+		// Frame events of the current state
+		for _, frame := range entered {
+			for _, event := range state.Events {
+				if event.Frame == frame {
+					world.Events.Publish(ecs.Event{
+						Type:   ecs.EventAnimationFrame,
+						Source: id,
+						Target: id,
+						Data:   AnimationFrameEvent{State: state.Name, Clip: state.Clip, Frame: frame, Name: event.Name},
+					})
+				}
+			}
+		}
+
+		// Report one-shots that just played through
+		if state.OneShot && animData.Completed && !animator.Finished {
+			animator.Finished = true
+			world.Events.Publish(ecs.Event{
+				Type:   ecs.EventAnimationFinished,
+				Source: id,
+				Target: id,
+				Data:   AnimationFinishedEvent{State: state.Name, Clip: state.Clip},
+			})
 		}
 	}
 }
 
-// updateAnimationFrame advances the animation frame counter and updates textures.
-func updateAnimationFrame(animData *components.AnimationData) {
-	if animData.FrameCount <= 1 {
-		return // No animation needed for single frame
+// advanceAnimation runs an animation's clock for dt seconds (scaled by its
+// Speed) and returns the frames it entered. Clips that do not loop stop on
+// their final frame once a cycle completes.
+func advanceAnimation(animData *components.AnimationData, dt float32, loop bool) []int32 {
+	if animData.FrameCount <= 0 || animData.Speed <= 0 {
+		return nil
 	}
 
-	animData.FrameCounter++
+	var entered []int32
+	animData.Elapsed += dt * animData.Speed
 
-	if animData.FrameCounter >= animData.FrameDelay {
-		// Move to next frame
-		animData.CurrentFrame++
-		if animData.CurrentFrame >= animData.FrameCount {
-			animData.CurrentFrame = 0
+	// Bounded so a long frame or zero-length frames cannot spin forever
+	for steps := int32(0); steps <= animData.FrameCount*2; steps++ {
+		duration := animData.FrameDuration(animData.CurrentFrame)
+		if duration > 0 && animData.Elapsed < duration {
+			break
 		}
+		if duration > 0 {
+			animData.Elapsed -= duration
+		} else {
+			animData.Elapsed = 0
+		}
+
+		if animData.Completed && !loop {
+			break
+		}
+		next, cycled := nextAnimationFrame(animData)
+		if cycled {
+			animData.Completed = true
+			if !loop {
+				animData.Elapsed = 0
+				break
+			}
+		}
+		if next != animData.CurrentFrame {
+			animData.CurrentFrame = next
+			entered = append(entered, next)
+		}
+		if duration <= 0 {
+			break
+		}
+	}
+
+	return entered
+}
 
-		if !animData.IsSpriteSheet {
-			// Calculate memory offset for current frame (for GIF animations)
-			nextFrameDataOffset := uint32(animData.Image.Width * animData.Image.Height * 4 * animData.CurrentFrame)
+// nextAnimationFrame returns the frame after the current one for the
+// animation's playback mode, and whether that step completes a cycle.
+func nextAnimationFrame(animData *components.AnimationData) (int32, bool) {
+	last := animData.FrameCount - 1
+	frame := animData.CurrentFrame
 
-			// Update GPU texture with current frame data
-			rl.UpdateTexture(animData.Texture,
-				unsafe.Slice((*color.RGBA)(unsafe.Pointer(uintptr(animData.Image.Data)+uintptr(nextFrameDataOffset))), animData.FrameSize))
+	switch animData.Mode {
+	case components.PlayReverse:
+		if frame <= 0 {
+			return last, true
 		}
+		return frame - 1, false
+
+	case components.PlayPingPong:
+		if last <= 0 {
+			return 0, true
+		}
+		if animData.Direction == 0 {
+			animData.Direction = 1
+		}
+		next := frame + animData.Direction
+		if next > last {
+			animData.Direction = -1
+			next = last - 1
+		}
+		if next < 0 {
+			// Back at the start: the next step begins a new cycle
+			animData.Direction = 1
+			return 1, true
+		}
+		return next, false
+
+	default:
+		if frame >= last {
+			return 0, true
+		}
+		return frame + 1, false
+	}
+}
 
-		animData.FrameCounter = 0
+// resetAnimation rewinds an animation to the first frame of its playback mode.
+func resetAnimation(animData *components.AnimationData) {
+	animData.CurrentFrame = 0
+	if animData.Mode == components.PlayReverse && animData.FrameCount > 0 {
+		animData.CurrentFrame = animData.FrameCount - 1
 	}
+	animData.Elapsed = 0
+	animData.Direction = 1
+	animData.Completed = false
 }
@@ -0,0 +1,118 @@
+package systems
+
+import (
+	"testing"
+
+	"fire/internal/components"
+)
+
+// newTestClip returns a clip of count frames lasting durations seconds
+// each (one entry applies to every frame).
+func newTestClip(count int32, mode components.PlaybackMode, durations ...float32) *components.AnimationData {
+	clip := &components.AnimationData{
+		FrameCount:     count,
+		FrameDurations: durations,
+		Mode:           mode,
+		Speed:          1,
+	}
+	resetAnimation(clip)
+	return clip
+}
+
+// playedFrames advances a clip in steps of dt and returns the frame shown
+// after each.
+func playedFrames(clip *components.AnimationData, dt float32, steps int, loop bool) []int32 {
+	frames := make([]int32, 0, steps)
+	for i := 0; i < steps; i++ {
+		advanceAnimation(clip, dt, loop)
+		frames = append(frames, clip.CurrentFrame)
+	}
+	return frames
+}
+
+func equalFrames(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAdvanceAnimationModes(t *testing.T) {
+	tests := []struct {
+		name string
+		clip *components.AnimationData
+		loop bool
+		want []int32
+	}{
+		{"forward loops", newTestClip(3, components.PlayForward, 0.125), true, []int32{1, 2, 0, 1}},
+		{"forward once stops on the last frame", newTestClip(3, components.PlayForward, 0.125), false, []int32{1, 2, 2, 2}},
+		{"reverse", newTestClip(3, components.PlayReverse, 0.125), true, []int32{1, 0, 2, 1}},
+		{"ping-pong", newTestClip(3, components.PlayPingPong, 0.125), true, []int32{1, 2, 1, 0, 1, 2}},
+		{"per-frame durations", newTestClip(3, components.PlayForward, 0.125, 0.25, 0.125), true, []int32{1, 1, 2, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := playedFrames(tt.clip, 0.125, len(tt.want), tt.loop); !equalFrames(got, tt.want) {
+				t.Errorf("played %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvanceAnimationEnteredFrames(t *testing.T) {
+	clip := newTestClip(4, components.PlayForward, 0.125)
+
+	// A long update passes through every frame it skips
+	entered := advanceAnimation(clip, 0.3125, true)
+	if want := []int32{1, 2}; !equalFrames(entered, want) {
+		t.Errorf("entered %v, want %v", entered, want)
+	}
+	if clip.Elapsed != 0.0625 {
+		t.Errorf("elapsed %v, want the 0.0625 left over", clip.Elapsed)
+	}
+}
+
+func TestAdvanceAnimationCompletes(t *testing.T) {
+	clip := newTestClip(2, components.PlayForward, 0.125)
+	advanceAnimation(clip, 0.125, false)
+	if clip.Completed {
+		t.Fatal("completed before the last frame played")
+	}
+	advanceAnimation(clip, 0.125, false)
+	if !clip.Completed || clip.CurrentFrame != 1 {
+		t.Errorf("after a cycle: completed %v on frame %d, want true on 1", clip.Completed, clip.CurrentFrame)
+	}
+}
+
+func TestAdvanceAnimationIndependentOfFrameRate(t *testing.T) {
+	var want int32 = -1
+	for _, rate := range testFrameRates {
+		clip := newTestClip(8, components.PlayForward, 0.1)
+		clip.Speed = 1.45
+		for frame := 0; frame < int(rate); frame++ {
+			advanceAnimation(clip, 1/rate, true)
+		}
+		// 1.45 s of playback at 0.1 s per frame is 14.5 frames, halfway
+		// through frame 6 of the second cycle
+		if clip.CurrentFrame != 6 {
+			t.Errorf("%v Hz: on frame %d after 1s, want 6", rate, clip.CurrentFrame)
+		}
+		if want >= 0 && clip.CurrentFrame != want {
+			t.Errorf("%v Hz: frame %d differs from %d", rate, clip.CurrentFrame, want)
+		}
+		want = clip.CurrentFrame
+	}
+}
+
+func TestAdvanceAnimationZeroDurations(t *testing.T) {
+	// Zero-length frames advance one frame per update rather than spinning
+	clip := newTestClip(3, components.PlayForward, 0)
+	if got := playedFrames(clip, 0.01, 3, true); !equalFrames(got, []int32{1, 2, 0}) {
+		t.Errorf("played %v, want [1 2 0]", got)
+	}
+}
@@ -0,0 +1,213 @@
+package systems
+
+import (
+	"math"
+	"strings"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+)
+
+// AnimationFinishedEvent is the Data of an ecs.EventAnimationFinished.
+type AnimationFinishedEvent struct {
+	State string
+	Clip  components.AnimationState
+}
+
+// AnimationFrameEvent is the Data of an ecs.EventAnimationFrame.
+type AnimationFrameEvent struct {
+	State string
+	Clip  components.AnimationState
+	Frame int32
+	Name  string // Event name from the animator data, e.g. "footstep"
+}
+
+// animationFacts is the entity state animator conditions are evaluated on.
+// Any component may be missing; conditions about it are then false.
+type animationFacts struct {
+	transform *components.TransformComponent
+	physics   *components.PhysicsComponent
+	input     *components.InputComponent
+	abilities *components.AbilitiesComponent
+	melee     *components.MeleeComponent
+	health    *components.HealthComponent
+	triggers  map[string]bool
+}
+
+// actionNames are the arguments of the "action:" condition.
+var actionNames = map[components.MovementAction]string{
+	components.ActionDash:  "dash",
+	components.ActionRoll:  "roll",
+	components.ActionSlide: "slide",
+}
+
+// gatherAnimationFacts collects the components animator conditions read.
+func gatherAnimationFacts(world *ecs.World, id ecs.EntityID, animator *components.AnimatorComponent) animationFacts {
+	facts := animationFacts{triggers: animator.Triggers}
+	if store, ok := ecs.GetStore[*components.TransformComponent](world.Components); ok {
+		facts.transform, _ = store.Get(id)
+	}
+	if store, ok := ecs.GetStore[*components.PhysicsComponent](world.Components); ok {
+		facts.physics, _ = store.Get(id)
+	}
+	if store, ok := ecs.GetStore[*components.InputComponent](world.Components); ok {
+		facts.input, _ = store.Get(id)
+	}
+	if store, ok := ecs.GetStore[*components.AbilitiesComponent](world.Components); ok {
+		facts.abilities, _ = store.Get(id)
+	}
+	if store, ok := ecs.GetStore[*components.MeleeComponent](world.Components); ok {
+		facts.melee, _ = store.Get(id)
+	}
+	if store, ok := ecs.GetStore[*components.HealthComponent](world.Components); ok {
+		facts.health, _ = store.Get(id)
+	}
+	return facts
+}
+
+// check evaluates a named condition. Conditions are "name" or "name:arg";
+// unknown names are false.
+func (f animationFacts) check(condition string) bool {
+	name, arg, _ := strings.Cut(condition, ":")
+
+	switch name {
+	case "onGround":
+		return f.physics != nil && f.physics.IsOnGround
+	case "falling":
+		return f.transform != nil && f.transform.Velocity.Y > 0
+	case "moving":
+		return f.input != nil && f.input.MoveX != 0
+	case "turningAround":
+		return f.physics != nil && f.physics.IsTurningAround
+	case "crouching":
+		return f.physics != nil && f.physics.IsCrouching
+	case "crouchTransition":
+		// Going down or standing back up on the ground
+		return f.physics != nil && f.physics.CrouchTransitionTimer > 0 &&
+			(f.physics.IsCrouching || f.physics.IsOnGround)
+	case "wallSliding":
+		return f.abilities != nil && f.abilities.IsWallSliding
+	case "wallHang":
+		// Gripping the wall on the way up, before the slide starts
+		if f.physics == nil || f.input == nil || f.abilities == nil || !f.abilities.WallSlide {
+			return false
+		}
+		pressingIntoWall := f.physics.WallContact != 0 && f.input.MoveX != 0 &&
+			(f.input.MoveX > 0) == (f.physics.WallContact > 0)
+		return !f.physics.IsOnGround && pressingIntoWall
+	case "action":
+		return f.abilities != nil && f.abilities.Action != components.ActionNone &&
+			actionNames[f.abilities.Action] == arg
+	case "attacking":
+		return f.melee != nil && f.melee.IsAttacking()
+	case "attack":
+		return f.melee != nil && f.melee.IsAttacking() && f.melee.Current.Animation.String() == arg
+	case "dead":
+		return f.health != nil && f.health.IsDead()
+	case "trigger":
+		return f.triggers[arg]
+	}
+	return false
+}
+
+// value returns a named number for speed scaling; unknown names are 0.
+func (f animationFacts) value(name string) float32 {
+	if f.transform == nil {
+		return 0
+	}
+	switch name {
+	case "speedX":
+		return float32(math.Abs(float64(f.transform.Velocity.X)))
+	case "speedY":
+		return float32(math.Abs(float64(f.transform.Velocity.Y)))
+	}
+	return 0
+}
+
+// animatorSpeed returns the playback speed of a state, scaled by its SpeedBy
+// value when it has one.
+func animatorSpeed(state *components.AnimatorState, facts animationFacts) float32 {
+	speed := state.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	if state.SpeedBy != "" && state.SpeedReference > 0 {
+		speed *= facts.value(state.SpeedBy) / state.SpeedReference
+	}
+	return speed
+}
+
+// stepAnimator advances an animator by one update and returns its current
+// state. A finished one-shot moves to its next state first; then the first
+// transition (in priority order) whose conditions hold is taken. One-shots
+// still playing can only be left by interrupts, and held states only by
+// transitions naming them. Triggers are consumed.
+func stepAnimator(animator *components.AnimatorComponent, facts animationFacts) *components.AnimatorState {
+	controller := animator.Controller
+	defer clear(animator.Triggers)
+
+	state, ok := controller.States[animator.Current]
+	if !ok {
+		state = enterAnimatorState(animator, controller.Default)
+	}
+	if state == nil {
+		return nil
+	}
+
+	if state.OneShot && animator.Finished && !state.Hold {
+		next := state.Next
+		if next == "" {
+			next = controller.Default
+		}
+		if entered := enterAnimatorState(animator, next); entered != nil {
+			state = entered
+		}
+	}
+
+	playing := state.OneShot && !animator.Finished
+	for _, transition := range controller.Transitions {
+		if state.Hold && transition.From != state.Name {
+			continue
+		}
+		if transition.From != "" && transition.From != state.Name {
+			continue
+		}
+		if playing && !transition.Interrupt {
+			continue
+		}
+		if !transitionAllowed(transition, facts) {
+			continue
+		}
+
+		// The first match wins, even when it keeps the current state
+		if transition.To != state.Name {
+			if entered := enterAnimatorState(animator, transition.To); entered != nil {
+				state = entered
+			}
+		}
+		break
+	}
+
+	return state
+}
+
+// transitionAllowed reports whether all of a transition's conditions hold.
+func transitionAllowed(transition components.AnimatorTransition, facts animationFacts) bool {
+	for _, condition := range transition.Conditions {
+		if facts.check(condition.Name) == condition.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// enterAnimatorState switches the animator to the named state.
+func enterAnimatorState(animator *components.AnimatorComponent, name string) *components.AnimatorState {
+	state, ok := animator.Controller.States[name]
+	if !ok {
+		return nil
+	}
+	animator.Current = name
+	animator.Finished = false
+	return state
+}
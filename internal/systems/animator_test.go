@@ -0,0 +1,152 @@
+package systems
+
+import (
+	"testing"
+
+	"fire/internal/components"
+)
+
+// newTestAnimator returns an animator for a small controller: ground
+// locomotion, an attack one-shot, a hurt one-shot any trigger interrupts
+// into, and a held death state.
+func newTestAnimator() *components.AnimatorComponent {
+	when := func(names ...string) []components.AnimatorCondition {
+		conditions := make([]components.AnimatorCondition, 0, len(names))
+		for _, name := range names {
+			negate := name[0] == '!'
+			if negate {
+				name = name[1:]
+			}
+			conditions = append(conditions, components.AnimatorCondition{Name: name, Negate: negate})
+		}
+		return conditions
+	}
+	controller := &components.AnimatorController{
+		Default: "idle",
+		States: map[string]*components.AnimatorState{
+			"idle":   {Name: "idle"},
+			"run":    {Name: "run"},
+			"jump":   {Name: "jump"},
+			"attack": {Name: "attack", OneShot: true},
+			"hurt":   {Name: "hurt", OneShot: true},
+			"dead":   {Name: "dead", OneShot: true, Hold: true},
+		},
+		Transitions: []components.AnimatorTransition{
+			{To: "dead", Conditions: when("dead"), Interrupt: true},
+			{To: "hurt", Conditions: when("trigger:hit"), Interrupt: true},
+			{To: "attack", Conditions: when("attacking")},
+			{To: "jump", Conditions: when("!onGround")},
+			{To: "run", Conditions: when("moving")},
+			{To: "idle"},
+		},
+	}
+	return &components.AnimatorComponent{Controller: controller}
+}
+
+// testFacts builds animation facts for a grounded entity.
+type testFacts struct {
+	moving, airborne, attacking, dead bool
+}
+
+func (f testFacts) build(animator *components.AnimatorComponent) animationFacts {
+	input := &components.InputComponent{}
+	if f.moving {
+		input.MoveX = 1
+	}
+	melee := &components.MeleeComponent{}
+	if f.attacking {
+		melee.Current = &components.AttackDefinition{Name: "slash"}
+	}
+	health := &components.HealthComponent{Current: 3, Max: 3}
+	if f.dead {
+		health.Current = 0
+	}
+	return animationFacts{
+		transform: &components.TransformComponent{},
+		physics:   &components.PhysicsComponent{IsOnGround: !f.airborne},
+		input:     input,
+		melee:     melee,
+		health:    health,
+		triggers:  animator.Triggers,
+	}
+}
+
+func TestStepAnimator(t *testing.T) {
+	animator := newTestAnimator()
+	steps := []struct {
+		name     string
+		facts    testFacts
+		trigger  string
+		finished bool // The current one-shot played through before this step
+		want     string
+	}{
+		{"starts in the default state", testFacts{}, "", false, "idle"},
+		{"first matching transition wins", testFacts{moving: true}, "", false, "run"},
+		{"leaves the ground", testFacts{moving: true, airborne: true}, "", false, "jump"},
+		{"starts a one-shot", testFacts{attacking: true}, "", false, "attack"},
+		{"a playing one-shot ignores plain transitions", testFacts{moving: true}, "", false, "attack"},
+		{"a trigger interrupts it", testFacts{moving: true}, "hit", false, "hurt"},
+		{"the trigger is consumed", testFacts{moving: true}, "", false, "hurt"},
+		{"a finished one-shot moves on", testFacts{moving: true}, "", true, "run"},
+		{"death interrupts", testFacts{dead: true}, "", false, "dead"},
+		{"a held state stays after finishing", testFacts{moving: true, dead: true}, "hit", true, "dead"},
+	}
+	for _, step := range steps {
+		if step.trigger != "" {
+			animator.SetTrigger(step.trigger)
+		}
+		animator.Finished = step.finished
+		state := stepAnimator(animator, step.facts.build(animator))
+		if state == nil || state.Name != step.want || animator.Current != step.want {
+			t.Fatalf("%s: in %v (current %q), want %q", step.name, state, animator.Current, step.want)
+		}
+	}
+}
+
+func TestAnimationFactsCheck(t *testing.T) {
+	facts := animationFacts{
+		transform: &components.TransformComponent{},
+		physics:   &components.PhysicsComponent{IsOnGround: false, WallContact: 1},
+		input:     &components.InputComponent{MoveX: 1},
+		abilities: &components.AbilitiesComponent{WallSlide: true, Action: components.ActionRoll},
+		triggers:  map[string]bool{"hit": true},
+	}
+	facts.transform.Velocity.Y = 50
+
+	tests := map[string]bool{
+		"falling":       true,
+		"onGround":      false,
+		"wallHang":      true,
+		"action:roll":   true,
+		"action:dash":   false,
+		"trigger:hit":   true,
+		"trigger:other": false,
+		"attacking":     false, // No melee component
+		"unknown":       false,
+	}
+	for condition, want := range tests {
+		if got := facts.check(condition); got != want {
+			t.Errorf("check(%q) = %v, want %v", condition, got, want)
+		}
+	}
+}
+
+func TestAnimatorSpeed(t *testing.T) {
+	facts := animationFacts{transform: &components.TransformComponent{}}
+	facts.transform.Velocity.X = -120
+
+	tests := []struct {
+		state components.AnimatorState
+		want  float32
+	}{
+		{components.AnimatorState{}, 1},
+		{components.AnimatorState{Speed: 2}, 2},
+		{components.AnimatorState{SpeedBy: "speedX", SpeedReference: 240}, 0.5},
+		{components.AnimatorState{Speed: 2, SpeedBy: "speedX", SpeedReference: 240}, 1},
+	}
+	for _, tt := range tests {
+		if got := animatorSpeed(&tt.state, facts); got != tt.want {
+			t.Errorf("animatorSpeed(%+v) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
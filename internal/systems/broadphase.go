@@ -0,0 +1,177 @@
+package systems
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// BroadphaseCellSize is the side, in world units, of the square cells the
+// collision broadphase sorts solids into.
+const BroadphaseCellSize = 128
+
+// broadphaseCell is a broadphase grid position in cells.
+type broadphaseCell struct {
+	X, Y int
+}
+
+// broadphase is a uniform grid of solids. Each solid is listed in every
+// cell its bounds overlap, so an entity only has to be checked against the
+// solids in the cells around it instead of every solid in the level.
+//
+// Tiles do not move, so they are sorted into static cells once per world,
+// in ID order within each cell. Moving solids are listed in separate cells
+// that are refilled every frame.
+type broadphase struct {
+	static  map[broadphaseCell][]*ecs.Entity // Tiles, in ID order
+	dynamic map[broadphaseCell][]*ecs.Entity // Moving solids this frame
+
+	// What the static cells were built from
+	world *ecs.World
+	tiles int // Number of tile components in world
+
+	// Query scratch space, reused between queries
+	seen  []int // Query stamp each entity ID was last returned in
+	stamp int
+}
+
+// newBroadphase creates an empty broadphase.
+func newBroadphase() *broadphase {
+	return &broadphase{
+		static:  make(map[broadphaseCell][]*ecs.Entity),
+		dynamic: make(map[broadphaseCell][]*ecs.Entity),
+	}
+}
+
+// staticFor reports whether the static cells were built from the given
+// number of tiles of world.
+func (b *broadphase) staticFor(world *ecs.World, tiles int) bool {
+	return b.world == world && b.tiles == tiles
+}
+
+// buildStatic replaces the static cells with the given solids, sorted into
+// cells by their world bounds. world and tiles record what they were built
+// from (see staticFor).
+func (b *broadphase) buildStatic(world *ecs.World, tiles int, solids []*ecs.Entity, bounds func(*ecs.Entity) (rl.Rectangle, bool)) {
+	clear(b.static)
+	b.world, b.tiles = world, tiles
+	for _, solid := range solids {
+		if rect, ok := bounds(solid); ok {
+			b.insert(b.static, solid, rect)
+		}
+	}
+	for _, cell := range b.static {
+		slices.SortFunc(cell, compareIDs)
+	}
+}
+
+// clearDynamic empties the moving solids' cells. The cell lists are kept so
+// refilling them each frame does not allocate.
+func (b *broadphase) clearDynamic() {
+	for cell, solids := range b.dynamic {
+		b.dynamic[cell] = solids[:0]
+	}
+}
+
+// insertDynamic lists a moving solid in the cells its bounds overlap until
+// the next clearDynamic.
+func (b *broadphase) insertDynamic(solid *ecs.Entity, rect rl.Rectangle) {
+	b.insert(b.dynamic, solid, rect)
+}
+
+// insert lists a solid in every cell of cells that rect overlaps.
+func (b *broadphase) insert(cells map[broadphaseCell][]*ecs.Entity, solid *ecs.Entity, rect rl.Rectangle) {
+	first, last := b.cellRange(rect)
+	for x := first.X; x <= last.X; x++ {
+		for y := first.Y; y <= last.Y; y++ {
+			cell := broadphaseCell{X: x, Y: y}
+			cells[cell] = append(cells[cell], solid)
+		}
+	}
+}
+
+// query returns the active solids listed in the cells overlapping area,
+// once each and in ID order, so resolving against them gives the same
+// result as resolving against every solid.
+func (b *broadphase) query(area rl.Rectangle, out []*ecs.Entity) []*ecs.Entity {
+	b.stamp++
+	out = out[:0]
+	first, last := b.cellRange(area)
+	for x := first.X; x <= last.X; x++ {
+		for y := first.Y; y <= last.Y; y++ {
+			cell := broadphaseCell{X: x, Y: y}
+			out = b.collect(b.static[cell], out)
+			out = b.collect(b.dynamic[cell], out)
+		}
+	}
+
+	// Only the few solids found are sorted; most come in order already.
+	// SortFunc does not allocate, so queries stay free every step.
+	slices.SortFunc(out, compareIDs)
+	return out
+}
+
+// compareIDs orders entities by ID.
+func compareIDs(a, b *ecs.Entity) int {
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// collect appends the active solids of a cell not yet returned by the
+// current query.
+func (b *broadphase) collect(solids, out []*ecs.Entity) []*ecs.Entity {
+	for _, solid := range solids {
+		if !solid.Active {
+			continue
+		}
+		if int(solid.ID) >= len(b.seen) {
+			b.seen = append(b.seen, make([]int, int(solid.ID)+1-len(b.seen))...)
+		}
+		if b.seen[solid.ID] != b.stamp {
+			b.seen[solid.ID] = b.stamp
+			out = append(out, solid)
+		}
+	}
+	return out
+}
+
+// cellRange returns the first and last cells a rectangle overlaps.
+func (b *broadphase) cellRange(rect rl.Rectangle) (broadphaseCell, broadphaseCell) {
+	return broadphaseCellAt(rect.X, rect.Y), broadphaseCellAt(rect.X+rect.Width, rect.Y+rect.Height)
+}
+
+// occupied calls visit with the bounds of every cell holding a solid, once
+// each.
+func (b *broadphase) occupied(visit func(rl.Rectangle)) {
+	for cell, solids := range b.static {
+		if len(solids) > 0 {
+			visit(broadphaseCellBounds(cell))
+		}
+	}
+	for cell, solids := range b.dynamic {
+		if len(solids) > 0 && len(b.static[cell]) == 0 {
+			visit(broadphaseCellBounds(cell))
+		}
+	}
+}
+
+// broadphaseCellAt returns the cell containing a world position.
+func broadphaseCellAt(x, y float32) broadphaseCell {
+	return broadphaseCell{
+		X: int(math.Floor(float64(x / BroadphaseCellSize))),
+		Y: int(math.Floor(float64(y / BroadphaseCellSize))),
+	}
+}
+
+// broadphaseCellBounds returns the world area of a cell.
+func broadphaseCellBounds(cell broadphaseCell) rl.Rectangle {
+	return rl.Rectangle{
+		X:      float32(cell.X * BroadphaseCellSize),
+		Y:      float32(cell.Y * BroadphaseCellSize),
+		Width:  BroadphaseCellSize,
+		Height: BroadphaseCellSize,
+	}
+}
@@ -0,0 +1,299 @@
+package systems
+
+import (
+	"math"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// CameraSystem moves cameras to follow their target entity: the target may
+// roam a deadzone before the camera follows, the view leads in the facing
+// direction, movement is smoothed and the view is kept inside the level or
+// the camera zone the target is in. Screen shake and zoom effects are
+// applied on top.
+type CameraSystem struct {
+	// Shake requests gathered from events since the last update
+	damageHits    int
+	landingSpeeds []float32
+}
+
+// NewCameraSystem creates a new CameraSystem that shakes the camera on the
+// damage and landing events of the given bus.
+func NewCameraSystem(events *ecs.EventBus) *CameraSystem {
+	s := &CameraSystem{}
+	events.Subscribe(ecs.EventDamage, func(ecs.Event) {
+		s.damageHits++
+	})
+	events.Subscribe(ecs.EventPlayerLand, func(event ecs.Event) {
+		if land, ok := event.Data.(LandEvent); ok {
+			s.landingSpeeds = append(s.landingSpeeds, land.Speed)
+		}
+	})
+	return s
+}
+
+// FindCamera returns the first active camera in the world, or nil. Systems
+// and UI use it to reach the camera API.
+func FindCamera(world *ecs.World) *components.CameraComponent {
+	cameraStore, ok := ecs.GetStore[*components.CameraComponent](world.Components)
+	if !ok {
+		return nil
+	}
+	for _, id := range cameraStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+		camera, _ := cameraStore.Get(id)
+		return camera
+	}
+	return nil
+}
+
+// Update moves every camera towards its target and applies its effects.
+func (s *CameraSystem) Update(world *ecs.World, dt float32) {
+	cameraStore, ok1 := ecs.GetStore[*components.CameraComponent](world.Components)
+	transformStore, ok2 := ecs.GetStore[*components.TransformComponent](world.Components)
+	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
+
+	defer func() {
+		s.damageHits = 0
+		s.landingSpeeds = s.landingSpeeds[:0]
+	}()
+
+	if !ok1 || !ok2 {
+		return
+	}
+
+	if dt > MaxPhysicsStep {
+		dt = MaxPhysicsStep
+	}
+
+	for _, id := range cameraStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+
+		camera, _ := cameraStore.Get(id)
+		s.applyShakeRequests(camera)
+
+		transform, ok := transformStore.Get(ecs.EntityID(camera.TargetID))
+		if !ok {
+			continue
+		}
+
+		// Follow the middle of the collider when there is one
+		point := transform.Position
+		if colliderStore != nil {
+			if collider, ok := colliderStore.Get(ecs.EntityID(camera.TargetID)); ok {
+				bounds := collider.GetWorldBounds(transform.Position)
+				point = rl.Vector2{X: bounds.X + bounds.Width/2, Y: bounds.Y + bounds.Height/2}
+			}
+		}
+
+		updateCameraZone(camera, point)
+
+		lead := camera.LookAhead
+		if !transform.FacingRight {
+			lead = -lead
+		}
+
+		if camera.Snap {
+			camera.Focus = point
+			camera.LookAheadOffset = lead
+			camera.Camera.Zoom = camera.BaseZoom() * camera.PulseFactor()
+			camera.Center = cameraGoal(camera)
+			camera.Camera.Target = camera.Center
+			camera.Snap = false
+			continue
+		}
+		if dt <= 0 {
+			continue
+		}
+
+		camera.AdvanceEffects(dt)
+		camera.Camera.Zoom = camera.BaseZoom() * camera.PulseFactor()
+
+		camera.Focus = followDeadzone(camera.Focus, point, camera.DeadzoneWidth, camera.DeadzoneHeight)
+		camera.LookAheadOffset += (lead - camera.LookAheadOffset) * smoothingFactor(camera.LookAheadSpeed, dt)
+
+		goal := cameraGoal(camera)
+		t := smoothingFactor(camera.Smoothing, dt)
+		camera.Center = rl.Vector2{
+			X: camera.Center.X + (goal.X-camera.Center.X)*t,
+			Y: camera.Center.Y + (goal.Y-camera.Center.Y)*t,
+		}
+
+		applyShake(camera, dt)
+	}
+
+	if debug := FindDebugDraw(world); debug != nil {
+		for _, id := range cameraStore.All() {
+			if entity := world.GetEntity(id); entity != nil && entity.Active {
+				camera, _ := cameraStore.Get(id)
+				drawCameraDebug(debug, camera)
+			}
+		}
+	}
+}
+
+// drawCameraDebug queues a camera's deadzone around its focus, the followed
+// view centre and the active zone.
+func drawCameraDebug(debug *components.DebugDrawComponent, camera *components.CameraComponent) {
+	deadzone := rl.Rectangle{
+		X:      camera.Focus.X - camera.DeadzoneWidth/2,
+		Y:      camera.Focus.Y - camera.DeadzoneHeight/2,
+		Width:  camera.DeadzoneWidth,
+		Height: camera.DeadzoneHeight,
+	}
+	debug.Rect(deadzone, debugCameraColor)
+	debug.Line(camera.Focus, camera.Center, debugCameraColor)
+	debug.Circle(camera.Center, debugWaypointSize, debugCameraColor)
+	if camera.ActiveZone >= 0 && camera.ActiveZone < len(camera.Zones) {
+		debug.Rect(camera.Zones[camera.ActiveZone].Area, debugCameraColor)
+	}
+}
+
+// applyShakeRequests turns the damage and landing events since the last
+// update into trauma.
+func (s *CameraSystem) applyShakeRequests(camera *components.CameraComponent) {
+	for i := 0; i < s.damageHits; i++ {
+		camera.AddTrauma(camera.DamageTrauma)
+	}
+	for _, speed := range s.landingSpeeds {
+		if speed < camera.LandMinSpeed || camera.LandFullSpeed <= camera.LandMinSpeed {
+			continue
+		}
+		strength := min(1, (speed-camera.LandMinSpeed)/(camera.LandFullSpeed-camera.LandMinSpeed))
+		camera.AddTrauma(camera.LandTrauma * strength)
+	}
+}
+
+// updateCameraZone tracks the zone containing point and eases to the zoom of
+// the zone entered, or back to the default zoom when leaving one.
+func updateCameraZone(camera *components.CameraComponent, point rl.Vector2) {
+	zone := -1
+	for i, candidate := range camera.Zones {
+		if rl.CheckCollisionPointRec(point, candidate.Area) {
+			zone = i
+			break
+		}
+	}
+	if zone == camera.ActiveZone {
+		return
+	}
+
+	previousZoom := float32(0)
+	if camera.ActiveZone >= 0 && camera.ActiveZone < len(camera.Zones) {
+		previousZoom = camera.Zones[camera.ActiveZone].Zoom
+	}
+	camera.ActiveZone = zone
+
+	zoom := float32(0)
+	if zone >= 0 {
+		zoom = camera.Zones[zone].Zoom
+	}
+	if zoom > 0 {
+		camera.SetZoom(zoom, camera.ZoneBlendTime)
+	} else if previousZoom > 0 {
+		camera.SetZoom(camera.DefaultZoom, camera.ZoneBlendTime)
+	}
+}
+
+// cameraGoal returns where the view centre should be: the focus plus the
+// look-ahead, or the centre of a locking zone, kept inside the bounds in
+// force.
+func cameraGoal(camera *components.CameraComponent) rl.Vector2 {
+	goal := rl.Vector2{X: camera.Focus.X + camera.LookAheadOffset, Y: camera.Focus.Y}
+	bounds := camera.Bounds
+
+	if camera.ActiveZone >= 0 && camera.ActiveZone < len(camera.Zones) {
+		zone := camera.Zones[camera.ActiveZone]
+		bounds = zone.Area
+		if zone.Mode == components.ZoneLock {
+			goal = rl.Vector2{X: zone.Area.X + zone.Area.Width/2, Y: zone.Area.Y + zone.Area.Height/2}
+		}
+	}
+
+	return clampCameraTarget(bounds, camera.ViewSize(), goal)
+}
+
+// applyShake decays trauma and offsets and tilts the drawn view by smooth
+// noise scaled by trauma squared.
+func applyShake(camera *components.CameraComponent, dt float32) {
+	camera.Trauma = max(0, camera.Trauma-camera.TraumaDecay*dt)
+	camera.ShakeTime += dt * camera.ShakeFrequency
+
+	shake := camera.Trauma * camera.Trauma
+	camera.Camera.Target = rl.Vector2{
+		X: camera.Center.X + camera.ShakeOffset*shake*shakeNoise(1, camera.ShakeTime),
+		Y: camera.Center.Y + camera.ShakeOffset*shake*shakeNoise(2, camera.ShakeTime),
+	}
+	camera.Camera.Rotation = camera.ShakeAngle * shake * shakeNoise(3, camera.ShakeTime)
+}
+
+// shakeNoise is a smooth pseudo-random signal in [-1, 1]; each seed gives an
+// independent channel.
+func shakeNoise(seed, t float32) float32 {
+	a := math.Sin(float64(t*1.9 + seed*12.9898))
+	b := math.Sin(float64(t*3.7 + seed*78.233))
+	return float32(a*0.6 + b*0.4)
+}
+
+// followDeadzone moves focus just enough to keep point inside the deadzone
+// centred on it.
+func followDeadzone(focus, point rl.Vector2, width, height float32) rl.Vector2 {
+	halfWidth, halfHeight := width/2, height/2
+	if point.X > focus.X+halfWidth {
+		focus.X = point.X - halfWidth
+	} else if point.X < focus.X-halfWidth {
+		focus.X = point.X + halfWidth
+	}
+	if point.Y > focus.Y+halfHeight {
+		focus.Y = point.Y - halfHeight
+	} else if point.Y < focus.Y-halfHeight {
+		focus.Y = point.Y + halfHeight
+	}
+	return focus
+}
+
+// smoothingFactor returns the fraction of the remaining distance to cover
+// this update for a frame-rate independent exponential follow. A rate of 0
+// covers all of it.
+func smoothingFactor(rate, dt float32) float32 {
+	if rate <= 0 {
+		return 1
+	}
+	return 1 - float32(math.Exp(-float64(rate*dt)))
+}
+
+// clampCameraTarget keeps a view of the given size inside bounds. An area
+// smaller than the view is centred; empty bounds do not clamp.
+func clampCameraTarget(bounds rl.Rectangle, view, target rl.Vector2) rl.Vector2 {
+	if bounds.Width <= 0 || bounds.Height <= 0 {
+		return target
+	}
+	return rl.Vector2{
+		X: clampAxis(target.X, bounds.X, bounds.Width, view.X),
+		Y: clampAxis(target.Y, bounds.Y, bounds.Height, view.Y),
+	}
+}
+
+// clampAxis clamps a view centre on one axis to [start, start+length].
+func clampAxis(centre, start, length, view float32) float32 {
+	if length <= view {
+		return start + length/2
+	}
+	low, high := start+view/2, start+length-view/2
+	if centre < low {
+		return low
+	}
+	if centre > high {
+		return high
+	}
+	return centre
+}
@@ -0,0 +1,111 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"fire/internal/components"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestFollowDeadzone(t *testing.T) {
+	focus := rl.Vector2{X: 100, Y: 100}
+	tests := []struct {
+		name  string
+		point rl.Vector2
+		want  rl.Vector2
+	}{
+		{"inside does not move", rl.Vector2{X: 110, Y: 95}, focus},
+		{"right edge pulls", rl.Vector2{X: 150, Y: 100}, rl.Vector2{X: 130, Y: 100}},
+		{"left edge pulls", rl.Vector2{X: 50, Y: 100}, rl.Vector2{X: 70, Y: 100}},
+		{"both axes", rl.Vector2{X: 150, Y: 150}, rl.Vector2{X: 130, Y: 140}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := followDeadzone(focus, tt.point, 40, 20); got != tt.want {
+				t.Errorf("focus %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmoothingFactorIndependentOfFrameRate(t *testing.T) {
+	if got := smoothingFactor(0, 0.016); got != 1 {
+		t.Errorf("rate 0 covers %v, want all of it", got)
+	}
+
+	// Following for one second covers the same distance at any frame rate
+	var want float64 = -1
+	for _, rate := range testFrameRates {
+		position := float32(0)
+		for frame := 0; frame < int(rate); frame++ {
+			position += (100 - position) * smoothingFactor(5, 1/rate)
+		}
+		if want >= 0 && math.Abs(float64(position)-want) > 0.01 {
+			t.Errorf("%v Hz: at %v after 1s, others at %v", rate, position, want)
+		}
+		want = float64(position)
+	}
+}
+
+func TestClampCameraTarget(t *testing.T) {
+	bounds := rl.Rectangle{X: 0, Y: 0, Width: 1000, Height: 200}
+	view := rl.Vector2{X: 400, Y: 300}
+	tests := []struct {
+		name   string
+		bounds rl.Rectangle
+		target rl.Vector2
+		want   rl.Vector2
+	}{
+		{"inside", bounds, rl.Vector2{X: 500, Y: 0}, rl.Vector2{X: 500, Y: 100}},
+		{"past the left edge", bounds, rl.Vector2{X: 50, Y: 0}, rl.Vector2{X: 200, Y: 100}},
+		{"past the right edge", bounds, rl.Vector2{X: 950, Y: 0}, rl.Vector2{X: 800, Y: 100}},
+		{"empty bounds do not clamp", rl.Rectangle{}, rl.Vector2{X: -50, Y: 7}, rl.Vector2{X: -50, Y: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The bounds are shorter than the view, so Y is centred
+			if got := clampCameraTarget(tt.bounds, view, tt.target); got != tt.want {
+				t.Errorf("target %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCameraGoalLockZone(t *testing.T) {
+	camera := &components.CameraComponent{
+		Focus:           rl.Vector2{X: 100, Y: 100},
+		LookAheadOffset: 30,
+		ActiveZone:      -1,
+		Zones: []components.CameraZone{
+			{Area: rl.Rectangle{X: 1000, Y: 0, Width: 200, Height: 100}, Mode: components.ZoneLock},
+		},
+	}
+	if got, want := cameraGoal(camera), (rl.Vector2{X: 130, Y: 100}); got != want {
+		t.Errorf("goal %v, want the focus plus look-ahead %v", got, want)
+	}
+
+	camera.ActiveZone = 0
+	if got, want := cameraGoal(camera), (rl.Vector2{X: 1100, Y: 50}); got != want {
+		t.Errorf("goal in a lock zone %v, want its centre %v", got, want)
+	}
+}
+
+func TestApplyShakeDecays(t *testing.T) {
+	camera := &components.CameraComponent{
+		Center:         rl.Vector2{X: 50, Y: 50},
+		Trauma:         1,
+		TraumaDecay:    2,
+		ShakeOffset:    10,
+		ShakeFrequency: 20,
+	}
+	applyShake(camera, 0.25)
+	if camera.Trauma != 0.5 {
+		t.Errorf("trauma %v, want 0.5", camera.Trauma)
+	}
+	applyShake(camera, 1)
+	if camera.Trauma != 0 || camera.Camera.Target != camera.Center {
+		t.Errorf("spent trauma %v left the view at %v, want %v", camera.Trauma, camera.Camera.Target, camera.Center)
+	}
+}
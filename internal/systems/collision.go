@@ -1,48 +1,198 @@
 package systems
 
 import (
+	"math"
+	"sort"
+
 	"fire/internal/components"
 	"fire/internal/ecs"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-// CollisionSystem detects and resolves collisions between entities.
-type CollisionSystem struct{}
+// LandEvent is the Data of an ecs.EventPlayerLand published by
+// CollisionSystem.
+type LandEvent struct {
+	Speed float32 // Downward speed just before landing
+}
+
+// broadphaseReach is how far beyond an entity's bounds solids are checked.
+// It is well over what the shipped speeds cover in a MaxPhysicsStep (a
+// 900 units/s fall moves 30), so an entity cannot be pushed into a solid
+// that was not checked; anything faster widens its own area by how far it
+// moved (see collisionReach).
+const broadphaseReach = BroadphaseCellSize
+
+// collisionContact is a contact made by the last collision pass: where it
+// was made and the normal of the surface the entity was pushed out of.
+type collisionContact struct {
+	Point  rl.Vector2
+	Normal rl.Vector2
+}
+
+// CollisionSystem detects and resolves collisions between entities. Solids
+// are sorted into a broadphase grid and entities are only resolved against
+// the solids near them. Without a grid (the zero value)
+// every entity is resolved against every solid.
+//
+// Registered on its own it resolves once per frame; a PhysicsSystem created
+// with it resolves after every physics step instead.
+type CollisionSystem struct {
+	solids     []*ecs.Entity // Solids in ID order, gathered each frame without a grid
+	grid       *broadphase
+	candidates []*ecs.Entity // Solids near the entity being resolved
+	headroom   []*ecs.Entity // Solids near a shrunk collider's headroom strip
+
+	// Last pass, kept for the debug overlay
+	contacts []collisionContact
+	checked  []rl.Rectangle // Broadphase area checked for each entity
+}
 
 // NewCollisionSystem creates a new CollisionSystem.
 func NewCollisionSystem() *CollisionSystem {
-	return &CollisionSystem{}
+	return &CollisionSystem{grid: newBroadphase()}
 }
 
 // Update checks and resolves collisions for all collidable entities.
 func (s *CollisionSystem) Update(world *ecs.World, dt float32) {
+	// The overlay shows the last pass, also while a hit-stop holds it
+	defer s.drawDebug(FindDebugDraw(world))
+
+	// Nothing moved during a hit-stop; keep last frame's contacts
+	if dt <= 0 {
+		return
+	}
+
+	// Match the step the physics system integrated this frame
+	if dt > MaxPhysicsStep {
+		dt = MaxPhysicsStep
+	}
+
+	s.gatherSolids(world)
+	s.resolve(world, dt)
+}
+
+// gatherSolids collects the solid entities: tiles (those with a trigger
+// collider are not solid) and moving platforms. With a grid, tiles are
+// sorted into it once per world, again only when the number of tiles
+// changes, and the grid skips inactive ones; platforms are re-inserted
+// every frame. It holds for every step of a frame: a platform moving in the
+// steps stays far inside broadphaseReach of the cells it was sorted into.
+func (s *CollisionSystem) gatherSolids(world *ecs.World) {
 	transformStore, ok1 := ecs.GetStore[*components.TransformComponent](world.Components)
 	colliderStore, ok2 := ecs.GetStore[*components.ColliderComponent](world.Components)
-	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
 	tileStore, _ := ecs.GetStore[*components.TileComponent](world.Components)
+	platformStore, _ := ecs.GetStore[*components.PlatformComponent](world.Components)
 
+	s.solids = s.solids[:0]
 	if !ok1 || !ok2 {
 		return
 	}
 
-	// Get all tile entities for collision checking
-	tileEntities := make([]*ecs.Entity, 0)
+	tiles := 0
+	if tileStore != nil {
+		tiles = tileStore.Len()
+	}
+	if s.grid != nil && s.grid.staticFor(world, tiles) {
+		s.grid.clearDynamic()
+		s.insertPlatforms(world, platformStore, transformStore, colliderStore)
+		return
+	}
+
 	if tileStore != nil {
 		for _, id := range tileStore.All() {
+			entity := world.GetEntity(id)
+			if entity == nil || (s.grid == nil && !entity.Active) {
+				continue
+			}
+			if collider, ok := colliderStore.Get(id); ok && collider.IsTrigger {
+				continue
+			}
+			s.solids = append(s.solids, entity)
+		}
+	}
+
+	if s.grid != nil {
+		s.grid.buildStatic(world, tiles, s.solids, func(solid *ecs.Entity) (rl.Rectangle, bool) {
+			return solidBounds(transformStore, colliderStore, solid)
+		})
+		s.solids = s.solids[:0]
+		s.grid.clearDynamic()
+		s.insertPlatforms(world, platformStore, transformStore, colliderStore)
+		return
+	}
+
+	if platformStore != nil {
+		for _, id := range platformStore.All() {
 			entity := world.GetEntity(id)
 			if entity != nil && entity.Active {
-				tileEntities = append(tileEntities, entity)
+				s.solids = append(s.solids, entity)
 			}
 		}
 	}
 
-	// Check collisions for each non-tile entity
+	// Store order is random; a fixed order resolves overlaps the same way
+	// every run, as the grid does (see broadphase.query)
+	sort.Slice(s.solids, func(i, j int) bool { return s.solids[i].ID < s.solids[j].ID })
+}
+
+// insertPlatforms lists the moving platforms in the grid's dynamic cells.
+func (s *CollisionSystem) insertPlatforms(world *ecs.World, platformStore *ecs.ComponentStore[*components.PlatformComponent], transformStore *ecs.ComponentStore[*components.TransformComponent], colliderStore *ecs.ComponentStore[*components.ColliderComponent]) {
+	if platformStore == nil {
+		return
+	}
+	for _, id := range platformStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil {
+			continue
+		}
+		if bounds, ok := solidBounds(transformStore, colliderStore, entity); ok {
+			s.grid.insertDynamic(entity, bounds)
+		}
+	}
+}
+
+// solidBounds returns a solid's collider in world space, or false if it has
+// no transform or collider.
+func solidBounds(transformStore *ecs.ComponentStore[*components.TransformComponent], colliderStore *ecs.ComponentStore[*components.ColliderComponent], solid *ecs.Entity) (rl.Rectangle, bool) {
+	transform, ok := transformStore.Get(solid.ID)
+	if !ok {
+		return rl.Rectangle{}, false
+	}
+	collider, ok := colliderStore.Get(solid.ID)
+	if !ok {
+		return rl.Rectangle{}, false
+	}
+	return collider.GetWorldBounds(transform.Position), true
+}
+
+// resolve pushes every non-solid entity out of the gathered solids after it
+// moved for dt.
+func (s *CollisionSystem) resolve(world *ecs.World, dt float32) {
+	transformStore, ok1 := ecs.GetStore[*components.TransformComponent](world.Components)
+	colliderStore, ok2 := ecs.GetStore[*components.ColliderComponent](world.Components)
+	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
+	tileStore, _ := ecs.GetStore[*components.TileComponent](world.Components)
+	platformStore, _ := ecs.GetStore[*components.PlatformComponent](world.Components)
+
+	if !ok1 || !ok2 {
+		return
+	}
+
+	s.contacts = s.contacts[:0]
+	s.checked = s.checked[:0]
+
+	ground := groundStores{tiles: tileStore, platforms: platformStore, transforms: transformStore}
+
+	// Check collisions for each non-solid entity
 	for _, id := range colliderStore.All() {
-		// Skip tiles (they don't move)
+		// Skip solids (they are moved by their own systems, if at all)
 		if tileStore != nil && tileStore.Has(id) {
 			continue
 		}
+		if platformStore != nil && platformStore.Has(id) {
+			continue
+		}
 
 		entity := world.GetEntity(id)
 		if entity == nil || !entity.Active {
@@ -55,73 +205,476 @@ func (s *CollisionSystem) Update(world *ecs.World, dt float32) {
 
 		transform, _ := transformStore.Get(id)
 		collider, _ := colliderStore.Get(id)
+		// Triggers such as coins only report overlaps; nothing pushes them
+		if collider.IsTrigger {
+			continue
+		}
+		fallSpeed := transform.Velocity.Y
 
-		// Reset ground state
 		hasPhysics := physicsStore != nil && physicsStore.Has(id)
+		var physics *components.PhysicsComponent
 		if hasPhysics {
-			physics, _ := physicsStore.Get(id)
+			physics, _ = physicsStore.Get(id)
+		}
+
+		// Read before the ground state is reset; a rider moved with its platform
+		reach := collisionReach(transform, physics, dt)
+
+		// Reset ground state
+		wasOnGround := false
+		if hasPhysics {
+			wasOnGround = physics.IsOnGround
 			physics.IsOnGround = false
+			physics.OnOneWayPlatform = false
+			physics.SurfaceFriction = 1
+			physics.GroundEntityID = 0
+			physics.GroundVelocity = rl.Vector2{}
+			physics.WallContact = 0
 		}
 
+		// Only the solids around the entity can touch it this step
+		candidates := s.solids
+		if s.grid != nil {
+			bounds := collider.GetWorldBounds(transform.Position)
+			area := rl.Rectangle{
+				X:      bounds.X - reach,
+				Y:      bounds.Y - reach,
+				Width:  bounds.Width + 2*reach,
+				Height: bounds.Height + 2*reach,
+			}
+			s.candidates = s.grid.query(area, s.candidates)
+			s.checked = append(s.checked, area)
+			candidates = s.candidates
+		}
+
+		// Slope pass: put entities on ramp surfaces before the box passes run
+		s.resolveSlopes(transformStore, colliderStore, ground, candidates, transform, collider, physics, wasOnGround, dt)
+
 		// First pass: Resolve vertical collisions
-		for _, tileEntity := range tileEntities {
-			tileTransform, ok := transformStore.Get(tileEntity.ID)
+		for _, solidEntity := range candidates {
+			solidTransform, ok := transformStore.Get(solidEntity.ID)
 			if !ok {
 				continue
 			}
-			tileCollider, ok := colliderStore.Get(tileEntity.ID)
-			if !ok {
+			solidCollider, ok := colliderStore.Get(solidEntity.ID)
+			if !ok || solidCollider.Slope != components.SlopeNone {
 				continue
 			}
 
 			entityBounds := collider.GetWorldBounds(transform.Position)
-			tileBounds := tileCollider.GetWorldBounds(tileTransform.Position)
+			solidBounds := solidCollider.GetWorldBounds(solidTransform.Position)
 
-			collisionDir := checkCollisionDirection(entityBounds, tileBounds)
+			// One-way platforms only block from above
+			if solidCollider.OneWay {
+				dropping := hasPhysics && physics.DropThroughTimer > 0
+				relativeVelocityY := transform.Velocity.Y - solidTransform.Velocity.Y
+				if dropping || !landsOnOneWay(entityBounds, solidBounds, relativeVelocityY, dt) {
+					continue
+				}
+
+				transform.Position.Y -= entityBounds.Y + entityBounds.Height - solidBounds.Y
+				if transform.Velocity.Y > 0 {
+					transform.Velocity.Y = 0
+				}
+				s.contacts = append(s.contacts, collisionContact{
+					Point:  rl.Vector2{X: entityBounds.X + entityBounds.Width/2, Y: solidBounds.Y},
+					Normal: rl.Vector2{Y: -1},
+				})
+
+				if hasPhysics {
+					ground.land(physics, solidEntity.ID)
+					physics.OnOneWayPlatform = true
+				}
+				continue
+			}
+
+			collisionDir := checkCollisionDirection(entityBounds, solidBounds)
 			if collisionDir.Y != 0 {
-				collisionRec := rl.GetCollisionRec(entityBounds, tileBounds)
+				collisionRec := rl.GetCollisionRec(entityBounds, solidBounds)
 				correctionY := collisionDir.Y * collisionRec.Height
 				transform.Position.Y += correctionY
-				
+				s.contacts = append(s.contacts, collisionContact{Point: rectCentre(collisionRec), Normal: collisionDir})
+
 				if collisionDir.Y*transform.Velocity.Y < 0 {
 					transform.Velocity.Y = 0
 				}
 
 				if collisionDir.Y == -1 && hasPhysics {
-					physics, _ := physicsStore.Get(id)
-					physics.IsOnGround = true
+					ground.land(physics, solidEntity.ID)
 				}
 			}
 		}
 
 		// Second pass: Resolve horizontal collisions
-		for _, tileEntity := range tileEntities {
-			tileTransform, ok := transformStore.Get(tileEntity.ID)
+		for _, solidEntity := range candidates {
+			solidTransform, ok := transformStore.Get(solidEntity.ID)
 			if !ok {
 				continue
 			}
-			tileCollider, ok := colliderStore.Get(tileEntity.ID)
-			if !ok {
+			solidCollider, ok := colliderStore.Get(solidEntity.ID)
+			if !ok || solidCollider.OneWay || solidCollider.Slope != components.SlopeNone {
 				continue
 			}
 
 			entityBounds := collider.GetWorldBounds(transform.Position)
-			tileBounds := tileCollider.GetWorldBounds(tileTransform.Position)
+			solidBounds := solidCollider.GetWorldBounds(solidTransform.Position)
 
-			collisionDir := checkCollisionDirection(entityBounds, tileBounds)
+			collisionDir := checkCollisionDirection(entityBounds, solidBounds)
 			if collisionDir.X != 0 {
-				collisionRec := rl.GetCollisionRec(entityBounds, tileBounds)
+				collisionRec := rl.GetCollisionRec(entityBounds, solidBounds)
 				correctionX := collisionDir.X * collisionRec.Width
 				transform.Position.X += correctionX
+				s.contacts = append(s.contacts, collisionContact{Point: rectCentre(collisionRec), Normal: collisionDir})
 
 				if collisionDir.X*transform.Velocity.X < 0 {
 					transform.Velocity.X = 0
 				}
+
+				// Being pushed right means the wall is on the left, and vice versa
+				if hasPhysics {
+					physics.WallContact = -int(collisionDir.X)
+				}
 			}
 		}
+
+		if hasPhysics && !wasOnGround && physics.IsOnGround && entity.HasTag("player") {
+			world.Events.Publish(ecs.Event{
+				Type:   ecs.EventPlayerLand,
+				Source: id,
+				Data:   LandEvent{Speed: fallSpeed},
+			})
+		}
 	}
 }
 
+// drawDebug queues the occupied broadphase cells, the area checked around
+// each entity and the contact normals of the last pass.
+func (s *CollisionSystem) drawDebug(debug *components.DebugDrawComponent) {
+	if debug == nil {
+		return
+	}
+	if s.grid != nil {
+		s.grid.occupied(func(cell rl.Rectangle) {
+			debug.Rect(cell, debugCellColor)
+		})
+		for _, area := range s.checked {
+			first, last := s.grid.cellRange(area)
+			cells := broadphaseCellBounds(first)
+			cells.Width = float32(last.X-first.X+1) * BroadphaseCellSize
+			cells.Height = float32(last.Y-first.Y+1) * BroadphaseCellSize
+			debug.Rect(cells, debugCheckedColor)
+		}
+	}
+	for _, contact := range s.contacts {
+		tip := rl.Vector2{
+			X: contact.Point.X + contact.Normal.X*debugNormalLength,
+			Y: contact.Point.Y + contact.Normal.Y*debugNormalLength,
+		}
+		debug.Arrow(contact.Point, tip, debugContactColor)
+	}
+}
+
+// collisionReach returns how far beyond an entity's bounds solids have to
+// be checked: broadphaseReach, or how far the entity moved this step if that
+// is more. A rider also moved with the platform it stood on.
+func collisionReach(transform *components.TransformComponent, physics *components.PhysicsComponent, dt float32) float32 {
+	velocity := transform.Velocity
+	if physics != nil {
+		velocity = rl.Vector2Add(velocity, physics.GroundVelocity)
+	}
+	moved := (float32(math.Abs(float64(velocity.X))) + float32(math.Abs(float64(velocity.Y)))) * dt
+	return max(broadphaseReach, moved)
+}
+
+// groundStores holds the stores needed to describe what an entity stands on.
+type groundStores struct {
+	tiles      *ecs.ComponentStore[*components.TileComponent]
+	platforms  *ecs.ComponentStore[*components.PlatformComponent]
+	transforms *ecs.ComponentStore[*components.TransformComponent]
+}
+
+// land marks an entity as standing on a solid, picking up the tile's surface
+// friction or the moving platform it now rides.
+func (g groundStores) land(physics *components.PhysicsComponent, solidID ecs.EntityID) {
+	physics.IsOnGround = true
+
+	if g.tiles != nil {
+		if tile, ok := g.tiles.Get(solidID); ok && tile.Friction > 0 {
+			physics.SurfaceFriction = tile.Friction
+		}
+	}
+
+	if g.platforms != nil && g.platforms.Has(solidID) {
+		physics.GroundEntityID = uint32(solidID)
+		if platformTransform, ok := g.transforms.Get(solidID); ok {
+			physics.GroundVelocity = platformTransform.Velocity
+		}
+	}
+}
+
+// hasHeadroom reports whether an entity with a shrunk collider has room to
+// return to its standing size: no blocking solid may overlap the strip
+// between its current top edge and its standing top edge. The solids near
+// the strip come from collision's grid, gathered first if it was not built
+// for this world's tiles; without a grid (or a collision system) every
+// solid is gathered and checked.
+func hasHeadroom(world *ecs.World, collision *CollisionSystem, collider *components.ColliderComponent, position rl.Vector2) bool {
+	transformStore, ok1 := ecs.GetStore[*components.TransformComponent](world.Components)
+	colliderStore, ok2 := ecs.GetStore[*components.ColliderComponent](world.Components)
+	tileStore, _ := ecs.GetStore[*components.TileComponent](world.Components)
+
+	if !ok1 || !ok2 {
+		return true
+	}
+
+	standing := collider.StandingBounds
+	strip := rl.Rectangle{
+		X:      position.X + standing.X,
+		Y:      position.Y + standing.Y,
+		Width:  standing.Width,
+		Height: collider.Bounds.Y - standing.Y,
+	}
+	if strip.Height <= 0 {
+		return true
+	}
+
+	if collision == nil {
+		collision = &CollisionSystem{}
+	}
+	tiles := 0
+	if tileStore != nil {
+		tiles = tileStore.Len()
+	}
+	if collision.grid == nil || !collision.grid.staticFor(world, tiles) {
+		collision.gatherSolids(world)
+	}
+
+	candidates := collision.solids
+	if collision.grid != nil {
+		// Platforms may have moved since they were sorted into the grid
+		area := rl.Rectangle{
+			X:      strip.X - broadphaseReach,
+			Y:      strip.Y - broadphaseReach,
+			Width:  strip.Width + 2*broadphaseReach,
+			Height: strip.Height + 2*broadphaseReach,
+		}
+		collision.headroom = collision.grid.query(area, collision.headroom)
+		candidates = collision.headroom
+	}
+
+	for _, solid := range candidates {
+		if !solid.Active {
+			continue
+		}
+		solidCollider, ok := colliderStore.Get(solid.ID)
+		if !ok || solidCollider.OneWay || solidCollider.IsTrigger {
+			continue
+		}
+		if bounds, ok := solidBounds(transformStore, colliderStore, solid); ok && rl.CheckCollisionRecs(strip, bounds) {
+			return false
+		}
+	}
+	return true
+}
+
+// oneWayTolerance is how far (in world units) an entity's feet may already be
+// below a one-way platform's top edge and still land on it.
+const oneWayTolerance = 2.0
+
+// landsOnOneWay reports whether an entity overlapping a one-way platform
+// should be pushed on top of it: it must be falling (or resting) and its feet
+// must have been above the platform's top edge before moving for dt.
+func landsOnOneWay(entity, platform rl.Rectangle, velocityY, dt float32) bool {
+	entityLeft, entityTop, entityRight, entityBottom := getEdges(entity)
+	platformLeft, platformTop, platformRight, platformBottom := getEdges(platform)
+
+	if entityRight <= platformLeft || entityLeft >= platformRight {
+		return false
+	}
+	if entityBottom < platformTop || entityTop >= platformBottom {
+		return false
+	}
+	if velocityY < 0 {
+		return false
+	}
+
+	previousBottom := entityBottom - velocityY*dt
+	return previousBottom <= platformTop+oneWayTolerance
+}
+
+// resolveSlopes resolves an entity against every slope collider. Floor, ceiling
+// and wall contacts are applied as they are found; if the entity didn't land
+// on any slope but was grounded last frame, it is glued down onto the highest
+// slope surface within reach.
+func (s *CollisionSystem) resolveSlopes(
+	transformStore *ecs.ComponentStore[*components.TransformComponent],
+	colliderStore *ecs.ComponentStore[*components.ColliderComponent],
+	ground groundStores,
+	solidEntities []*ecs.Entity,
+	transform *components.TransformComponent,
+	collider *components.ColliderComponent,
+	physics *components.PhysicsComponent,
+	wasOnGround bool,
+	dt float32,
+) {
+	landed := false
+	land := func(solidID ecs.EntityID) {
+		landed = true
+		if transform.Velocity.Y > 0 {
+			transform.Velocity.Y = 0
+		}
+		if physics != nil {
+			ground.land(physics, solidID)
+		}
+	}
+
+	for _, solidEntity := range solidEntities {
+		solidTransform, ok := transformStore.Get(solidEntity.ID)
+		if !ok {
+			continue
+		}
+		solidCollider, ok := colliderStore.Get(solidEntity.ID)
+		if !ok || solidCollider.Slope == components.SlopeNone {
+			continue
+		}
+
+		entityBounds := collider.GetWorldBounds(transform.Position)
+		correction, contact := resolveSlope(entityBounds, solidCollider, solidTransform.Position, transform.Velocity, dt, false)
+
+		transform.Position.X += correction.X
+		transform.Position.Y += correction.Y
+
+		feet := rl.Vector2{X: entityBounds.X + entityBounds.Width/2, Y: entityBounds.Y + entityBounds.Height + correction.Y}
+		switch contact {
+		case slopeFloor:
+			land(solidEntity.ID)
+			s.contacts = append(s.contacts, collisionContact{Point: feet, Normal: slopeNormal(solidCollider, solidTransform.Position)})
+		case slopeCeiling:
+			if transform.Velocity.Y < 0 {
+				transform.Velocity.Y = 0
+			}
+			top := rl.Vector2{X: feet.X, Y: entityBounds.Y + correction.Y}
+			s.contacts = append(s.contacts, collisionContact{Point: top, Normal: rl.Vector2{Y: 1}})
+		case slopeWall:
+			if correction.X*transform.Velocity.X < 0 {
+				transform.Velocity.X = 0
+			}
+			side := rl.Vector2{X: entityBounds.X + correction.X, Y: entityBounds.Y + entityBounds.Height/2}
+			normal := rl.Vector2{X: 1}
+			if correction.X < 0 {
+				side.X += entityBounds.Width
+				normal.X = -1
+			}
+			s.contacts = append(s.contacts, collisionContact{Point: side, Normal: normal})
+		}
+	}
+
+	if landed || !wasOnGround || transform.Velocity.Y < 0 {
+		return
+	}
+
+	// Glue to the nearest surface below when walking downhill
+	var glueSolid ecs.EntityID
+	var glueNormal rl.Vector2
+	glueY := float32(0)
+	for _, solidEntity := range solidEntities {
+		solidTransform, ok := transformStore.Get(solidEntity.ID)
+		if !ok {
+			continue
+		}
+		solidCollider, ok := colliderStore.Get(solidEntity.ID)
+		if !ok || solidCollider.Slope == components.SlopeNone {
+			continue
+		}
+
+		entityBounds := collider.GetWorldBounds(transform.Position)
+		correction, contact := resolveSlope(entityBounds, solidCollider, solidTransform.Position, transform.Velocity, dt, true)
+		if contact != slopeFloor || correction.Y <= 0 {
+			continue
+		}
+		if glueSolid == 0 || correction.Y < glueY {
+			glueSolid = solidEntity.ID
+			glueNormal = slopeNormal(solidCollider, solidTransform.Position)
+			glueY = correction.Y
+		}
+	}
+
+	if glueSolid != 0 {
+		transform.Position.Y += glueY
+		land(glueSolid)
+		bounds := collider.GetWorldBounds(transform.Position)
+		feet := rl.Vector2{X: bounds.X + bounds.Width/2, Y: bounds.Y + bounds.Height}
+		s.contacts = append(s.contacts, collisionContact{Point: feet, Normal: glueNormal})
+	}
+}
+
+// slopeSnapPadding is extra distance (in world units) beyond this frame's
+// movement within which an entity is snapped onto a slope surface.
+const slopeSnapPadding = 2.0
+
+// slopeContact describes how an entity was resolved against a slope tile.
+type slopeContact int
+
+const (
+	slopeNoContact slopeContact = iota
+	slopeFloor
+	slopeCeiling
+	slopeWall
+)
+
+// resolveSlope returns the position correction that keeps an entity on top of
+// a slope tile, and the kind of contact made. The entity rests on the highest
+// surface point under its footprint, like a box on a ramp. When glue is set
+// (the entity was grounded and is not rising) it is also pulled down onto the
+// surface so walking downhill doesn't turn into a series of small falls.
+// Deeper overlaps are pushed out of the tile's flat bottom or its sides.
+func resolveSlope(entity rl.Rectangle, tileCollider *components.ColliderComponent, tilePosition rl.Vector2, velocity rl.Vector2, dt float32, glue bool) (rl.Vector2, slopeContact) {
+	tile := tileCollider.GetWorldBounds(tilePosition)
+	entityLeft, entityTop, entityRight, entityBottom := getEdges(entity)
+	tileLeft, _, tileRight, tileBottom := getEdges(tile)
+
+	if entityRight <= tileLeft || entityLeft >= tileRight {
+		return rl.Vector2{}, slopeNoContact
+	}
+
+	// Highest surface point under the entity's footprint
+	leftHeight, rightHeight := tileCollider.Slope.EdgeHeights()
+	sampleX := float32(math.Max(float64(entityLeft), float64(tileLeft)))
+	if rightHeight >= leftHeight {
+		sampleX = float32(math.Min(float64(entityRight), float64(tileRight)))
+	}
+	surface := tileCollider.SurfaceY(tilePosition, sampleX)
+
+	snap := (float32(math.Abs(float64(velocity.X)))+float32(math.Abs(float64(velocity.Y))))*dt + slopeSnapPadding
+
+	if velocity.Y >= 0 {
+		penetration := entityBottom - surface
+		if penetration >= 0 && penetration <= snap && entityTop < surface {
+			return rl.Vector2{Y: -penetration}, slopeFloor
+		}
+		if glue && penetration < 0 && -penetration <= snap {
+			return rl.Vector2{Y: -penetration}, slopeFloor
+		}
+	}
+
+	// Not inside the solid part under the surface
+	if entityBottom <= surface || entityTop >= tileBottom {
+		return rl.Vector2{}, slopeNoContact
+	}
+
+	if velocity.Y < 0 && tileBottom-entityTop <= snap {
+		return rl.Vector2{Y: tileBottom - entityTop}, slopeCeiling
+	}
+
+	// Push out through the nearer side
+	pushRight := tileRight - entityLeft
+	pushLeft := entityRight - tileLeft
+	if pushRight < pushLeft {
+		return rl.Vector2{X: pushRight}, slopeWall
+	}
+	return rl.Vector2{X: -pushLeft}, slopeWall
+}
+
 // checkCollisionDirection returns the direction of collision between two rectangles.
 func checkCollisionDirection(r1, r2 rl.Rectangle) rl.Vector2 {
 	r1Left, r1Top, r1Right, r1Bottom := getEdges(r1)
@@ -158,6 +711,23 @@ func checkCollisionDirection(r1, r2 rl.Rectangle) rl.Vector2 {
 	return unitVec
 }
 
+// slopeNormal returns the unit normal of a slope tile's surface, pointing
+// out of the tile.
+func slopeNormal(tileCollider *components.ColliderComponent, tilePosition rl.Vector2) rl.Vector2 {
+	tile := tileCollider.GetWorldBounds(tilePosition)
+	rise := tileCollider.SurfaceY(tilePosition, tile.X+tile.Width) - tileCollider.SurfaceY(tilePosition, tile.X)
+	length := float32(math.Hypot(float64(rise), float64(tile.Width)))
+	if length == 0 {
+		return rl.Vector2{Y: -1}
+	}
+	return rl.Vector2{X: rise / length, Y: -tile.Width / length}
+}
+
+// rectCentre returns the centre of a rectangle.
+func rectCentre(r rl.Rectangle) rl.Vector2 {
+	return rl.Vector2{X: r.X + r.Width/2, Y: r.Y + r.Height/2}
+}
+
 // getEdges returns the edges of a rectangle: left, top, right, bottom.
 func getEdges(r rl.Rectangle) (float32, float32, float32, float32) {
 	return r.X, r.Y, r.X + r.Width, r.Y + r.Height
@@ -0,0 +1,487 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestResolveSlope(t *testing.T) {
+	// 16x16 tiles at the origin; the entity is 8x16
+	const dt = 1.0 / 60
+	tests := []struct {
+		name       string
+		shape      components.SlopeShape
+		entity     rl.Rectangle
+		velocity   rl.Vector2
+		glue       bool
+		want       rl.Vector2
+		wantResult slopeContact
+	}{
+		{
+			name:       "lands on a 45 rise",
+			shape:      components.Slope45Up,
+			entity:     rl.Rectangle{X: 4, Y: -11, Width: 8, Height: 16}, // Bottom 5, surface at x=12 is 4
+			velocity:   rl.Vector2{Y: 100},
+			want:       rl.Vector2{Y: -1},
+			wantResult: slopeFloor,
+		},
+		{
+			name:       "samples the high side of a 45 fall",
+			shape:      components.Slope45Down,
+			entity:     rl.Rectangle{X: 4, Y: -12, Width: 8, Height: 16}, // Surface at x=4 is 4
+			velocity:   rl.Vector2{Y: 100},
+			wantResult: slopeFloor,
+		},
+		{
+			name:       "lands on the low half of a 2:1 rise",
+			shape:      components.Slope2to1UpLow,
+			entity:     rl.Rectangle{X: 8, Y: -2, Width: 8, Height: 16}, // Bottom 14, surface at x=16 is 8
+			velocity:   rl.Vector2{Y: 400},
+			want:       rl.Vector2{Y: -6},
+			wantResult: slopeFloor,
+		},
+		{
+			name:       "lands on the high half of a 2:1 fall",
+			shape:      components.Slope2to1DownHigh,
+			entity:     rl.Rectangle{X: 0, Y: -15, Width: 8, Height: 16}, // Bottom 1, surface at x=0 is 0
+			velocity:   rl.Vector2{Y: 100},
+			want:       rl.Vector2{Y: -1},
+			wantResult: slopeFloor,
+		},
+		{
+			name:       "above the surface does not touch",
+			shape:      components.Slope2to1UpHigh,
+			entity:     rl.Rectangle{X: 4, Y: -20, Width: 8, Height: 16},
+			velocity:   rl.Vector2{Y: 10},
+			wantResult: slopeNoContact,
+		},
+		{
+			name:       "glue pulls a walker down the surface",
+			shape:      components.Slope45Down,
+			entity:     rl.Rectangle{X: 8, Y: -13, Width: 8, Height: 16}, // Bottom 3, surface at x=8 is 8
+			velocity:   rl.Vector2{X: 240},
+			glue:       true,
+			want:       rl.Vector2{Y: 5},
+			wantResult: slopeFloor,
+		},
+		{
+			name:       "beside the tile does not touch",
+			shape:      components.Slope45Up,
+			entity:     rl.Rectangle{X: 16, Y: 0, Width: 8, Height: 16},
+			velocity:   rl.Vector2{Y: 100},
+			wantResult: slopeNoContact,
+		},
+		{
+			name:       "rising into the underside hits the ceiling",
+			shape:      components.Slope45Up,
+			entity:     rl.Rectangle{X: 4, Y: 14, Width: 8, Height: 16},
+			velocity:   rl.Vector2{Y: -300},
+			want:       rl.Vector2{Y: 2},
+			wantResult: slopeCeiling,
+		},
+		{
+			name:       "walking into the low side pushes out as a wall",
+			shape:      components.Slope2to1DownLow,
+			entity:     rl.Rectangle{X: -6, Y: 0, Width: 8, Height: 16}, // Deep inside the solid part
+			velocity:   rl.Vector2{X: 60},
+			want:       rl.Vector2{X: -2},
+			wantResult: slopeWall,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collider := &components.ColliderComponent{Bounds: rl.Rectangle{Width: 16, Height: 16}, Slope: tt.shape}
+			got, result := resolveSlope(tt.entity, collider, rl.Vector2{}, tt.velocity, dt, tt.glue)
+			if result != tt.wantResult || got != tt.want {
+				t.Errorf("resolveSlope = %v, %v; want %v, %v", got, result, tt.want, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestLandsOnOneWay(t *testing.T) {
+	platform := rl.Rectangle{X: 0, Y: 100, Width: 48, Height: 8}
+	const dt = 1.0 / 60
+	tests := []struct {
+		name      string
+		entity    rl.Rectangle
+		velocityY float32
+		want      bool
+	}{
+		{"falling onto the top", rl.Rectangle{X: 8, Y: 86, Width: 16, Height: 16}, 240, true},
+		{"resting on the top", rl.Rectangle{X: 8, Y: 84, Width: 16, Height: 16}, 0, true},
+		{"within the tolerance", rl.Rectangle{X: 8, Y: 87, Width: 16, Height: 16}, 60, true},
+		{"rising through", rl.Rectangle{X: 8, Y: 90, Width: 16, Height: 16}, -200, false},
+		{"falling from inside", rl.Rectangle{X: 8, Y: 90, Width: 16, Height: 16}, 60, false},
+		{"above without touching", rl.Rectangle{X: 8, Y: 80, Width: 16, Height: 16}, 60, false},
+		{"beside the platform", rl.Rectangle{X: 48, Y: 86, Width: 16, Height: 16}, 240, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := landsOnOneWay(tt.entity, platform, tt.velocityY, dt); got != tt.want {
+				t.Errorf("landsOnOneWay = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropThroughOneWay(t *testing.T) {
+	tests := []struct {
+		name     string
+		oneWay   bool
+		downHeld bool
+		wantY    float32 // Where the body rests afterwards
+		wantRise bool    // Whether it jumped
+	}{
+		{"down and jump drops through", true, true, 440, false},
+		{"jump alone jumps off the platform", true, false, 340, true},
+		{"down and jump on a solid tile jumps", false, true, 340, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world := ecs.NewWorld()
+			transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+			colliderStore := ecs.RegisterStore[*components.ColliderComponent](world.Components)
+			physicsStore := ecs.RegisterStore[*components.PhysicsComponent](world.Components)
+			inputStore := ecs.RegisterStore[*components.InputComponent](world.Components)
+			tileStore := ecs.RegisterStore[*components.TileComponent](world.Components)
+
+			// A ledge over a floor
+			addTile := func(x, y float32, oneWay bool) {
+				entity := world.CreateEntity("tile")
+				transformStore.Add(entity.ID, &components.TransformComponent{Position: rl.Vector2{X: x, Y: y}})
+				colliderStore.Add(entity.ID, &components.ColliderComponent{Bounds: rl.Rectangle{Width: 32, Height: 32}, OneWay: oneWay})
+				tileStore.Add(entity.ID, &components.TileComponent{Friction: 1})
+			}
+			for x := float32(0); x < 128; x += 32 {
+				addTile(x, 380, tt.oneWay)
+				addTile(x, 480, false)
+			}
+
+			body := world.CreateEntity("player")
+			transform := &components.TransformComponent{Position: rl.Vector2{X: 40, Y: 340}}
+			bounds := rl.Rectangle{Width: 40, Height: 40}
+			physics := &components.PhysicsComponent{
+				Gravity:         1800,
+				JumpForce:       600,
+				MaxFallSpeed:    900,
+				JumpBufferTime:  0.12,
+				DropThroughTime: 0.2,
+			}
+			input := &components.InputComponent{}
+			transformStore.Add(body.ID, transform)
+			colliderStore.Add(body.ID, &components.ColliderComponent{Bounds: bounds, StandingBounds: bounds})
+			physicsStore.Add(body.ID, physics)
+			inputStore.Add(body.ID, input)
+			world.AddSystem(NewPhysicsSystem(nil, NewCollisionSystem()))
+
+			// Settle on the ledge, then press
+			world.Update(1.0 / 60)
+			if !physics.IsOnGround || physics.OnOneWayPlatform != tt.oneWay {
+				t.Fatalf("on ground %v, on one-way %v before the press", physics.IsOnGround, physics.OnOneWayPlatform)
+			}
+			input.DownHeld = tt.downHeld
+			input.JumpPressed = true
+			world.Update(1.0 / 60)
+			input.JumpPressed = false
+			input.DownHeld = false
+
+			if rose := transform.Velocity.Y < 0; rose != tt.wantRise {
+				t.Errorf("velocity %.2f after the press, want rising %v", transform.Velocity.Y, tt.wantRise)
+			}
+			for frame := 0; frame < 120; frame++ {
+				world.Update(1.0 / 60)
+			}
+			if transform.Position.Y != tt.wantY || !physics.IsOnGround {
+				t.Errorf("rests at y %v (on ground %v), want %v", transform.Position.Y, physics.IsOnGround, tt.wantY)
+			}
+		})
+	}
+}
+
+// collisionScene is a level with every kind of solid and the bodies moving
+// through it.
+type collisionScene struct {
+	world   *ecs.World
+	bodies  []*components.TransformComponent
+	addTile func(x, y float32, collider components.ColliderComponent) *ecs.Entity
+}
+
+// newCollisionScene builds the same level each call: a floor, a wall, a
+// ramp, a one-way ledge, a deep pit of stacked tiles and a moving platform,
+// with a walker, a faller, a rider and a body falling far faster than any
+// shipped speed.
+func newCollisionScene() *collisionScene {
+	const tile = 32
+	world := ecs.NewWorld()
+	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+	colliderStore := ecs.RegisterStore[*components.ColliderComponent](world.Components)
+	physicsStore := ecs.RegisterStore[*components.PhysicsComponent](world.Components)
+	inputStore := ecs.RegisterStore[*components.InputComponent](world.Components)
+	tileStore := ecs.RegisterStore[*components.TileComponent](world.Components)
+	platformStore := ecs.RegisterStore[*components.PlatformComponent](world.Components)
+
+	addTile := func(x, y float32, collider components.ColliderComponent) *ecs.Entity {
+		entity := world.CreateEntity("tile")
+		collider.Bounds = rl.Rectangle{Width: tile, Height: tile}
+		transformStore.Add(entity.ID, &components.TransformComponent{Position: rl.Vector2{X: x, Y: y}})
+		colliderStore.Add(entity.ID, &collider)
+		tileStore.Add(entity.ID, &components.TileComponent{Friction: 1})
+		return entity
+	}
+	for x := float32(-320); x < 1280; x += tile {
+		addTile(x, 480, components.ColliderComponent{})
+	}
+	for y := float32(352); y < 480; y += tile {
+		addTile(640, y, components.ColliderComponent{})
+	}
+	addTile(384, 448, components.ColliderComponent{Slope: components.Slope45Up})
+	addTile(200, 380, components.ColliderComponent{OneWay: true})
+	for y := float32(992); y >= 512; y -= tile {
+		addTile(960, y, components.ColliderComponent{})
+	}
+
+	platform := world.CreateEntity("platform")
+	platformStore.Add(platform.ID, &components.PlatformComponent{
+		Waypoints: []rl.Vector2{{X: 0, Y: 300}, {X: 160, Y: 300}},
+		Speed:     60,
+		PingPong:  true,
+	})
+	transformStore.Add(platform.ID, &components.TransformComponent{Position: rl.Vector2{X: 0, Y: 300}})
+	colliderStore.Add(platform.ID, &components.ColliderComponent{Bounds: rl.Rectangle{Width: 96, Height: 16}})
+
+	scene := &collisionScene{world: world, addTile: addTile}
+	addBody := func(position, velocity rl.Vector2, moveX, maxFallSpeed float32) {
+		entity := world.CreateEntity("player")
+		transform := &components.TransformComponent{Position: position, Velocity: velocity}
+		bounds := rl.Rectangle{Width: 40, Height: 40}
+		transformStore.Add(entity.ID, transform)
+		colliderStore.Add(entity.ID, &components.ColliderComponent{Bounds: bounds, StandingBounds: bounds})
+		physicsStore.Add(entity.ID, &components.PhysicsComponent{
+			Gravity:            1800,
+			MoveSpeed:          240,
+			GroundAcceleration: 2400,
+			GroundDeceleration: 3000,
+			AirControl:         0.6,
+			MaxFallSpeed:       maxFallSpeed,
+		})
+		inputStore.Add(entity.ID, &components.InputComponent{MoveX: moveX})
+		scene.bodies = append(scene.bodies, transform)
+	}
+	addBody(rl.Vector2{X: 100, Y: 430}, rl.Vector2{}, 1, 900)
+	addBody(rl.Vector2{X: 300, Y: -2000}, rl.Vector2{}, 0, 900)
+	addBody(rl.Vector2{X: 20, Y: 250}, rl.Vector2{}, -1, 900)
+	addBody(rl.Vector2{X: 956, Y: -100}, rl.Vector2{Y: 15000}, 0, 0)
+
+	return scene
+}
+
+func TestCollisionBroadphaseMatchesFullScan(t *testing.T) {
+	// Resolved once per frame, so the fast body moves far between passes
+	withGrid := newCollisionScene()
+	withGrid.world.AddSystem(NewPlatformSystem())
+	withGrid.world.AddSystem(NewPhysicsSystem(nil, nil))
+	withGrid.world.AddSystem(NewCollisionSystem())
+	fullScan := newCollisionScene()
+	fullScan.world.AddSystem(NewPlatformSystem())
+	fullScan.world.AddSystem(NewPhysicsSystem(nil, nil))
+	fullScan.world.AddSystem(&CollisionSystem{})
+
+	for frame := 0; frame < 120; frame++ {
+		withGrid.world.Update(MaxPhysicsStep)
+		fullScan.world.Update(MaxPhysicsStep)
+		for i := range withGrid.bodies {
+			got, want := withGrid.bodies[i], fullScan.bodies[i]
+			if got.Position != want.Position || got.Velocity != want.Velocity {
+				t.Fatalf("frame %d body %d: broadphase at %v moving %v, full scan at %v moving %v",
+					frame, i, got.Position, got.Velocity, want.Position, want.Velocity)
+			}
+		}
+	}
+
+	// The fast body sinks deeper into the pit in one step than the grid
+	// reaches; it must still be pushed all the way back up to the floor
+	if fast := withGrid.bodies[3]; fast.Position.Y != 440 {
+		t.Errorf("fast body rests at y %v, want 440 on the floor", fast.Position.Y)
+	}
+}
+
+func TestCollisionGridFollowsTileChanges(t *testing.T) {
+	withGrid := newCollisionScene()
+	withGrid.world.AddSystem(NewPhysicsSystem(NewPlatformSystem(), NewCollisionSystem()))
+	fullScan := newCollisionScene()
+	fullScan.world.AddSystem(NewPhysicsSystem(NewPlatformSystem(), &CollisionSystem{}))
+
+	for frame := 0; frame < 240; frame++ {
+		// Tiles built into the grid on the first frame change later: a
+		// ledge appears under the faller and the ramp is switched off
+		if frame == 10 {
+			for _, scene := range []*collisionScene{withGrid, fullScan} {
+				scene.addTile(300, 0, components.ColliderComponent{})
+				for _, entity := range scene.world.GetEntitiesWithTag("tile") {
+					if collider, _ := ecs.RegisterStore[*components.ColliderComponent](scene.world.Components).Get(entity.ID); collider.Slope != components.SlopeNone {
+						entity.Active = false
+					}
+				}
+			}
+		}
+		withGrid.world.Update(1.0 / 60)
+		fullScan.world.Update(1.0 / 60)
+		for i := range withGrid.bodies {
+			got, want := withGrid.bodies[i], fullScan.bodies[i]
+			if got.Position != want.Position || got.Velocity != want.Velocity {
+				t.Fatalf("frame %d body %d: broadphase at %v moving %v, full scan at %v moving %v",
+					frame, i, got.Position, got.Velocity, want.Position, want.Velocity)
+			}
+		}
+	}
+
+	if faller := withGrid.bodies[1]; faller.Position.Y != -40 {
+		t.Errorf("faller rests at y %v, want -40 on the added ledge", faller.Position.Y)
+	}
+	if walker := withGrid.bodies[0]; walker.Position.Y != 440 {
+		t.Errorf("walker at y %v, want 440 on the floor past the switched-off ramp", walker.Position.Y)
+	}
+}
+
+func TestCollisionLeavesTriggersInSolids(t *testing.T) {
+	for name, system := range map[string]*CollisionSystem{"broadphase": NewCollisionSystem(), "full scan": {}} {
+		t.Run(name, func(t *testing.T) {
+			world := ecs.NewWorld()
+			addTestTile(world, 0, 0, 0)
+			coin := addTestCoin(world, 4, 20)
+			transform, _ := ecs.RegisterStore[*components.TransformComponent](world.Components).Get(coin.ID)
+
+			system.Update(world, 1.0/60)
+
+			if transform.Position != (rl.Vector2{X: 4, Y: 20}) {
+				t.Errorf("coin pushed to %v, want it left where it was placed", transform.Position)
+			}
+		})
+	}
+}
+
+func TestHasHeadroom(t *testing.T) {
+	// A 40 unit collider crouched to 20 needs the 20 units above it clear
+	standing := rl.Rectangle{Width: 20, Height: 40}
+	collider := &components.ColliderComponent{Bounds: standing, StandingBounds: standing}
+	collider.SetHeight(20)
+
+	tests := []struct {
+		name     string
+		position rl.Vector2
+		want     bool
+	}{
+		{"in the open", rl.Vector2{X: 100, Y: 440}, true},
+		{"under a tile", rl.Vector2{X: 506, Y: 320}, false},
+		{"under the platform", rl.Vector2{X: 40, Y: 305}, false},
+		{"under the one-way ledge", rl.Vector2{X: 206, Y: 400}, true},
+		{"under a trigger tile", rl.Vector2{X: 706, Y: 320}, true},
+		{"beside the platform", rl.Vector2{X: 180, Y: 305}, true},
+	}
+	for name, collision := range map[string]*CollisionSystem{"broadphase": NewCollisionSystem(), "full scan": {}, "no collision system": nil} {
+		t.Run(name, func(t *testing.T) {
+			scene := newCollisionScene()
+			scene.addTile(500, 300, components.ColliderComponent{})
+			scene.addTile(700, 300, components.ColliderComponent{IsTrigger: true})
+			for _, tt := range tests {
+				if got := hasHeadroom(scene.world, collision, collider, tt.position); got != tt.want {
+					t.Errorf("%s: headroom %v, want %v", tt.name, got, tt.want)
+				}
+			}
+
+			// The platform moves over the body after it was gathered
+			platform := scene.world.GetEntitiesWithTag("platform")[0]
+			transform, _ := ecs.RegisterStore[*components.TransformComponent](scene.world.Components).Get(platform.ID)
+			transform.Position.X = 120
+			if hasHeadroom(scene.world, collision, collider, rl.Vector2{X: 180, Y: 305}) {
+				t.Error("headroom under the platform after it moved")
+			}
+		})
+	}
+}
+
+func TestHasHeadroomDoesNotAllocate(t *testing.T) {
+	scene := newCollisionScene()
+	collision := NewCollisionSystem()
+	standing := rl.Rectangle{Width: 20, Height: 40}
+	collider := &components.ColliderComponent{Bounds: standing, StandingBounds: standing}
+	collider.SetHeight(20)
+	hasHeadroom(scene.world, collision, collider, rl.Vector2{X: 40, Y: 305})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		hasHeadroom(scene.world, collision, collider, rl.Vector2{X: 40, Y: 305})
+	})
+	if allocs != 0 {
+		t.Errorf("%v allocations per check, want none once the grid is built", allocs)
+	}
+}
+
+func TestMovementIndependentOfFrameRate(t *testing.T) {
+	// Scripted input drives platforms, physics and collision run in every
+	// step; the bodies walk into a ramp and a wall, fall onto the floor,
+	// ride a platform off its end and drop into a pit
+	const seconds = 3
+	const samplesPerSecond = 6
+	samples := make([][][]rl.Vector2, len(testFrameRates))
+	for i, rate := range testFrameRates {
+		scene := newCollisionScene()
+		scene.world.AddSystem(NewPhysicsSystem(NewPlatformSystem(), NewCollisionSystem()))
+		frames := int(rate) * seconds
+		for frame := 1; frame <= frames; frame++ {
+			scene.world.Update(1 / rate)
+			if frame%(int(rate)/samplesPerSecond) != 0 {
+				continue
+			}
+			positions := make([]rl.Vector2, len(scene.bodies))
+			for j, body := range scene.bodies {
+				positions[j] = body.Position
+			}
+			samples[i] = append(samples[i], positions)
+		}
+	}
+
+	// Rounding in the accumulator may leave one step for the next frame,
+	// which a body running off a ledge covers at terminal fall speed
+	tolerance := float32(math.Hypot(240, 900))*PhysicsStep + 0.01
+	for i := 1; i < len(testFrameRates); i++ {
+		for sample := range samples[0] {
+			for j := range samples[0][sample] {
+				got, want := samples[i][sample][j], samples[0][sample][j]
+				if rl.Vector2Distance(got, want) > tolerance {
+					t.Errorf("%v Hz at %.2fs: body %d at %v, at %v Hz it is at %v",
+						testFrameRates[i], float32(sample+1)/samplesPerSecond, j, got, testFrameRates[0], want)
+				}
+			}
+		}
+	}
+}
+
+func TestCollisionReach(t *testing.T) {
+	tests := []struct {
+		name           string
+		velocity       rl.Vector2
+		groundVelocity rl.Vector2
+		want           float32
+	}{
+		{"at rest", rl.Vector2{}, rl.Vector2{}, broadphaseReach},
+		{"terminal fall", rl.Vector2{Y: 900}, rl.Vector2{}, broadphaseReach},
+		{"dash", rl.Vector2{X: -160 / 0.15}, rl.Vector2{}, broadphaseReach},
+		{"faster than the reach", rl.Vector2{X: 3000, Y: 6000}, rl.Vector2{}, 300},
+		{"riding a fast platform", rl.Vector2{X: 900}, rl.Vector2{X: 3000}, 130},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transform := &components.TransformComponent{Velocity: tt.velocity}
+			physics := &components.PhysicsComponent{GroundVelocity: tt.groundVelocity}
+			if got := collisionReach(transform, physics, MaxPhysicsStep); math.Abs(float64(got-tt.want)) > 0.01 {
+				t.Errorf("collisionReach = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
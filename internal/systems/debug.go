@@ -0,0 +1,184 @@
+package systems
+
+import (
+	"fmt"
+	"strings"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Debug overlay sizes, in world units
+const (
+	debugVelocityScale = 0.15 // Seconds of travel a velocity arrow shows
+	debugNormalLength  = 16
+	debugGroundMark    = 6 // Radius of the on-ground marker
+	debugWaypointSize  = 4
+)
+
+// Debug overlay colours, shared by the systems that queue shapes
+var (
+	debugCellColor     = rl.Color{R: 255, G: 255, B: 255, A: 40}
+	debugCheckedColor  = rl.Color{R: 0, G: 200, B: 255, A: 140}
+	debugContactColor  = rl.Color{R: 255, G: 60, B: 200, A: 255}
+	debugVelocityColor = rl.Color{R: 255, G: 230, B: 0, A: 255}
+	debugGroundColor   = rl.Color{R: 60, G: 220, B: 60, A: 255}
+	debugAirColor      = rl.Color{R: 220, G: 60, B: 60, A: 255}
+	debugTriggerColor  = rl.Color{R: 170, G: 90, B: 255, A: 200}
+	debugPatrolColor   = rl.Color{R: 255, G: 150, B: 40, A: 220}
+	debugSightColor    = rl.Color{R: 255, G: 80, B: 80, A: 150}
+	debugCameraColor   = rl.Color{R: 80, G: 160, B: 255, A: 220}
+	debugLabelColor    = rl.Color{R: 255, G: 255, B: 255, A: 230}
+)
+
+// debugTogglePressed reports whether the overlay was switched this frame.
+var debugTogglePressed = func() bool { return rl.IsKeyPressed(rl.KeyF3) }
+
+// FindDebugDraw returns the debug overlay's shape queue while the overlay is
+// on, or nil. Systems queue shapes into it during their update; queueing
+// into nil does nothing, so the result can be used without a check.
+func FindDebugDraw(world *ecs.World) *components.DebugDrawComponent {
+	debugStore, ok := ecs.GetStore[*components.DebugDrawComponent](world.Components)
+	if !ok {
+		return nil
+	}
+	for _, id := range debugStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+		if debug, _ := debugStore.Get(id); debug.Enabled {
+			return debug
+		}
+		return nil
+	}
+	return nil
+}
+
+// DebugSystem switches the debug overlay with F3 and queues the state of
+// the entities themselves: velocity arrows, on-ground markers, trigger
+// volumes, AI patrol paths and sight ranges, and entity IDs and tags.
+// Other systems queue what only they know, such as the collision system's
+// contacts and broadphase cells or the camera's deadzone. It runs after the
+// simulation, so the overlay shows where everything ended up this frame.
+type DebugSystem struct {
+	Enabled *bool // Setting the overlay follows; toggled by F3
+}
+
+// NewDebugSystem creates a new DebugSystem switching the given setting.
+func NewDebugSystem(enabled *bool) *DebugSystem {
+	return &DebugSystem{Enabled: enabled}
+}
+
+// Update applies the toggle and queues the entity overlay.
+func (s *DebugSystem) Update(world *ecs.World, dt float32) {
+	debugStore, ok := ecs.GetStore[*components.DebugDrawComponent](world.Components)
+	if !ok || s.Enabled == nil {
+		return
+	}
+
+	if debugTogglePressed() {
+		*s.Enabled = !*s.Enabled
+	}
+	for _, id := range debugStore.All() {
+		debug, _ := debugStore.Get(id)
+		debug.Enabled = *s.Enabled
+		if !debug.Enabled {
+			debug.Shapes = debug.Shapes[:0]
+		}
+	}
+
+	debug := FindDebugDraw(world)
+	if debug == nil {
+		return
+	}
+
+	transformStore, ok := ecs.GetStore[*components.TransformComponent](world.Components)
+	if !ok {
+		return
+	}
+	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
+	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
+	aiStore, _ := ecs.GetStore[*components.AIComponent](world.Components)
+	tileStore, _ := ecs.GetStore[*components.TileComponent](world.Components)
+
+	for _, id := range transformStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+		transform, _ := transformStore.Get(id)
+
+		// Tiles only show when they are trigger volumes; there are too
+		// many of them to label
+		var collider *components.ColliderComponent
+		if colliderStore != nil {
+			collider, _ = colliderStore.Get(id)
+		}
+		isTile := tileStore != nil && tileStore.Has(id)
+		if collider != nil && collider.IsTrigger {
+			debug.Rect(collider.GetWorldBounds(transform.Position), debugTriggerColor)
+		}
+		if isTile || collider == nil {
+			continue
+		}
+
+		bounds := collider.GetWorldBounds(transform.Position)
+		centre := rectCentre(bounds)
+
+		if transform.Velocity.X != 0 || transform.Velocity.Y != 0 {
+			tip := rl.Vector2{
+				X: centre.X + transform.Velocity.X*debugVelocityScale,
+				Y: centre.Y + transform.Velocity.Y*debugVelocityScale,
+			}
+			debug.Arrow(centre, tip, debugVelocityColor)
+		}
+
+		if physicsStore != nil {
+			if physics, ok := physicsStore.Get(id); ok {
+				feet := rl.Vector2{X: centre.X, Y: bounds.Y + bounds.Height}
+				color := debugAirColor
+				if physics.IsOnGround {
+					color = debugGroundColor
+				}
+				debug.Circle(feet, debugGroundMark, color)
+			}
+		}
+
+		if aiStore != nil {
+			if ai, ok := aiStore.Get(id); ok {
+				s.drawAI(debug, ai, centre)
+			}
+		}
+
+		label := fmt.Sprintf("#%d", id)
+		if len(entity.Tags) > 0 {
+			label += " " + strings.Join(entity.Tags, ",")
+		}
+		debug.Text(rl.Vector2{X: bounds.X, Y: bounds.Y - 12}, label, debugLabelColor)
+	}
+}
+
+// drawAI queues an AI's patrol path, with the waypoint it is heading for
+// marked, and its sight range around centre.
+func (s *DebugSystem) drawAI(debug *components.DebugDrawComponent, ai *components.AIComponent, centre rl.Vector2) {
+	path := ai.PatrolPath
+	for i, point := range path {
+		if i > 0 {
+			debug.Line(path[i-1], point, debugPatrolColor)
+		}
+		size := float32(debugWaypointSize)
+		if i == ai.PathIndex {
+			size *= 2
+		}
+		debug.Circle(point, size, debugPatrolColor)
+	}
+	if len(path) > 2 {
+		debug.Line(path[len(path)-1], path[0], debugPatrolColor)
+	}
+	if ai.SightRange > 0 {
+		debug.Circle(centre, ai.SightRange, debugSightColor)
+	}
+}
@@ -0,0 +1,156 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// pressDebugToggle makes the next DebugSystem updates see F3 pressed or
+// not, until the test ends.
+func pressDebugToggle(t *testing.T, pressed bool) {
+	t.Helper()
+	previous := debugTogglePressed
+	debugTogglePressed = func() bool { return pressed }
+	t.Cleanup(func() { debugTogglePressed = previous })
+}
+
+// newDebugTestWorld creates a world with the overlay's shape queue, a
+// moving player on the ground, a patrolling mob, a trigger tile and a solid
+// tile.
+func newDebugTestWorld() (*ecs.World, *components.DebugDrawComponent) {
+	world := ecs.NewWorld()
+	debug := &components.DebugDrawComponent{}
+	ecs.RegisterStore[*components.DebugDrawComponent](world.Components).Add(world.CreateEntity("debug").ID, debug)
+
+	player := addTestBody(world, 0, 0, "player")
+	transform, _ := ecs.RegisterStore[*components.TransformComponent](world.Components).Get(player.ID)
+	transform.Velocity = rl.Vector2{X: 100, Y: -200}
+	ecs.RegisterStore[*components.PhysicsComponent](world.Components).Add(player.ID, &components.PhysicsComponent{IsOnGround: true})
+
+	mob := addTestBody(world, 300, 0, "enemy", "mob")
+	ecs.RegisterStore[*components.AIComponent](world.Components).Add(mob.ID, &components.AIComponent{
+		PatrolPath: []rl.Vector2{{X: 200, Y: 40}, {X: 400, Y: 40}, {X: 300, Y: -60}},
+		PathIndex:  1,
+		SightRange: 150,
+	})
+
+	trigger := addTestTile(world, 600, 0, 1)
+	collider, _ := ecs.RegisterStore[*components.ColliderComponent](world.Components).Get(trigger.ID)
+	collider.IsTrigger = true
+	addTestTile(world, 700, 0, 0)
+	return world, debug
+}
+
+// roundVector rounds a vector to a thousandth of a world unit.
+func roundVector(v rl.Vector2) rl.Vector2 {
+	return rl.Vector2{
+		X: float32(math.Round(float64(v.X)*1000) / 1000),
+		Y: float32(math.Round(float64(v.Y)*1000) / 1000),
+	}
+}
+
+// countShapes counts the queued shapes of a kind and colour.
+func countShapes(debug *components.DebugDrawComponent, kind components.DebugShapeKind, color rl.Color) int {
+	count := 0
+	for _, shape := range debug.Shapes {
+		if shape.Kind == kind && shape.Color == color {
+			count++
+		}
+	}
+	return count
+}
+
+func TestDebugSystemToggle(t *testing.T) {
+	world, debug := newDebugTestWorld()
+	enabled := false
+	system := NewDebugSystem(&enabled)
+
+	pressDebugToggle(t, false)
+	system.Update(world, 1.0/60)
+	if enabled || debug.Enabled || len(debug.Shapes) != 0 || FindDebugDraw(world) != nil {
+		t.Fatalf("overlay on %v (queue %v, %d shapes) without F3", enabled, debug.Enabled, len(debug.Shapes))
+	}
+
+	pressDebugToggle(t, true)
+	system.Update(world, 1.0/60)
+	if !enabled || !debug.Enabled || len(debug.Shapes) == 0 || FindDebugDraw(world) != debug {
+		t.Fatalf("overlay on %v (queue %v, %d shapes) after F3", enabled, debug.Enabled, len(debug.Shapes))
+	}
+
+	// Switching off drops what was queued
+	system.Update(world, 1.0/60)
+	if enabled || debug.Enabled || len(debug.Shapes) != 0 {
+		t.Errorf("overlay on %v (queue %v, %d shapes) after a second F3", enabled, debug.Enabled, len(debug.Shapes))
+	}
+}
+
+func TestDebugSystemShapes(t *testing.T) {
+	world, debug := newDebugTestWorld()
+	enabled := true
+	pressDebugToggle(t, false)
+	NewDebugSystem(&enabled).Update(world, 1.0/60)
+
+	// The player's 20x40 collider is centred on (10, 20)
+	velocity := components.DebugShape{Kind: components.DebugArrow, From: rl.Vector2{X: 10, Y: 20}, To: rl.Vector2{X: 25, Y: -10}, Color: debugVelocityColor}
+	ground := components.DebugShape{Kind: components.DebugCircle, From: rl.Vector2{X: 10, Y: 40}, Radius: debugGroundMark, Color: debugGroundColor}
+	trigger := components.DebugShape{Kind: components.DebugRect, Rect: rl.Rectangle{X: 600, Width: 32, Height: 32}, Color: debugTriggerColor}
+	sight := components.DebugShape{Kind: components.DebugCircle, From: rl.Vector2{X: 310, Y: 20}, Radius: 150, Color: debugSightColor}
+	for name, want := range map[string]components.DebugShape{"velocity": velocity, "ground": ground, "trigger": trigger, "sight": sight} {
+		found := false
+		for _, shape := range debug.Shapes {
+			// Arrow tips are scaled by debugVelocityScale, so allow rounding
+			shape.From, shape.To = roundVector(shape.From), roundVector(shape.To)
+			found = found || shape == want
+		}
+		if !found {
+			t.Errorf("no %s shape %+v in %+v", name, want, debug.Shapes)
+		}
+	}
+
+	// A closed three-point patrol path with the next waypoint marked larger
+	if lines := countShapes(debug, components.DebugLine, debugPatrolColor); lines != 3 {
+		t.Errorf("%d patrol lines, want 3", lines)
+	}
+	var waypoints []float32
+	for _, shape := range debug.Shapes {
+		if shape.Kind == components.DebugCircle && shape.Color == debugPatrolColor {
+			waypoints = append(waypoints, shape.Radius)
+		}
+	}
+	if len(waypoints) != 3 || waypoints[0] != debugWaypointSize || waypoints[1] != 2*debugWaypointSize || waypoints[2] != debugWaypointSize {
+		t.Errorf("waypoint sizes %v, want the second marked", waypoints)
+	}
+
+	// Both bodies are labelled, only the moving one gets an arrow, and the
+	// solid tile shows nothing
+	if labels := countShapes(debug, components.DebugText, debugLabelColor); labels != 2 {
+		t.Errorf("%d labels, want 2", labels)
+	}
+	if arrows := countShapes(debug, components.DebugArrow, debugVelocityColor); arrows != 1 {
+		t.Errorf("%d velocity arrows, want only the moving player's", arrows)
+	}
+	if rects := countShapes(debug, components.DebugRect, debugTriggerColor); rects != 1 {
+		t.Errorf("%d trigger volumes, want 1", rects)
+	}
+}
+
+func TestRenderSystemClearsDebugQueue(t *testing.T) {
+	world, debug := newDebugTestWorld()
+	enabled := true
+	pressDebugToggle(t, false)
+	NewDebugSystem(&enabled).Update(world, 1.0/60)
+	if len(debug.Shapes) == 0 {
+		t.Fatal("nothing queued")
+	}
+
+	NewRenderSystem(RenderConfig{}).Update(world, 1.0/60)
+
+	if len(debug.Shapes) != 0 {
+		t.Errorf("%d shapes left after the frame was rendered", len(debug.Shapes))
+	}
+}
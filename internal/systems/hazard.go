@@ -0,0 +1,137 @@
+package systems
+
+import (
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// hazardReach is how far (in world units) around an entity's collider a
+// damaging tile is still touching it, so solid tiles it rests against count.
+const hazardReach = 1.0
+
+// HazardSystem hurts entities touching damaging tiles. Each entity is hurt
+// at most once per its HealthComponent.HazardCooldown.
+type HazardSystem struct{}
+
+// NewHazardSystem creates a new HazardSystem.
+func NewHazardSystem() *HazardSystem {
+	return &HazardSystem{}
+}
+
+// Update applies tile damage to entities with health.
+func (s *HazardSystem) Update(world *ecs.World, dt float32) {
+	healthStore, ok1 := ecs.GetStore[*components.HealthComponent](world.Components)
+	transformStore, ok2 := ecs.GetStore[*components.TransformComponent](world.Components)
+	colliderStore, ok3 := ecs.GetStore[*components.ColliderComponent](world.Components)
+	tileStore, ok4 := ecs.GetStore[*components.TileComponent](world.Components)
+	animatorStore, _ := ecs.GetStore[*components.AnimatorComponent](world.Components)
+	abilitiesStore, _ := ecs.GetStore[*components.AbilitiesComponent](world.Components)
+
+	// Hazards are frozen during a hit-stop
+	if !ok1 || !ok2 || !ok3 || !ok4 || dt <= 0 {
+		return
+	}
+	if dt > MaxPhysicsStep {
+		dt = MaxPhysicsStep
+	}
+
+	// Only damaging tiles matter
+	hazards := make([]rl.Rectangle, 0)
+	damages := make([]int, 0)
+	for _, id := range tileStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+		tile, _ := tileStore.Get(id)
+		if tile.Damage <= 0 {
+			continue
+		}
+		transform, ok := transformStore.Get(id)
+		if !ok {
+			continue
+		}
+		collider, ok := colliderStore.Get(id)
+		if !ok {
+			continue
+		}
+		hazards = append(hazards, collider.GetWorldBounds(transform.Position))
+		damages = append(damages, tile.Damage)
+	}
+
+	for _, id := range healthStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+
+		health, _ := healthStore.Get(id)
+		if health.HazardTimer > 0 {
+			// The frame the cooldown runs out may hurt again
+			health.HazardTimer -= dt
+			if health.HazardTimer > 0 {
+				continue
+			}
+		}
+		if health.Invulnerable || isRolling(abilitiesStore, id) || health.IsDead() || len(hazards) == 0 {
+			continue
+		}
+
+		transform, ok := transformStore.Get(id)
+		if !ok {
+			continue
+		}
+		collider, ok := colliderStore.Get(id)
+		if !ok {
+			continue
+		}
+		bounds := collider.GetWorldBounds(transform.Position)
+		reach := rl.Rectangle{
+			X:      bounds.X - hazardReach,
+			Y:      bounds.Y - hazardReach,
+			Width:  bounds.Width + 2*hazardReach,
+			Height: bounds.Height + 2*hazardReach,
+		}
+
+		// The worst hazard touched this frame hurts
+		damage := 0
+		for i, hazard := range hazards {
+			if damages[i] > damage && rl.CheckCollisionRecs(reach, hazard) {
+				damage = damages[i]
+			}
+		}
+		if damage == 0 {
+			continue
+		}
+
+		health.TakeDamage(damage)
+		health.HazardTimer = health.HazardCooldown
+
+		var animator *components.AnimatorComponent
+		if animatorStore != nil {
+			animator, _ = animatorStore.Get(id)
+		}
+		if animator != nil {
+			animator.SetTrigger("hit")
+		}
+
+		world.Events.Publish(ecs.Event{
+			Type:   ecs.EventDamage,
+			Target: id,
+			Data:   DamageEvent{Amount: damage},
+		})
+
+		if health.IsDead() {
+			// Animated entities stay to play their death state
+			if animator == nil {
+				entity.Active = false
+			}
+			world.Events.Publish(ecs.Event{
+				Type:   ecs.EventDeath,
+				Target: id,
+			})
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package systems
+
+import (
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/core"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// addTestTile adds a 32x32 tile dealing damage at a position.
+func addTestTile(world *ecs.World, x, y float32, damage int) *ecs.Entity {
+	tile := world.CreateEntity("tile")
+	ecs.RegisterStore[*components.TransformComponent](world.Components).Add(tile.ID, &components.TransformComponent{Position: rl.Vector2{X: x, Y: y}})
+	ecs.RegisterStore[*components.ColliderComponent](world.Components).Add(tile.ID, &components.ColliderComponent{Bounds: rl.Rectangle{Width: 32, Height: 32}})
+	ecs.RegisterStore[*components.TileComponent](world.Components).Add(tile.ID, &components.TileComponent{Damage: damage})
+	return tile
+}
+
+// addTestVictim adds a 20x40 body with health standing on y = 0.
+func addTestVictim(world *ecs.World, x float32, cooldown float32, tags ...string) (*ecs.Entity, *components.HealthComponent) {
+	body := addTestBody(world, x, -40, tags...)
+	health := &components.HealthComponent{Current: 5, Max: 5, HazardCooldown: cooldown}
+	ecs.RegisterStore[*components.HealthComponent](world.Components).Add(body.ID, health)
+	return body, health
+}
+
+func TestHazardDamagesOnTouch(t *testing.T) {
+	world := ecs.NewWorld()
+	addTestTile(world, 0, 0, 2)
+	addTestTile(world, 200, 0, 0)
+	hurt, hurtHealth := addTestVictim(world, 6, 1, "player")
+	_, safeHealth := addTestVictim(world, 206, 1, "enemy")
+	_, farHealth := addTestVictim(world, 400, 1, "enemy")
+
+	var damaged []ecs.Event
+	world.Events.Subscribe(ecs.EventDamage, func(event ecs.Event) {
+		damaged = append(damaged, event)
+	})
+	NewHazardSystem().Update(world, 1.0/60)
+	world.Events.Process()
+
+	if hurtHealth.Current != 3 || hurtHealth.HazardTimer != 1 {
+		t.Errorf("health %d with timer %v on a spike, want 3 and the cooldown", hurtHealth.Current, hurtHealth.HazardTimer)
+	}
+	if safeHealth.Current != 5 {
+		t.Errorf("health %d on a tile without damage, want 5", safeHealth.Current)
+	}
+	if farHealth.Current != 5 {
+		t.Errorf("health %d away from every tile, want 5", farHealth.Current)
+	}
+	if len(damaged) != 1 || damaged[0].Target != hurt.ID || damaged[0].Data != (DamageEvent{Amount: 2}) {
+		t.Errorf("damage events %+v, want one of 2 for entity %d", damaged, hurt.ID)
+	}
+}
+
+func TestHazardCooldown(t *testing.T) {
+	tests := []struct {
+		name     string
+		cooldown float32
+	}{
+		{"player", core.PlayerHazardCooldown},
+		{"mob", core.MobHazardCooldown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world := ecs.NewWorld()
+			addTestTile(world, 0, 0, 1)
+			_, health := addTestVictim(world, 6, tt.cooldown, tt.name)
+			health.Current, health.Max = 100, 100
+			system := NewHazardSystem()
+			const dt = 1.0 / 60
+
+			// Standing on the tile hurts once, then once per cooldown
+			var hits []float32
+			for frame := 0; frame < int(2.5/dt); frame++ {
+				before := health.Current
+				system.Update(world, dt)
+				if health.Current < before {
+					hits = append(hits, float32(frame)*dt)
+				}
+			}
+
+			if len(hits) != 3 {
+				t.Fatalf("hurt at %v in 2.5s, want three times", hits)
+			}
+			for i := 1; i < len(hits); i++ {
+				// Frames end on either side of the cooldown
+				if gap := hits[i] - hits[i-1]; gap < tt.cooldown-dt/2 || gap > tt.cooldown+dt*3/2 {
+					t.Errorf("hurt %.3fs after the last hurt, want the %vs cooldown", gap, tt.cooldown)
+				}
+			}
+		})
+	}
+}
+
+func TestHazardSkipsInvulnerableAndHitStop(t *testing.T) {
+	world := ecs.NewWorld()
+	addTestTile(world, 0, 0, 1)
+	_, health := addTestVictim(world, 6, 1, "player")
+	system := NewHazardSystem()
+
+	system.Update(world, 0)
+	health.Invulnerable = true
+	system.Update(world, 1.0/60)
+
+	if health.Current != 5 || health.HazardTimer != 0 {
+		t.Errorf("health %d with timer %v, want no hurt during a hit-stop or while invulnerable", health.Current, health.HazardTimer)
+	}
+}
+
+func TestHazardSkipsRolling(t *testing.T) {
+	world := ecs.NewWorld()
+	addTestTile(world, 0, 0, 1)
+	body, health := addTestVictim(world, 6, 1, "player")
+	abilities := &components.AbilitiesComponent{RollInvulnerable: true}
+	ecs.RegisterStore[*components.AbilitiesComponent](world.Components).Add(body.ID, abilities)
+	system := NewHazardSystem()
+
+	system.Update(world, 1.0/60)
+	if health.Current != 5 || health.Invulnerable {
+		t.Fatalf("health %d (invulnerable %v) while rolling, want 5 without the shared flag", health.Current, health.Invulnerable)
+	}
+
+	abilities.RollInvulnerable = false
+	system.Update(world, 1.0/60)
+	if health.Current != 4 {
+		t.Errorf("health %d after the roll, want 4", health.Current)
+	}
+}
+
+func TestHazardKilledPlayerCannotAct(t *testing.T) {
+	world, transform, abilities, input, _, health := newAbilityTestWorld()
+	id := world.GetEntitiesWithTag("player")[0].ID
+	health.HazardCooldown = 0.1
+	melee := newTestMelee()
+	ecs.RegisterStore[*components.MeleeComponent](world.Components).Add(id, melee)
+	ecs.RegisterStore[*components.SpriteComponent](world.Components).Add(id, &components.SpriteComponent{})
+	// Animated entities stay active when they die, like the shipped player
+	ecs.RegisterStore[*components.AnimatorComponent](world.Components).Add(id, &components.AnimatorComponent{})
+
+	// A floor with a pool of water that hurts on it
+	for x := float32(-64); x < 1600; x += 32 {
+		addTestTile(world, x, 40, 0)
+	}
+	for x := float32(96); x < 640; x += 32 {
+		water := addTestTile(world, x, 8, 1)
+		collider, _ := ecs.RegisterStore[*components.ColliderComponent](world.Components).Get(water.ID)
+		collider.IsTrigger = true
+	}
+	collision := NewCollisionSystem()
+	world.AddSystem(NewAbilitySystem(collision))
+	world.AddSystem(NewMeleeSystem())
+	world.AddSystem(NewPhysicsSystem(nil, collision))
+	world.AddSystem(NewHazardSystem())
+
+	input.MoveX = 1
+	for frame := 0; frame < 600 && !health.IsDead(); frame++ {
+		world.Update(1.0 / 60)
+	}
+	if !health.IsDead() {
+		t.Fatalf("health %d after walking through the water, want dead", health.Current)
+	}
+
+	// Held and pressed input does nothing once dead; the body only slows
+	// to a stop on the floor
+	for frame := 0; frame < 60; frame++ {
+		input.MoveX = -1
+		input.JumpPressed, input.JumpHeld = true, true
+		input.DashPressed, input.RollPressed, input.AttackPressed = true, true, true
+		world.Update(1.0 / 60)
+		if abilities.Action != components.ActionNone || melee.IsAttacking() {
+			t.Fatalf("frame %d: dead player acting (ability %v, attacking %v)", frame, abilities.Action, melee.IsAttacking())
+		}
+		if transform.Velocity.Y < 0 || transform.Velocity.X < 0 {
+			t.Fatalf("frame %d: dead player moving %v under input", frame, transform.Velocity)
+		}
+	}
+	stopped := transform.Position
+	world.Update(1.0 / 60)
+	if transform.Position != stopped || transform.Velocity != (rl.Vector2{}) {
+		t.Errorf("dead player still moving %v at %v", transform.Velocity, transform.Position)
+	}
+}
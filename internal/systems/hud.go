@@ -0,0 +1,309 @@
+package systems
+
+import (
+	"fmt"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// HUDAnchor is the screen corner or edge a HUD element is placed from.
+type HUDAnchor int
+
+const (
+	HUDTopLeft HUDAnchor = iota
+	HUDTopCenter
+	HUDTopRight
+	HUDBottomLeft
+	HUDBottomRight
+)
+
+// hudElement places a HUD element: its anchor and its offset from it,
+// towards the screen centre, in layout units.
+type hudElement struct {
+	anchor HUDAnchor
+	offset rl.Vector2
+}
+
+// HUD layout (in layout units)
+var (
+	hudHearts    = hudElement{anchor: HUDTopLeft, offset: rl.Vector2{X: 16, Y: 16}}
+	hudTimer     = hudElement{anchor: HUDTopCenter, offset: rl.Vector2{X: 0, Y: 16}}
+	hudScore     = hudElement{anchor: HUDTopRight, offset: rl.Vector2{X: 16, Y: 16}}
+	hudAbilities = hudElement{anchor: HUDBottomLeft, offset: rl.Vector2{X: 16, Y: 16}}
+)
+
+const (
+	hudHeartSize    = 32
+	hudHeartGap     = 6
+	hudTextSize     = 24
+	hudLabelSize    = 14
+	hudIconSize     = 48
+	hudIconGap      = 8
+	hudFlashBorder  = 12 // Width of the red screen edge while flashing
+	hudFlashBlinks  = 12 // Heart colour changes per second while flashing
+	hudShadowOffset = 2
+)
+
+var (
+	hudEmptyHeart = rl.Color{R: 0, G: 0, B: 0, A: 110}
+	hudIconFill   = rl.Color{R: 20, G: 20, B: 30, A: 170}
+	hudIconReady  = rl.Color{R: 255, G: 240, B: 180, A: 255}
+	hudIconWait   = rl.Color{R: 150, G: 150, B: 150, A: 255}
+)
+
+// HUDConfig holds configuration for the HUD.
+type HUDConfig struct {
+	Font            rl.Font // Raylib's default font when not loaded
+	Heart           rl.Texture2D
+	ReferenceHeight float32 // Screen height the layout is measured in
+	HealthPerHeart  int     // Health shown by one full heart
+	DamageFlashTime float32 // Seconds the HUD flashes after the player is hurt
+	ShowTimer       bool
+}
+
+// HUD draws the player's state over the game: hearts from its
+// HealthComponent, score and coins from its ScoreComponent, ability
+// cooldowns from its AbilitiesComponent and an optional level timer. It
+// flashes when the player takes damage.
+//
+// Elements are anchored to screen corners and edges and scaled with the
+// screen height, so the layout holds at any resolution. The RenderSystem
+// draws the HUD at the start of the UI layer.
+type HUD struct {
+	Config HUDConfig
+
+	elapsed float32        // Level time shown by the timer
+	flash   float32        // Damage flash time left
+	damaged []ecs.EntityID // Damage targets since the last draw
+
+	// Screen size and layout scale this frame
+	screen rl.Vector2
+	scale  float32
+}
+
+// NewHUD creates a HUD that flashes on the damage events of the given bus.
+func NewHUD(events *ecs.EventBus, config HUDConfig) *HUD {
+	h := &HUD{Config: config}
+	events.Subscribe(ecs.EventDamage, func(event ecs.Event) {
+		h.damaged = append(h.damaged, event.Target)
+	})
+	return h
+}
+
+// Draw advances the HUD's timers by dt and draws it in screen space.
+func (h *HUD) Draw(world *ecs.World, dt float32) {
+	h.screen = rl.Vector2{X: float32(rl.GetScreenWidth()), Y: float32(rl.GetScreenHeight())}
+	h.scale = 1
+	if h.Config.ReferenceHeight > 0 && h.screen.Y > 0 {
+		h.scale = h.screen.Y / h.Config.ReferenceHeight
+	}
+	h.elapsed += dt
+	h.flash = max(h.flash-dt, 0)
+
+	var player *ecs.Entity
+	if players := world.GetEntitiesWithTag("player"); len(players) > 0 {
+		player = players[0]
+	}
+	if player != nil {
+		for _, id := range h.damaged {
+			if id == player.ID {
+				h.flash = h.Config.DamageFlashTime
+			}
+		}
+	}
+	h.damaged = h.damaged[:0]
+
+	if h.flash > 0 && h.Config.DamageFlashTime > 0 {
+		alpha := h.flash / h.Config.DamageFlashTime * 0.6
+		screen := rl.Rectangle{Width: h.screen.X, Height: h.screen.Y}
+		rl.DrawRectangleLinesEx(screen, hudFlashBorder*h.scale, rl.Fade(rl.Red, alpha))
+	}
+
+	if player != nil {
+		if healthStore, ok := ecs.GetStore[*components.HealthComponent](world.Components); ok {
+			if health, ok := healthStore.Get(player.ID); ok {
+				h.drawHearts(health)
+			}
+		}
+		if scoreStore, ok := ecs.GetStore[*components.ScoreComponent](world.Components); ok {
+			if score, ok := scoreStore.Get(player.ID); ok {
+				h.drawScore(score)
+			}
+		}
+		if abilitiesStore, ok := ecs.GetStore[*components.AbilitiesComponent](world.Components); ok {
+			if abilities, ok := abilitiesStore.Get(player.ID); ok {
+				h.drawAbilities(abilities)
+			}
+		}
+	}
+	if h.Config.ShowTimer {
+		h.drawTimer()
+	}
+}
+
+// place returns the screen position of the top-left corner of an element of
+// the given layout size, offset from its anchor.
+func (h *HUD) place(element hudElement, offset, size rl.Vector2) rl.Vector2 {
+	x := (element.offset.X + offset.X) * h.scale
+	y := (element.offset.Y + offset.Y) * h.scale
+	width, height := size.X*h.scale, size.Y*h.scale
+
+	switch element.anchor {
+	case HUDTopCenter:
+		x += (h.screen.X - width) / 2
+	case HUDTopRight:
+		x = h.screen.X - width - x
+	case HUDBottomLeft:
+		y = h.screen.Y - height - y
+	case HUDBottomRight:
+		x = h.screen.X - width - x
+		y = h.screen.Y - height - y
+	}
+	return rl.Vector2{X: x, Y: y}
+}
+
+// measure returns the layout size of a text.
+func (h *HUD) measure(text string, size float32) rl.Vector2 {
+	measured := rl.MeasureTextEx(h.Config.Font, text, size*h.scale, h.scale)
+	return rl.Vector2{X: measured.X / h.scale, Y: measured.Y / h.scale}
+}
+
+// text draws a text with a drop shadow at a screen position.
+func (h *HUD) text(text string, position rl.Vector2, size float32, color rl.Color) {
+	shadow := rl.Vector2{X: position.X + hudShadowOffset*h.scale, Y: position.Y + hudShadowOffset*h.scale}
+	rl.DrawTextEx(h.Config.Font, text, shadow, size*h.scale, h.scale, rl.Fade(rl.Black, 0.6))
+	rl.DrawTextEx(h.Config.Font, text, position, size*h.scale, h.scale, color)
+}
+
+// heartFill is how much of one HUD heart is drawn, as fractions of its
+// width from the left: the container maximum health covers, and the part of
+// it current health fills.
+type heartFill struct {
+	container float32
+	filled    float32
+}
+
+// heartFills splits health into hearts of perHeart health each (at least
+// one). The last heart's container is partial when maximum health is not a
+// multiple of perHeart; current health is clamped to the containers.
+func heartFills(current, maxHealth, perHeart int) []heartFill {
+	perHeart = max(perHeart, 1)
+	maxHealth = max(maxHealth, 0)
+	current = min(max(current, 0), maxHealth)
+
+	fills := make([]heartFill, (maxHealth+perHeart-1)/perHeart)
+	for i := range fills {
+		base := i * perHeart
+		fills[i] = heartFill{
+			container: float32(min(maxHealth-base, perHeart)) / float32(perHeart),
+			filled:    float32(min(max(current-base, 0), perHeart)) / float32(perHeart),
+		}
+	}
+	return fills
+}
+
+// drawHearts draws a heart per HealthPerHeart of maximum health, an empty
+// silhouette filled from the left by current health (see heartFills).
+// Hearts blink red during the damage flash.
+func (h *HUD) drawHearts(health *components.HealthComponent) {
+	source := rl.Rectangle{Width: float32(h.Config.Heart.Width), Height: float32(h.Config.Heart.Height)}
+
+	tint := rl.White
+	if h.flash > 0 && int(h.flash*hudFlashBlinks)%2 == 0 {
+		tint = rl.Red
+	}
+
+	for i, fill := range heartFills(health.Current, health.Max, h.Config.HealthPerHeart) {
+		offset := rl.Vector2{X: float32(i) * (hudHeartSize + hudHeartGap)}
+		position := h.place(hudHearts, offset, rl.Vector2{X: hudHeartSize, Y: hudHeartSize})
+		h.drawHeartPart(source, position, fill.container, hudEmptyHeart)
+		if fill.filled > 0 {
+			h.drawHeartPart(source, position, fill.filled, tint)
+		}
+	}
+}
+
+// drawHeartPart draws the left fraction of the heart texture at a screen
+// position.
+func (h *HUD) drawHeartPart(source rl.Rectangle, position rl.Vector2, fraction float32, tint rl.Color) {
+	source.Width *= fraction
+	dest := rl.Rectangle{X: position.X, Y: position.Y, Width: hudHeartSize * h.scale * fraction, Height: hudHeartSize * h.scale}
+	rl.DrawTexturePro(h.Config.Heart, source, dest, rl.Vector2{X: 0, Y: 0}, 0, tint)
+}
+
+// drawScore draws the score and coin count, right-aligned.
+func (h *HUD) drawScore(score *components.ScoreComponent) {
+	lines := []string{
+		fmt.Sprintf("Score %d", score.Score),
+		fmt.Sprintf("Coins %d", score.Coins),
+	}
+	y := float32(0)
+	for _, line := range lines {
+		size := h.measure(line, hudTextSize)
+		h.text(line, h.place(hudScore, rl.Vector2{Y: y}, size), hudTextSize, rl.White)
+		y += size.Y
+	}
+}
+
+// drawTimer draws the level time as minutes and seconds, centred.
+func (h *HUD) drawTimer() {
+	seconds := int(h.elapsed)
+	text := fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+	size := h.measure(text, hudTextSize)
+	h.text(text, h.place(hudTimer, rl.Vector2{}, size), hudTextSize, rl.White)
+}
+
+// cooldownFraction returns the part of a cooldown still to run: 0 when the
+// ability is ready, 1 when it was just used.
+func cooldownFraction(timer, cooldown float32) float32 {
+	if timer <= 0 || cooldown <= 0 {
+		return 0
+	}
+	return min(timer/cooldown, 1)
+}
+
+// drawAbilities draws an icon for each unlocked cooldown ability. The part
+// of the icon still cooling down is covered from the top.
+func (h *HUD) drawAbilities(abilities *components.AbilitiesComponent) {
+	icons := []struct {
+		label    string
+		unlocked bool
+		timer    float32
+		cooldown float32
+	}{
+		{"DASH", abilities.Dash, abilities.DashCooldownTimer, abilities.DashCooldown},
+		{"ROLL", abilities.Roll, abilities.RollCooldownTimer, abilities.RollCooldown},
+		{"SLIDE", abilities.Slide, abilities.SlideCooldownTimer, abilities.SlideCooldown},
+	}
+
+	x := float32(0)
+	for _, icon := range icons {
+		if !icon.unlocked {
+			continue
+		}
+		size := rl.Vector2{X: hudIconSize, Y: hudIconSize}
+		position := h.place(hudAbilities, rl.Vector2{X: x}, size)
+		box := rl.Rectangle{X: position.X, Y: position.Y, Width: hudIconSize * h.scale, Height: hudIconSize * h.scale}
+		rl.DrawRectangleRec(box, rl.Fade(rl.Black, 0.35))
+
+		color := hudIconReady
+		if waiting := cooldownFraction(icon.timer, icon.cooldown); waiting > 0 {
+			color = hudIconWait
+			cover := box
+			cover.Height *= waiting
+			rl.DrawRectangleRec(cover, hudIconFill)
+		}
+		rl.DrawRectangleLinesEx(box, 2*h.scale, color)
+
+		labelSize := h.measure(icon.label, hudLabelSize)
+		label := rl.Vector2{
+			X: box.X + (box.Width-labelSize.X*h.scale)/2,
+			Y: box.Y + (box.Height-labelSize.Y*h.scale)/2,
+		}
+		h.text(icon.label, label, hudLabelSize, color)
+		x += hudIconSize + hudIconGap
+	}
+}
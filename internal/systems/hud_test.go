@@ -0,0 +1,57 @@
+package systems
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeartFills(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   int
+		maxHealth int
+		perHeart  int
+		want      []heartFill
+	}{
+		{"full", 4, 4, 2, []heartFill{{1, 1}, {1, 1}}},
+		{"odd current shows a half heart", 3, 4, 2, []heartFill{{1, 1}, {1, 0.5}}},
+		{"empty", 0, 4, 2, []heartFill{{1, 0}, {1, 0}}},
+		{"current above max is clamped", 9, 4, 2, []heartFill{{1, 1}, {1, 1}}},
+		{"negative current is clamped", -3, 4, 2, []heartFill{{1, 0}, {1, 0}}},
+		{"max not a multiple adds a partial heart", 5, 5, 2, []heartFill{{1, 1}, {1, 1}, {0.5, 0.5}}},
+		{"partial heart fills no further than its container", 4, 5, 2, []heartFill{{1, 1}, {1, 1}, {0.5, 0}}},
+		{"thirds", 4, 5, 3, []heartFill{{1, 1}, {2.0 / 3, 1.0 / 3}}},
+		{"unset per heart means one", 2, 3, 0, []heartFill{{1, 1}, {1, 1}, {1, 0}}},
+		{"no max no hearts", 3, 0, 2, []heartFill{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heartFills(tt.current, tt.maxHealth, tt.perHeart); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("heartFills(%d, %d, %d) = %v, want %v", tt.current, tt.maxHealth, tt.perHeart, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCooldownFraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		timer    float32
+		cooldown float32
+		want     float32
+	}{
+		{"ready", 0, 0.5, 0},
+		{"just used", 0.5, 0.5, 1},
+		{"half way", 0.25, 0.5, 0.5},
+		{"timer past the cooldown is clamped", 0.8, 0.5, 1},
+		{"overshot timer is ready", -0.01, 0.5, 0},
+		{"no cooldown", 0.3, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cooldownFraction(tt.timer, tt.cooldown); got != tt.want {
+				t.Errorf("cooldownFraction(%v, %v) = %v, want %v", tt.timer, tt.cooldown, got, tt.want)
+			}
+		})
+	}
+}
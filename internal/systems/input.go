@@ -7,7 +7,8 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-// InputSystem reads keyboard input and updates InputComponent.
+// InputSystem reads keyboard input and updates InputComponent. Dead
+// entities get no input.
 type InputSystem struct{}
 
 // NewInputSystem creates a new InputSystem.
@@ -18,6 +19,7 @@ func NewInputSystem() *InputSystem {
 // Update reads input and updates all entities with InputComponent.
 func (s *InputSystem) Update(world *ecs.World, dt float32) {
 	inputStore, ok := ecs.GetStore[*components.InputComponent](world.Components)
+	healthStore, _ := ecs.GetStore[*components.HealthComponent](world.Components)
 	if !ok {
 		return
 	}
@@ -29,6 +31,10 @@ func (s *InputSystem) Update(world *ecs.World, dt float32) {
 		}
 
 		input, _ := inputStore.Get(id)
+		if isDead(healthStore, id) {
+			*input = components.InputComponent{}
+			continue
+		}
 
 		// Reset per-frame state
 		input.JumpPressed = false
@@ -46,5 +52,27 @@ func (s *InputSystem) Update(world *ecs.World, dt float32) {
 			input.JumpPressed = true
 		}
 		input.JumpHeld = rl.IsKeyDown(rl.KeyUp) || rl.IsKeyDown(rl.KeySpace)
+
+		// Down (drop through one-way platforms)
+		input.DownHeld = rl.IsKeyDown(rl.KeyDown)
+
+		// Movement abilities
+		input.DashPressed = rl.IsKeyPressed(rl.KeyLeftShift)
+		input.RollPressed = rl.IsKeyPressed(rl.KeyC)
+		input.SlidePressed = rl.IsKeyPressed(rl.KeyV)
+
+		// Melee attack
+		input.AttackPressed = rl.IsKeyPressed(rl.KeyX)
+	}
+}
+
+// isDead reports whether an entity has health and none of it left. Systems
+// acting on input check it too, so the dead cannot move, jump, use
+// abilities or attack whatever their InputComponent holds.
+func isDead(healthStore *ecs.ComponentStore[*components.HealthComponent], id ecs.EntityID) bool {
+	if healthStore == nil {
+		return false
 	}
+	health, ok := healthStore.Get(id)
+	return ok && health.IsDead()
 }
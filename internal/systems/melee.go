@@ -0,0 +1,288 @@
+package systems
+
+import (
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DamageEvent is the Data of an ecs.EventDamage published by MeleeSystem
+// and HazardSystem.
+type DamageEvent struct {
+	Amount int
+	Attack string // Name of the attack that landed; empty for hazards
+}
+
+// MeleeSystem starts and chains melee attacks and applies their hitboxes.
+// Attack timing follows the attack's animation: a hitbox is live only while
+// the animation shows one of its frames, and the attack ends when the
+// animation has played through once.
+type MeleeSystem struct{}
+
+// NewMeleeSystem creates a new MeleeSystem.
+func NewMeleeSystem() *MeleeSystem {
+	return &MeleeSystem{}
+}
+
+// Update processes attack input and hits for all entities with MeleeComponent.
+func (s *MeleeSystem) Update(world *ecs.World, dt float32) {
+	meleeStore, ok1 := ecs.GetStore[*components.MeleeComponent](world.Components)
+	transformStore, ok2 := ecs.GetStore[*components.TransformComponent](world.Components)
+	spriteStore, ok3 := ecs.GetStore[*components.SpriteComponent](world.Components)
+	inputStore, _ := ecs.GetStore[*components.InputComponent](world.Components)
+	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
+	abilitiesStore, _ := ecs.GetStore[*components.AbilitiesComponent](world.Components)
+	healthStore, _ := ecs.GetStore[*components.HealthComponent](world.Components)
+
+	if !ok1 || !ok2 || !ok3 {
+		return
+	}
+
+	for _, id := range meleeStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+
+		transform, ok := transformStore.Get(id)
+		if !ok {
+			continue
+		}
+		sprite, ok := spriteStore.Get(id)
+		if !ok {
+			continue
+		}
+		melee, _ := meleeStore.Get(id)
+
+		pressed := false
+		if inputStore != nil {
+			if input, ok := inputStore.Get(id); ok {
+				pressed = input.AttackPressed
+			}
+		}
+		crouching := false
+		if physicsStore != nil {
+			if physics, ok := physicsStore.Get(id); ok {
+				crouching = physics.IsCrouching
+			}
+		}
+
+		if melee.ComboWindowTimer > 0 {
+			melee.ComboWindowTimer -= dt
+		}
+
+		// Dying cancels an attack and prevents new ones
+		if isDead(healthStore, id) {
+			endAttack(melee, false)
+			continue
+		}
+
+		// Dashing, rolling or sliding cancels an attack and prevents new ones
+		if abilitiesStore != nil {
+			if abilities, ok := abilitiesStore.Get(id); ok && abilities.Action != components.ActionNone {
+				endAttack(melee, false)
+				continue
+			}
+		}
+
+		if !melee.IsAttacking() {
+			if pressed {
+				startNextAttack(melee, sprite, crouching)
+			}
+			continue
+		}
+
+		if pressed && melee.ComboIndex >= 0 && melee.ComboIndex+1 < len(melee.Combo) {
+			melee.ComboQueued = true
+		}
+
+		animData := sprite.Animations[melee.Current.Animation]
+		if animData == nil {
+			endAttack(melee, false)
+			continue
+		}
+
+		// Wait for the animation system to switch to and rewind the attack
+		if sprite.CurrentAnim != melee.Current.Animation || sprite.Restart {
+			continue
+		}
+
+		if animData.Completed {
+			if melee.ComboQueued {
+				startNextAttack(melee, sprite, false)
+			} else {
+				endAttack(melee, true)
+			}
+			continue
+		}
+
+		s.applyHitboxes(world, id, melee, transform, sprite, animData)
+	}
+}
+
+// startNextAttack begins the crouch attack when crouching, otherwise the next
+// attack in the combo if the previous one was chained in time, or the first.
+func startNextAttack(melee *components.MeleeComponent, sprite *components.SpriteComponent, crouching bool) {
+	if crouching {
+		if melee.CrouchAttack == nil {
+			return
+		}
+		beginAttack(melee, sprite, melee.CrouchAttack, -1)
+		return
+	}
+
+	if len(melee.Combo) == 0 {
+		return
+	}
+
+	next := 0
+	chained := melee.ComboQueued || melee.ComboWindowTimer > 0
+	if chained && melee.ComboIndex >= 0 && melee.ComboIndex+1 < len(melee.Combo) {
+		next = melee.ComboIndex + 1
+	}
+	beginAttack(melee, sprite, &melee.Combo[next], next)
+}
+
+// beginAttack makes attack the current one and restarts its animation.
+func beginAttack(melee *components.MeleeComponent, sprite *components.SpriteComponent, attack *components.AttackDefinition, comboIndex int) {
+	melee.Current = attack
+	melee.ComboIndex = comboIndex
+	melee.ComboQueued = false
+	melee.ComboWindowTimer = 0
+	melee.ActiveHitbox = -1
+	sprite.Restart = true
+}
+
+// endAttack finishes the current attack. When it ran to completion the combo
+// window opens so a quick press continues the chain.
+func endAttack(melee *components.MeleeComponent, completed bool) {
+	if melee.Current == nil {
+		return
+	}
+	melee.ComboWindowTimer = 0
+	if completed && melee.ComboIndex >= 0 {
+		melee.ComboWindowTimer = melee.Current.ComboWindow
+	}
+	melee.Current = nil
+	melee.ComboQueued = false
+	melee.ActiveHitbox = -1
+}
+
+// applyHitboxes damages every target overlapping a hitbox that is live on the
+// animation's current frame. Each hitbox hits a given target at most once.
+func (s *MeleeSystem) applyHitboxes(world *ecs.World, attackerID ecs.EntityID, melee *components.MeleeComponent, transform *components.TransformComponent, sprite *components.SpriteComponent, animData *components.AnimationData) {
+	healthStore, ok1 := ecs.GetStore[*components.HealthComponent](world.Components)
+	colliderStore, ok2 := ecs.GetStore[*components.ColliderComponent](world.Components)
+	transformStore, _ := ecs.GetStore[*components.TransformComponent](world.Components)
+	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
+	animatorStore, _ := ecs.GetStore[*components.AnimatorComponent](world.Components)
+	abilitiesStore, _ := ecs.GetStore[*components.AbilitiesComponent](world.Components)
+
+	if !ok1 || !ok2 {
+		return
+	}
+
+	attack := melee.Current
+	frame := animData.CurrentFrame
+
+	for i, hitbox := range attack.Hitboxes {
+		if frame < hitbox.StartFrame || frame > hitbox.EndFrame {
+			continue
+		}
+
+		if melee.ActiveHitbox != i || melee.HitTargets == nil {
+			melee.ActiveHitbox = i
+			melee.HitTargets = make(map[uint32]bool)
+		}
+
+		area := hitboxWorldRect(hitbox.Rect, transform, sprite.Scale, animData.FrameWidth())
+
+		for _, targetID := range healthStore.All() {
+			if targetID == attackerID || melee.HitTargets[uint32(targetID)] {
+				continue
+			}
+
+			target := world.GetEntity(targetID)
+			if target == nil || !target.Active {
+				continue
+			}
+			if melee.TargetTag != "" && !target.HasTag(melee.TargetTag) {
+				continue
+			}
+
+			targetTransform, ok := transformStore.Get(targetID)
+			if !ok {
+				continue
+			}
+			collider, ok := colliderStore.Get(targetID)
+			if !ok || !rl.CheckCollisionRecs(area, collider.GetWorldBounds(targetTransform.Position)) {
+				continue
+			}
+
+			melee.HitTargets[uint32(targetID)] = true
+
+			health, _ := healthStore.Get(targetID)
+			if health.Invulnerable || isRolling(abilitiesStore, targetID) || health.IsDead() {
+				continue
+			}
+			health.TakeDamage(attack.Damage)
+
+			var animator *components.AnimatorComponent
+			if animatorStore != nil {
+				animator, _ = animatorStore.Get(targetID)
+			}
+			if animator != nil {
+				animator.SetTrigger("hit")
+			}
+
+			// Knock the target away from the attacker
+			away := float32(1)
+			if !transform.FacingRight {
+				away = -1
+			}
+			targetTransform.Velocity = rl.Vector2{X: away * attack.Knockback.X, Y: attack.Knockback.Y}
+			if physicsStore != nil && attack.Knockback.Y < 0 {
+				if physics, ok := physicsStore.Get(targetID); ok {
+					physics.IsOnGround = false
+					physics.GroundEntityID = 0
+				}
+			}
+
+			world.FreezeFor(attack.HitStop)
+			world.Events.Publish(ecs.Event{
+				Type:   ecs.EventDamage,
+				Source: attackerID,
+				Target: targetID,
+				Data:   DamageEvent{Amount: attack.Damage, Attack: attack.Name},
+			})
+
+			if health.IsDead() {
+				// Animated targets stay to play their death state
+				if animator == nil {
+					target.Active = false
+				}
+				world.Events.Publish(ecs.Event{
+					Type:   ecs.EventDeath,
+					Source: attackerID,
+					Target: targetID,
+				})
+			}
+		}
+	}
+}
+
+// hitboxWorldRect converts a hitbox from sprite-frame pixels to world
+// coordinates, mirroring it across the frame when the entity faces left.
+func hitboxWorldRect(rect rl.Rectangle, transform *components.TransformComponent, scale, frameWidth float32) rl.Rectangle {
+	x := rect.X
+	if !transform.FacingRight {
+		x = frameWidth - rect.X - rect.Width
+	}
+	return rl.Rectangle{
+		X:      transform.Position.X + x*scale,
+		Y:      transform.Position.Y + rect.Y*scale,
+		Width:  rect.Width * scale,
+		Height: rect.Height * scale,
+	}
+}
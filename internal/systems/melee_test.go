@@ -0,0 +1,90 @@
+package systems
+
+import (
+	"testing"
+
+	"fire/internal/components"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func newTestMelee() *components.MeleeComponent {
+	return &components.MeleeComponent{
+		Combo: []components.AttackDefinition{
+			{Name: "slash1", ComboWindow: 0.3},
+			{Name: "slash2", ComboWindow: 0.3},
+			{Name: "slash3"},
+		},
+		CrouchAttack: &components.AttackDefinition{Name: "crouch"},
+		ComboIndex:   -1,
+		ActiveHitbox: -1,
+	}
+}
+
+func TestComboChain(t *testing.T) {
+	melee := newTestMelee()
+	sprite := &components.SpriteComponent{}
+
+	// Each attack completes and the next press lands inside its window
+	var got []string
+	for i := 0; i < 4; i++ {
+		startNextAttack(melee, sprite, false)
+		got = append(got, melee.Current.Name)
+		endAttack(melee, true)
+	}
+	want := []string{"slash1", "slash2", "slash3", "slash1"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("combo played %v, want %v", got, want)
+		}
+	}
+	if !sprite.Restart {
+		t.Error("starting an attack did not restart the sprite")
+	}
+}
+
+func TestComboBreaks(t *testing.T) {
+	tests := []struct {
+		name      string
+		completed bool
+		crouching bool
+		want      string
+	}{
+		{"interrupted attack restarts the combo", false, false, "slash1"},
+		{"completed attack chains", true, false, "slash2"},
+		{"crouching uses the crouch attack", true, true, "crouch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			melee := newTestMelee()
+			sprite := &components.SpriteComponent{}
+			startNextAttack(melee, sprite, false)
+			endAttack(melee, tt.completed)
+
+			startNextAttack(melee, sprite, tt.crouching)
+			if melee.Current == nil || melee.Current.Name != tt.want {
+				t.Errorf("attack %v, want %s", melee.Current, tt.want)
+			}
+		})
+	}
+}
+
+func TestHitboxWorldRect(t *testing.T) {
+	hitbox := rl.Rectangle{X: 80, Y: 40, Width: 30, Height: 20}
+	tests := []struct {
+		name        string
+		facingRight bool
+		want        rl.Rectangle
+	}{
+		{"facing right", true, rl.Rectangle{X: 260, Y: 180, Width: 60, Height: 40}},
+		{"mirrored facing left", false, rl.Rectangle{X: 120, Y: 180, Width: 60, Height: 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transform := &components.TransformComponent{Position: rl.Vector2{X: 100, Y: 100}, FacingRight: tt.facingRight}
+			if got := hitboxWorldRect(hitbox, transform, 2, 120); got != tt.want {
+				t.Errorf("hitboxWorldRect = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package systems
+
+import (
+	"math"
+	"math/rand"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ParticleSystem ages, moves and emits the particles of every
+// ParticleEmitterComponent. Emitters with a trigger burst on their event:
+// landings at the lander's feet, damage at the centre of the hurt entity.
+// Bursts are emitted even during a hit-stop, so sparks hang in the frozen
+// frame.
+type ParticleSystem struct {
+	// Trigger positions gathered from events since the last update
+	lands []ecs.EntityID
+	hits  []ecs.EntityID
+}
+
+// NewParticleSystem creates a new ParticleSystem that bursts triggered
+// emitters on the landing and damage events of the given bus.
+func NewParticleSystem(events *ecs.EventBus) *ParticleSystem {
+	s := &ParticleSystem{}
+	events.Subscribe(ecs.EventPlayerLand, func(event ecs.Event) {
+		s.lands = append(s.lands, event.Source)
+	})
+	events.Subscribe(ecs.EventDamage, func(event ecs.Event) {
+		s.hits = append(s.hits, event.Target)
+	})
+	return s
+}
+
+// Update triggers bursts, then simulates and emits every emitter.
+func (s *ParticleSystem) Update(world *ecs.World, dt float32) {
+	emitterStore, ok1 := ecs.GetStore[*components.ParticleEmitterComponent](world.Components)
+	transformStore, ok2 := ecs.GetStore[*components.TransformComponent](world.Components)
+	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
+
+	if !ok1 || !ok2 {
+		s.lands = s.lands[:0]
+		s.hits = s.hits[:0]
+		return
+	}
+
+	if dt > MaxPhysicsStep {
+		dt = MaxPhysicsStep
+	}
+
+	// Where the events happened
+	at := func(id ecs.EntityID, feet bool) (rl.Vector2, bool) {
+		transform, ok := transformStore.Get(id)
+		if !ok {
+			return rl.Vector2{}, false
+		}
+		if colliderStore == nil {
+			return transform.Position, true
+		}
+		collider, ok := colliderStore.Get(id)
+		if !ok {
+			return transform.Position, true
+		}
+		bounds := collider.GetWorldBounds(transform.Position)
+		if feet {
+			return rl.Vector2{X: bounds.X + bounds.Width/2, Y: bounds.Y + bounds.Height}, true
+		}
+		return rl.Vector2{X: bounds.X + bounds.Width/2, Y: bounds.Y + bounds.Height/2}, true
+	}
+
+	for _, id := range emitterStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+		emitter, _ := emitterStore.Get(id)
+		transform, ok := transformStore.Get(id)
+		if !ok {
+			continue
+		}
+
+		// The pool is allocated once; particles never allocate afterwards
+		if cap(emitter.Particles) < emitter.MaxParticles {
+			emitter.Particles = make([]components.Particle, 0, emitter.MaxParticles)
+		}
+
+		switch emitter.Trigger {
+		case components.TriggerLand:
+			for _, lander := range s.lands {
+				if position, ok := at(lander, true); ok {
+					emitter.Burst(position)
+				}
+			}
+		case components.TriggerHit:
+			for _, target := range s.hits {
+				if position, ok := at(target, false); ok {
+					emitter.Burst(position)
+				}
+			}
+		}
+
+		simulateParticles(emitter, dt)
+
+		centre := rl.Vector2{X: transform.Position.X + emitter.Offset.X, Y: transform.Position.Y + emitter.Offset.Y}
+		if emitter.Emitting && emitter.Rate > 0 {
+			emitter.Accumulator += emitter.Rate * dt
+			for ; emitter.Accumulator >= 1; emitter.Accumulator-- {
+				emitParticle(emitter, centre)
+			}
+		}
+		for _, burst := range emitter.Bursts {
+			for i := 0; i < burst.Count; i++ {
+				emitParticle(emitter, burst.Position)
+			}
+		}
+		emitter.Bursts = emitter.Bursts[:0]
+	}
+
+	s.lands = s.lands[:0]
+	s.hits = s.hits[:0]
+}
+
+// simulateParticles ages and moves an emitter's particles, removing the
+// ones that expired.
+func simulateParticles(emitter *components.ParticleEmitterComponent, dt float32) {
+	if dt <= 0 {
+		return
+	}
+	drag := max(1-emitter.Drag*dt, 0)
+
+	particles := emitter.Particles
+	for i := 0; i < len(particles); {
+		p := &particles[i]
+		p.Age += dt
+		if p.Age >= p.Lifetime {
+			// Order does not matter; fill the gap with the last particle
+			particles[i] = particles[len(particles)-1]
+			particles = particles[:len(particles)-1]
+			continue
+		}
+
+		p.Velocity.X = (p.Velocity.X + emitter.Gravity.X*dt) * drag
+		p.Velocity.Y = (p.Velocity.Y + emitter.Gravity.Y*dt) * drag
+		p.Position.X += p.Velocity.X * dt
+		p.Position.Y += p.Velocity.Y * dt
+		p.Rotation += p.Spin * dt
+		i++
+	}
+	emitter.Particles = particles
+}
+
+// emitParticle adds a particle somewhere in the emitter's area around
+// centre, unless the pool is full.
+func emitParticle(emitter *components.ParticleEmitterComponent, centre rl.Vector2) {
+	if len(emitter.Particles) >= cap(emitter.Particles) {
+		return
+	}
+
+	angle := float64((emitter.Direction + (rand.Float32()-0.5)*emitter.Spread) * rl.Deg2rad)
+	speed := randomBetween(emitter.SpeedMin, emitter.SpeedMax)
+	emitter.Particles = append(emitter.Particles, components.Particle{
+		Position: rl.Vector2{
+			X: centre.X + (rand.Float32()-0.5)*emitter.Area.X,
+			Y: centre.Y + (rand.Float32()-0.5)*emitter.Area.Y,
+		},
+		Velocity: rl.Vector2{X: float32(math.Cos(angle)) * speed, Y: float32(math.Sin(angle)) * speed},
+		Rotation: rand.Float32() * 360,
+		Spin:     (rand.Float32()*2 - 1) * emitter.SpinMax,
+		Lifetime: randomBetween(emitter.LifetimeMin, emitter.LifetimeMax),
+	})
+}
+
+// randomBetween returns a random value in [low, high).
+func randomBetween(low, high float32) float32 {
+	return low + rand.Float32()*(high-low)
+}
+
+// lerpColor blends two colours; t is 0 for a and 1 for b.
+func lerpColor(a, b rl.Color, t float32) rl.Color {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float32(x) + (float32(y)-float32(x))*t)
+	}
+	return rl.Color{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
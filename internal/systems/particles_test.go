@@ -0,0 +1,103 @@
+package systems
+
+import (
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestSimulateParticles(t *testing.T) {
+	emitter := &components.ParticleEmitterComponent{
+		Gravity: rl.Vector2{X: 0, Y: 100},
+		Drag:    0.5,
+		Particles: []components.Particle{
+			{Velocity: rl.Vector2{X: 10, Y: 0}, Spin: 90, Lifetime: 1},
+			{Lifetime: 0.05}, // Expires this step
+			{Position: rl.Vector2{X: 5, Y: 5}, Lifetime: 1},
+		},
+	}
+	simulateParticles(emitter, 0.1)
+
+	if len(emitter.Particles) != 2 {
+		t.Fatalf("%d particles left, want 2", len(emitter.Particles))
+	}
+	// Velocity gains gravity, then loses Drag*dt of itself; position moves by it
+	moving := emitter.Particles[0]
+	if want := (rl.Vector2{X: 9.5, Y: 9.5}); moving.Velocity != want {
+		t.Errorf("velocity %v, want %v", moving.Velocity, want)
+	}
+	if want := (rl.Vector2{X: 0.95, Y: 0.95}); moving.Position != want {
+		t.Errorf("position %v, want %v", moving.Position, want)
+	}
+	if moving.Rotation != 9 || moving.Age != 0.1 {
+		t.Errorf("rotation %v and age %v, want 9 and 0.1", moving.Rotation, moving.Age)
+	}
+	// The last particle fills the expired one's slot
+	if emitter.Particles[1].Position.X < 5 {
+		t.Errorf("second particle %+v, want the one that started at x 5", emitter.Particles[1])
+	}
+}
+
+func TestSimulateParticlesPaused(t *testing.T) {
+	emitter := &components.ParticleEmitterComponent{Particles: []components.Particle{{Velocity: rl.Vector2{X: 10}, Lifetime: 1}}}
+	simulateParticles(emitter, 0)
+	if p := emitter.Particles[0]; p.Age != 0 || p.Position.X != 0 {
+		t.Errorf("a hit-stop moved a particle: %+v", p)
+	}
+}
+
+func TestParticleSystemEmission(t *testing.T) {
+	world := ecs.NewWorld()
+	emitterStore := ecs.RegisterStore[*components.ParticleEmitterComponent](world.Components)
+	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+
+	ambient := world.CreateEntity("emitter")
+	transformStore.Add(ambient.ID, &components.TransformComponent{})
+	continuous := &components.ParticleEmitterComponent{Emitting: true, Rate: 10, LifetimeMin: 5, LifetimeMax: 5, MaxParticles: 4}
+	emitterStore.Add(ambient.ID, continuous)
+
+	dust := world.CreateEntity("emitter")
+	transformStore.Add(dust.ID, &components.TransformComponent{})
+	landing := &components.ParticleEmitterComponent{Trigger: components.TriggerLand, BurstCount: 3, LifetimeMin: 5, LifetimeMax: 5, MaxParticles: 8}
+	emitterStore.Add(dust.ID, landing)
+
+	player := world.CreateEntity("player")
+	transformStore.Add(player.ID, &components.TransformComponent{Position: rl.Vector2{X: 200, Y: 100}})
+
+	system := NewParticleSystem(world.Events)
+	world.Events.Publish(ecs.Event{Type: ecs.EventPlayerLand, Source: player.ID})
+	world.Events.Process()
+
+	// 10 per second for 0.25 s is 2.5 particles: two now, a half owed
+	for i := 0; i < 10; i++ {
+		system.Update(world, 0.025)
+	}
+	if len(continuous.Particles) != 2 || continuous.Accumulator < 0.49 || continuous.Accumulator > 0.51 {
+		t.Errorf("%d particles with %v owed, want 2 with 0.5", len(continuous.Particles), continuous.Accumulator)
+	}
+
+	// The landing burst comes once, at the lander
+	if len(landing.Particles) != 3 {
+		t.Fatalf("%d landing particles, want 3", len(landing.Particles))
+	}
+	for _, p := range landing.Particles {
+		if p.Position != (rl.Vector2{X: 200, Y: 100}) {
+			t.Errorf("dust at %v, want at the lander", p.Position)
+		}
+	}
+
+	// The pool never grows, and long frames are clamped like physics
+	system.Update(world, 1)
+	if continuous.Particles[0].Age > 0.25+MaxPhysicsStep+0.001 {
+		t.Errorf("a long frame aged particles to %v", continuous.Particles[0].Age)
+	}
+	for i := 0; i < 20; i++ {
+		system.Update(world, 0.025)
+	}
+	if len(continuous.Particles) != 4 || cap(continuous.Particles) != 4 {
+		t.Errorf("pool holds %d of %d, want 4 of 4", len(continuous.Particles), cap(continuous.Particles))
+	}
+}
@@ -1,18 +1,40 @@
 package systems
 
 import (
+	"math"
+
 	"fire/internal/components"
 	"fire/internal/ecs"
-
-	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// MaxPhysicsStep caps the time step integrated in a single update so a long
+// frame (window drag, breakpoint) cannot tunnel entities through tiles.
+const MaxPhysicsStep = 1.0 / 30.0
+
+// PhysicsStep is the fixed time step physics is integrated with. Each
+// update runs as many whole steps as its dt holds and carries the remainder
+// over to the next, so trajectories are the same at any frame rate.
+const PhysicsStep = 1.0 / 240.0
+
 // PhysicsSystem applies gravity and handles movement.
-type PhysicsSystem struct{}
+//
+// All quantities are in world units per second (velocity) and per second
+// squared (acceleration), integrated with semi-implicit Euler in fixed
+// PhysicsStep steps: velocity is updated first and the new velocity is used
+// to advance the position. Platforms are moved before and collisions
+// resolved after every step, so riders and ground and wall contacts are
+// never a frame stale.
+type PhysicsSystem struct {
+	accumulator float32          // Frame time not yet integrated
+	platforms   *PlatformSystem  // Moves platforms each step, if set
+	collision   *CollisionSystem // Resolves each step's movement, if set
+}
 
-// NewPhysicsSystem creates a new PhysicsSystem.
-func NewPhysicsSystem() *PhysicsSystem {
-	return &PhysicsSystem{}
+// NewPhysicsSystem creates a new PhysicsSystem that runs platforms and
+// collision in its steps. Either may be nil when it is registered as a
+// system of its own, running once per frame.
+func NewPhysicsSystem(platforms *PlatformSystem, collision *CollisionSystem) *PhysicsSystem {
+	return &PhysicsSystem{platforms: platforms, collision: collision}
 }
 
 // Update applies physics to all entities with Transform and Physics components.
@@ -20,11 +42,71 @@ func (s *PhysicsSystem) Update(world *ecs.World, dt float32) {
 	transformStore, ok1 := ecs.GetStore[*components.TransformComponent](world.Components)
 	physicsStore, ok2 := ecs.GetStore[*components.PhysicsComponent](world.Components)
 	inputStore, _ := ecs.GetStore[*components.InputComponent](world.Components)
+	abilitiesStore, _ := ecs.GetStore[*components.AbilitiesComponent](world.Components)
+	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
+	meleeStore, _ := ecs.GetStore[*components.MeleeComponent](world.Components)
+	healthStore, _ := ecs.GetStore[*components.HealthComponent](world.Components)
 
 	if !ok1 || !ok2 {
 		return
 	}
 
+	// A press becomes a buffered jump request once per frame, so it is
+	// neither lost in a frame shorter than a step (or in a hit-stop) nor
+	// repeated by the steps of a long one. It lasts until the next step at
+	// least.
+	if inputStore != nil {
+		for _, id := range inputStore.All() {
+			input, _ := inputStore.Get(id)
+			if physics, ok := physicsStore.Get(id); ok && input.JumpPressed && !isDead(healthStore, id) {
+				physics.JumpBufferTimer = max(physics.JumpBufferTime, PhysicsStep)
+			}
+		}
+	}
+
+	if s.collision != nil {
+		// The overlay shows the last pass, also while a hit-stop holds it
+		defer s.collision.drawDebug(FindDebugDraw(world))
+	}
+
+	// Nothing moves during a hit-stop
+	if dt <= 0 {
+		return
+	}
+
+	if dt > MaxPhysicsStep {
+		dt = MaxPhysicsStep
+	}
+
+	if s.collision != nil {
+		s.collision.gatherSolids(world)
+	}
+
+	s.accumulator += dt
+	for ; s.accumulator >= PhysicsStep; s.accumulator -= PhysicsStep {
+		if s.platforms != nil {
+			s.platforms.Update(world, PhysicsStep)
+		}
+		s.step(world, transformStore, physicsStore, inputStore, abilitiesStore, colliderStore, meleeStore, healthStore, PhysicsStep)
+		if s.collision != nil {
+			s.collision.resolve(world, PhysicsStep)
+		}
+	}
+}
+
+// step integrates every entity with Transform and Physics components over
+// one fixed time step.
+func (s *PhysicsSystem) step(
+	world *ecs.World,
+	transformStore *ecs.ComponentStore[*components.TransformComponent],
+	physicsStore *ecs.ComponentStore[*components.PhysicsComponent],
+	inputStore *ecs.ComponentStore[*components.InputComponent],
+	abilitiesStore *ecs.ComponentStore[*components.AbilitiesComponent],
+	colliderStore *ecs.ComponentStore[*components.ColliderComponent],
+	meleeStore *ecs.ComponentStore[*components.MeleeComponent],
+	healthStore *ecs.ComponentStore[*components.HealthComponent],
+	dt float32,
+) {
 	for _, id := range transformStore.All() {
 		if !physicsStore.Has(id) {
 			continue
@@ -38,9 +120,18 @@ func (s *PhysicsSystem) Update(world *ecs.World, dt float32) {
 		transform, _ := transformStore.Get(id)
 		physics, _ := physicsStore.Get(id)
 
-		// Apply gravity when not on ground
-		if !physics.IsOnGround {
-			transform.Acceleration.Y += physics.Gravity * 0.2
+		var abilities *components.AbilitiesComponent
+		if abilitiesStore != nil {
+			abilities, _ = abilitiesStore.Get(id)
+		}
+
+		// Timed abilities (dash, roll, slide) own horizontal velocity
+		actionActive := abilities != nil && abilities.Action != components.ActionNone
+		dashing := actionActive && abilities.Action == components.ActionDash
+
+		// Apply gravity when not on ground (dashing ignores it)
+		if !physics.IsOnGround && !dashing {
+			transform.Acceleration.Y = physics.Gravity
 		} else {
 			transform.Acceleration.Y = 0
 		}
@@ -49,32 +140,270 @@ func (s *PhysicsSystem) Update(world *ecs.World, dt float32) {
 		if inputStore != nil && inputStore.Has(id) {
 			input, _ := inputStore.Get(id)
 
-			// Horizontal movement
-			velocity := rl.Vector2{X: input.MoveX, Y: 0}
-
-			// Jump
-			if input.JumpPressed && physics.IsOnGround {
-				velocity.Y = -physics.JumpForce
-				physics.IsOnGround = false
+			// The dead only slow down and fall
+			if isDead(healthStore, id) {
+				input = &components.InputComponent{}
+				physics.JumpBufferTimer = 0
 			}
 
-			// Update facing direction
-			if input.MoveX < 0 {
-				transform.FacingRight = false
-			} else if input.MoveX > 0 {
-				transform.FacingRight = true
+			// Ground attacks root the attacker: movement and jump input are ignored
+			if meleeStore != nil && physics.IsOnGround {
+				if melee, ok := meleeStore.Get(id); ok && melee.IsAttacking() {
+					rooted := *input
+					rooted.MoveX = 0
+					input = &rooted
+					physics.JumpBufferTimer = 0
+				}
 			}
 
-			// Apply velocity with speed multiplier
-			transform.Velocity.X = velocity.X*physics.MoveSpeed + transform.Acceleration.X
-			transform.Velocity.Y = velocity.Y*physics.MoveSpeed + transform.Acceleration.Y
+			if actionActive {
+				// The ability system already set velocity and facing
+				physics.IsTurningAround = false
+			} else {
+				// Crouch before moving so the crouched speed applies this frame
+				if colliderStore != nil {
+					if collider, ok := colliderStore.Get(id); ok {
+						s.applyCrouch(world, transform, physics, collider, input)
+					}
+				}
+
+				// Horizontal movement (input is ignored briefly after a wall jump)
+				wallJumpLocked := abilities != nil && abilities.WallJumpLockTimer > 0
+				if wallJumpLocked {
+					abilities.WallJumpLockTimer -= dt
+					physics.IsTurningAround = false
+				} else {
+					s.applyHorizontalMovement(transform, physics, input, dt)
+				}
+
+				// Jump
+				jumped := s.applyJump(transform, physics, input)
+
+				// Wall slide and wall jump (a ground jump or drop-through
+				// already used the request)
+				if abilities != nil {
+					s.applyWallAbilities(transform, physics, abilities, input, !jumped)
+				}
+
+				// Update facing direction (follow the kick while a wall jump is locked)
+				moveX := input.MoveX
+				if abilities != nil && abilities.WallJumpLockTimer > 0 {
+					moveX = transform.Velocity.X
+				}
+				if moveX < 0 {
+					transform.FacingRight = false
+				} else if moveX > 0 {
+					transform.FacingRight = true
+				}
+			}
 		} else {
-			// Non-player entities just apply acceleration
-			transform.Velocity.Y += transform.Acceleration.Y
+			// Non-player entities just apply acceleration; ground friction
+			// brings knockback to rest
+			transform.Velocity.X += transform.Acceleration.X * dt
+			if physics.IsOnGround && physics.GroundDeceleration > 0 {
+				transform.Velocity.X = approachZero(transform.Velocity.X, physics.GroundDeceleration*dt)
+			}
+			physics.IsTurningAround = false
 		}
 
-		// Update position
-		transform.Position.X += transform.Velocity.X
-		transform.Position.Y += transform.Velocity.Y
+		transform.Velocity.Y += transform.Acceleration.Y * dt
+
+		// Clamp to terminal fall speed
+		if physics.MaxFallSpeed > 0 && transform.Velocity.Y > physics.MaxFallSpeed {
+			transform.Velocity.Y = physics.MaxFallSpeed
+		}
+		if abilities != nil && abilities.IsWallSliding && transform.Velocity.Y > abilities.WallSlideSpeed {
+			transform.Velocity.Y = abilities.WallSlideSpeed
+		}
+
+		// Advance jump timers
+		if physics.IsOnGround {
+			physics.CoyoteTimer = physics.CoyoteTime
+		} else if physics.CoyoteTimer > 0 {
+			physics.CoyoteTimer -= dt
+		}
+		if physics.JumpBufferTimer > 0 {
+			physics.JumpBufferTimer -= dt
+		}
+		if physics.DropThroughTimer > 0 {
+			physics.DropThroughTimer -= dt
+		}
+		if physics.CrouchTransitionTimer > 0 {
+			physics.CrouchTransitionTimer -= dt
+		}
+
+		// Update position with the new velocity
+		transform.Position.X += transform.Velocity.X * dt
+		transform.Position.Y += transform.Velocity.Y * dt
+	}
+}
+
+// applyJump starts a jump when one is requested (buffered by Update) and the
+// entity is grounded or within its coyote window, and cuts the ascent short
+// when the jump key is released early. Holding down while jumping on a
+// one-way platform drops through it instead. It reports whether it used the
+// jump request.
+func (s *PhysicsSystem) applyJump(transform *components.TransformComponent, physics *components.PhysicsComponent, input *components.InputComponent) bool {
+	wantsJump := physics.JumpBufferTimer > 0
+	canJump := (physics.IsOnGround || physics.CoyoteTimer > 0) && !physics.IsCrouching
+
+	// Down + jump on a one-way platform drops through it instead
+	if wantsJump && input.DownHeld && physics.IsOnGround && physics.OnOneWayPlatform {
+		physics.DropThroughTimer = physics.DropThroughTime
+		physics.IsOnGround = false
+		physics.GroundEntityID = 0
+		physics.OnOneWayPlatform = false
+		physics.CoyoteTimer = 0
+		physics.JumpBufferTimer = 0
+		return true
+	}
+
+	if wantsJump && canJump {
+		transform.Velocity.Y = -physics.JumpForce
+
+		// Jumping off a moving platform keeps its momentum
+		if physics.GroundEntityID != 0 {
+			transform.Velocity.X += physics.GroundVelocity.X
+			if physics.GroundVelocity.Y < 0 {
+				transform.Velocity.Y += physics.GroundVelocity.Y
+			}
+			physics.GroundEntityID = 0
+		}
+
+		physics.IsOnGround = false
+		physics.IsJumping = true
+		physics.CoyoteTimer = 0
+		physics.JumpBufferTimer = 0
+		return true
+	}
+
+	// Variable jump height: release early to cut the remaining ascent
+	if physics.IsJumping {
+		if transform.Velocity.Y >= 0 {
+			physics.IsJumping = false
+		} else if !input.JumpHeld {
+			// A zero multiplier is unset tuning, not a cut to a standstill
+			if physics.JumpCutMultiplier > 0 {
+				transform.Velocity.Y *= physics.JumpCutMultiplier
+			}
+			physics.IsJumping = false
+		}
+	}
+	return false
+}
+
+// applyCrouch crouches the entity while Down is held on the ground, shrinking
+// its collider to CrouchHeight. It stands back up once Down is released or the
+// ground is lost, but only when no ceiling overlaps the standing collider.
+func (s *PhysicsSystem) applyCrouch(world *ecs.World, transform *components.TransformComponent, physics *components.PhysicsComponent, collider *components.ColliderComponent, input *components.InputComponent) {
+	if physics.CrouchHeight <= 0 || collider.StandingBounds.Height <= physics.CrouchHeight {
+		return
+	}
+
+	wantsCrouch := input.DownHeld && physics.IsOnGround
+	if wantsCrouch && !physics.IsCrouching {
+		physics.IsCrouching = true
+		physics.CrouchTransitionTimer = physics.CrouchTransitionTime
+	} else if !wantsCrouch && physics.IsCrouching && hasHeadroom(world, s.collision, collider, transform.Position) {
+		physics.IsCrouching = false
+		physics.CrouchTransitionTimer = physics.CrouchTransitionTime
+		collider.Restore()
+	}
+
+	// Re-apply every frame: an ability may have resized the collider
+	if physics.IsCrouching {
+		collider.SetHeight(physics.CrouchHeight)
+	}
+}
+
+// applyWallAbilities handles wall sliding and wall jumping for entities that
+// have unlocked them. Sliding requires falling while pressing into the wall
+// reported by the last collision pass; a buffered jump request against a
+// wall kicks the entity up and away from it, unless canWallJump is false
+// because the request was already used this step.
+func (s *PhysicsSystem) applyWallAbilities(transform *components.TransformComponent, physics *components.PhysicsComponent, abilities *components.AbilitiesComponent, input *components.InputComponent, canWallJump bool) {
+	abilities.IsWallSliding = false
+
+	if physics.IsOnGround || physics.WallContact == 0 {
+		return
+	}
+
+	wantsJump := canWallJump && physics.JumpBufferTimer > 0
+	if abilities.WallJump && wantsJump {
+		away := -float32(physics.WallContact)
+		transform.Velocity.X = away * abilities.WallJumpForce.X
+		transform.Velocity.Y = -abilities.WallJumpForce.Y
+		physics.IsJumping = true
+		physics.JumpBufferTimer = 0
+		physics.WallContact = 0
+		abilities.WallJumpLockTimer = abilities.WallJumpLockTime
+		return
+	}
+
+	pressingIntoWall := input.MoveX != 0 && (input.MoveX > 0) == (physics.WallContact > 0)
+	if abilities.WallSlide && pressingIntoWall && transform.Velocity.Y > 0 {
+		abilities.IsWallSliding = true
+	}
+}
+
+// applyHorizontalMovement accelerates the horizontal velocity towards the
+// input's target speed. The rate depends on whether the entity is starting,
+// stopping or reversing, is scaled by the surface friction on the ground and
+// by AirControl in the air, and is stored in Acceleration.X.
+func (s *PhysicsSystem) applyHorizontalMovement(transform *components.TransformComponent, physics *components.PhysicsComponent, input *components.InputComponent, dt float32) {
+	target := input.MoveX * physics.MoveSpeed
+	if physics.IsCrouching && physics.CrouchSpeedMultiplier > 0 {
+		target *= physics.CrouchSpeedMultiplier
+	}
+	velocity := transform.Velocity.X
+
+	reversing := input.MoveX != 0 && velocity != 0 && (input.MoveX > 0) != (velocity > 0)
+	physics.IsTurningAround = reversing && physics.IsOnGround &&
+		float32(math.Abs(float64(velocity))) >= physics.TurnAroundSpeed
+
+	if physics.GroundAcceleration <= 0 || dt <= 0 {
+		transform.Velocity.X = target
+		transform.Acceleration.X = 0
+		return
+	}
+
+	rate := physics.GroundAcceleration
+	if reversing {
+		rate = physics.TurnAroundBraking
+	} else if input.MoveX == 0 {
+		rate = physics.GroundDeceleration
+	}
+
+	if physics.IsOnGround {
+		friction := physics.SurfaceFriction
+		if friction <= 0 {
+			friction = 1
+		}
+		rate *= friction
+	} else {
+		rate *= physics.AirControl
+	}
+
+	// Move towards the target without overshooting it
+	diff := target - velocity
+	step := rate * dt
+	if float32(math.Abs(float64(diff))) <= step {
+		transform.Acceleration.X = diff / dt
+	} else if diff > 0 {
+		transform.Acceleration.X = rate
+	} else {
+		transform.Acceleration.X = -rate
+	}
+	transform.Velocity.X += transform.Acceleration.X * dt
+}
+
+// approachZero moves value towards zero by at most step.
+func approachZero(value, step float32) float32 {
+	if value > step {
+		return value - step
+	}
+	if value < -step {
+		return value + step
 	}
+	return 0
 }
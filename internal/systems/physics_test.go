@@ -0,0 +1,302 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// testFrameRates are the display rates physics must behave the same at.
+var testFrameRates = []float32{30, 60, 144}
+
+// newPhysicsTestWorld creates a world holding one player-like entity
+// standing on the ground, with tuning close to the shipped player's.
+func newPhysicsTestWorld() (*ecs.World, *components.TransformComponent, *components.PhysicsComponent, *components.InputComponent) {
+	world := ecs.NewWorld()
+	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+	physicsStore := ecs.RegisterStore[*components.PhysicsComponent](world.Components)
+	inputStore := ecs.RegisterStore[*components.InputComponent](world.Components)
+
+	entity := world.CreateEntity("player")
+	transform := &components.TransformComponent{Position: rl.Vector2{X: 0, Y: 0}, FacingRight: true}
+	physics := &components.PhysicsComponent{
+		Gravity:            1800,
+		JumpForce:          600,
+		MoveSpeed:          240,
+		IsOnGround:         true,
+		GroundAcceleration: 2400,
+		GroundDeceleration: 3000,
+		TurnAroundBraking:  4000,
+		AirControl:         0.6,
+		JumpCutMultiplier:  0.45,
+		CoyoteTime:         0.1,
+		JumpBufferTime:     0.12,
+		MaxFallSpeed:       900,
+	}
+	input := &components.InputComponent{}
+	transformStore.Add(entity.ID, transform)
+	physicsStore.Add(entity.ID, physics)
+	inputStore.Add(entity.ID, input)
+	return world, transform, physics, input
+}
+
+// jumpApex jumps from rest at the given frame rate, holding jump, and
+// returns the greatest height reached above the starting position.
+func jumpApex(rate float32) float32 {
+	world, transform, _, input := newPhysicsTestWorld()
+	system := NewPhysicsSystem(nil, nil)
+	dt := 1 / rate
+
+	input.JumpPressed = true
+	input.JumpHeld = true
+	var apex float32
+	for frame := 0; frame < int(rate); frame++ {
+		system.Update(world, dt)
+		input.JumpPressed = false
+		apex = max(apex, -transform.Position.Y)
+	}
+	return apex
+}
+
+func TestPhysicsJumpApexIndependentOfFrameRate(t *testing.T) {
+	// Frames sample the fixed-step trajectory, so the observed apex can
+	// miss the true one by the drop over half a frame at 30 Hz
+	const tolerance = 0.5
+	want := float32(600 * 600 / (2 * 1800)) // v²/2g
+
+	var first float32
+	for i, rate := range testFrameRates {
+		apex := jumpApex(rate)
+		if math.Abs(float64(apex-want)) > 2 {
+			t.Errorf("%v Hz: apex %.2f, want about %.2f", rate, apex, want)
+		}
+		if i == 0 {
+			first = apex
+			continue
+		}
+		if math.Abs(float64(apex-first)) > tolerance {
+			t.Errorf("%v Hz: apex %.2f differs from %v Hz apex %.2f", rate, apex, testFrameRates[0], first)
+		}
+	}
+}
+
+func TestPhysicsRunSpeedIndependentOfFrameRate(t *testing.T) {
+	var first float32
+	for i, rate := range testFrameRates {
+		world, transform, _, input := newPhysicsTestWorld()
+		system := NewPhysicsSystem(nil, nil)
+		dt := 1 / rate
+
+		input.MoveX = 1
+		for frame := 0; frame < int(rate); frame++ {
+			system.Update(world, dt)
+		}
+
+		if transform.Velocity.X != 240 {
+			t.Errorf("%v Hz: run speed %.2f after 1s, want 240", rate, transform.Velocity.X)
+		}
+		if i == 0 {
+			first = transform.Position.X
+			continue
+		}
+		// Rounding in the accumulator may leave one step for the next frame
+		if diff := math.Abs(float64(transform.Position.X - first)); diff > 240*PhysicsStep+0.01 {
+			t.Errorf("%v Hz: ran %.2f in 1s, %v Hz ran %.2f", rate, transform.Position.X, testFrameRates[0], first)
+		}
+	}
+}
+
+func TestPhysicsJumpPressSurvivesShortFrame(t *testing.T) {
+	world, transform, _, input := newPhysicsTestWorld()
+	system := NewPhysicsSystem(nil, nil)
+
+	// A frame shorter than a step integrates nothing, but must not drop
+	// the press made during it
+	input.JumpPressed = true
+	input.JumpHeld = true
+	system.Update(world, PhysicsStep/4)
+	input.JumpPressed = false
+	system.Update(world, PhysicsStep)
+
+	if transform.Velocity.Y >= 0 {
+		t.Fatalf("velocity %.2f after a press in a short frame, want a jump", transform.Velocity.Y)
+	}
+}
+
+func TestPhysicsJumpPressSurvivesHitStop(t *testing.T) {
+	world, transform, _, input := newPhysicsTestWorld()
+	world.AddSystem(NewPhysicsSystem(nil, nil))
+
+	// A press during a hit-stop jumps once the world moves again
+	world.FreezeFor(0.1)
+	input.JumpPressed = true
+	input.JumpHeld = true
+	world.Update(1.0 / 60)
+	input.JumpPressed = false
+	if transform.Velocity.Y != 0 {
+		t.Fatalf("velocity %.2f during the hit-stop, want none", transform.Velocity.Y)
+	}
+
+	for frame := 0; frame < 10 && transform.Velocity.Y == 0; frame++ {
+		world.Update(1.0 / 60)
+	}
+	if transform.Velocity.Y >= 0 {
+		t.Fatalf("velocity %.2f after the hit-stop, want a jump", transform.Velocity.Y)
+	}
+}
+
+// releasedJumpApex jumps from rest, releases jump after the first frame and
+// returns the greatest height reached.
+func releasedJumpApex(cut float32) float32 {
+	world, transform, physics, input := newPhysicsTestWorld()
+	physics.JumpCutMultiplier = cut
+	system := NewPhysicsSystem(nil, nil)
+
+	input.JumpPressed = true
+	input.JumpHeld = true
+	var apex float32
+	for frame := 0; frame < 60; frame++ {
+		system.Update(world, 1.0/60)
+		input.JumpPressed = false
+		input.JumpHeld = false
+		apex = max(apex, -transform.Position.Y)
+	}
+	return apex
+}
+
+func TestPhysicsJumpCut(t *testing.T) {
+	full := jumpApex(60)
+	tests := []struct {
+		name string
+		cut  float32
+		want func(apex float32) bool
+	}{
+		{"half cut", 0.5, func(apex float32) bool { return apex > 10 && apex < full/2 }},
+		{"unset keeps the full jump", 0, func(apex float32) bool { return apex == full }},
+		{"one keeps the full jump", 1, func(apex float32) bool { return apex == full }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if apex := releasedJumpApex(tt.cut); !tt.want(apex) {
+				t.Errorf("cut %v: apex %.2f, full jump %.2f", tt.cut, apex, full)
+			}
+		})
+	}
+}
+
+func TestPhysicsWallJumpOnlyUsesUnspentRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		onGround     bool
+		oneWay       bool
+		downHeld     bool
+		wantWallKick bool
+	}{
+		{"ground jump beside a wall", true, false, false, false},
+		{"drop-through beside a wall", true, true, true, false},
+		{"airborne jump against a wall", false, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			world, transform, physics, input := newPhysicsTestWorld()
+			abilitiesStore := ecs.RegisterStore[*components.AbilitiesComponent](world.Components)
+			abilities := &components.AbilitiesComponent{
+				WallJump:         true,
+				WallJumpForce:    rl.Vector2{X: 300, Y: 560},
+				WallJumpLockTime: 0.15,
+			}
+			abilitiesStore.Add(world.GetEntitiesWithTag("player")[0].ID, abilities)
+
+			physics.IsOnGround = tt.onGround
+			physics.OnOneWayPlatform = tt.oneWay
+			physics.WallContact = 1
+			input.JumpPressed = true
+			input.JumpHeld = true
+			input.DownHeld = tt.downHeld
+			NewPhysicsSystem(nil, nil).Update(world, PhysicsStep)
+
+			kicked := abilities.WallJumpLockTimer > 0
+			if kicked != tt.wantWallKick {
+				t.Errorf("wall kick %v, want %v (velocity %v)", kicked, tt.wantWallKick, transform.Velocity)
+			}
+			if tt.wantWallKick && transform.Velocity.X >= 0 {
+				t.Errorf("kick velocity %v does not point away from the wall", transform.Velocity)
+			}
+		})
+	}
+}
+
+func TestApplyHorizontalMovement(t *testing.T) {
+	const dt = 0.01
+	tests := []struct {
+		name        string
+		velocity    float32
+		moveX       float32
+		onGround    bool
+		friction    float32
+		want        float32
+		wantTurning bool
+	}{
+		{"accelerates from rest", 0, 1, true, 1, 24, false},
+		{"stops short of the target", 230, 1, true, 1, 240, false},
+		{"decelerates on release", 100, 0, true, 1, 70, false},
+		{"brakes harder when reversing", 200, -1, true, 1, 160, true},
+		{"ice scales the rate", 0, 1, true, 0.25, 6, false},
+		{"air control scales the rate", 0, 1, false, 1, 14.4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, transform, physics, input := newPhysicsTestWorld()
+			physics.IsOnGround = tt.onGround
+			physics.SurfaceFriction = tt.friction
+			physics.TurnAroundSpeed = 120
+			transform.Velocity.X = tt.velocity
+			input.MoveX = tt.moveX
+
+			(&PhysicsSystem{}).applyHorizontalMovement(transform, physics, input, dt)
+
+			if math.Abs(float64(transform.Velocity.X-tt.want)) > 0.001 {
+				t.Errorf("velocity %v, want %v", transform.Velocity.X, tt.want)
+			}
+			if physics.IsTurningAround != tt.wantTurning {
+				t.Errorf("turning around %v, want %v", physics.IsTurningAround, tt.wantTurning)
+			}
+		})
+	}
+}
+
+func TestApplyCrouch(t *testing.T) {
+	world, transform, physics, input := newPhysicsTestWorld()
+	physics.CrouchHeight = 24
+	physics.CrouchSpeedMultiplier = 0.5
+	physics.CrouchTransitionTime = 0.1
+	standing := rl.Rectangle{Width: 20, Height: 40}
+	collider := &components.ColliderComponent{Bounds: standing, StandingBounds: standing}
+	ecs.RegisterStore[*components.ColliderComponent](world.Components).Add(world.GetEntitiesWithTag("player")[0].ID, collider)
+	system := NewPhysicsSystem(nil, nil)
+
+	input.DownHeld = true
+	input.MoveX = 1
+	for frame := 0; frame < 60; frame++ {
+		system.Update(world, 1.0/60)
+	}
+	if !physics.IsCrouching {
+		t.Fatal("holding down on the ground did not crouch")
+	}
+	if want := (rl.Rectangle{Y: 16, Width: 20, Height: 24}); collider.Bounds != want {
+		t.Errorf("crouched collider %v, want %v", collider.Bounds, want)
+	}
+	if transform.Velocity.X != 120 {
+		t.Errorf("crouch-walk speed %v, want 120", transform.Velocity.X)
+	}
+
+	input.DownHeld = false
+	system.Update(world, 1.0/60)
+	if physics.IsCrouching || collider.Bounds != standing {
+		t.Errorf("releasing down left crouching %v with collider %v", physics.IsCrouching, collider.Bounds)
+	}
+}
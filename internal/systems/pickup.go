@@ -0,0 +1,71 @@
+package systems
+
+import (
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// PickupSystem lets the player collect coins by touching them. Each coin
+// collected publishes an ecs.EventCoinCollected with the player as Source
+// and the coin as Target, and is deactivated.
+type PickupSystem struct{}
+
+// NewPickupSystem creates a new PickupSystem.
+func NewPickupSystem() *PickupSystem {
+	return &PickupSystem{}
+}
+
+// Update collects the coins overlapping a player's collider.
+func (s *PickupSystem) Update(world *ecs.World, dt float32) {
+	coinStore, ok1 := ecs.GetStore[*components.CoinComponent](world.Components)
+	transformStore, ok2 := ecs.GetStore[*components.TransformComponent](world.Components)
+	colliderStore, ok3 := ecs.GetStore[*components.ColliderComponent](world.Components)
+	healthStore, _ := ecs.GetStore[*components.HealthComponent](world.Components)
+	if !ok1 || !ok2 || !ok3 {
+		return
+	}
+
+	for _, player := range world.GetEntitiesWithTag("player") {
+		if !player.Active {
+			continue
+		}
+		if healthStore != nil {
+			if health, ok := healthStore.Get(player.ID); ok && health.IsDead() {
+				continue
+			}
+		}
+		transform, ok := transformStore.Get(player.ID)
+		if !ok {
+			continue
+		}
+		collider, ok := colliderStore.Get(player.ID)
+		if !ok {
+			continue
+		}
+		bounds := collider.GetWorldBounds(transform.Position)
+
+		for _, id := range coinStore.All() {
+			coin := world.GetEntity(id)
+			if coin == nil || !coin.Active {
+				continue
+			}
+			coinTransform, ok := transformStore.Get(id)
+			if !ok {
+				continue
+			}
+			coinCollider, ok := colliderStore.Get(id)
+			if !ok || !rl.CheckCollisionRecs(bounds, coinCollider.GetWorldBounds(coinTransform.Position)) {
+				continue
+			}
+
+			coin.Active = false
+			world.Events.Publish(ecs.Event{
+				Type:   ecs.EventCoinCollected,
+				Source: player.ID,
+				Target: id,
+			})
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package systems
+
+import (
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// addTestCoin adds a 24x24 coin at a position.
+func addTestCoin(world *ecs.World, x, y float32) *ecs.Entity {
+	coin := world.CreateEntity("coin")
+	ecs.RegisterStore[*components.TransformComponent](world.Components).Add(coin.ID, &components.TransformComponent{Position: rl.Vector2{X: x, Y: y}})
+	ecs.RegisterStore[*components.ColliderComponent](world.Components).Add(coin.ID, &components.ColliderComponent{Bounds: rl.Rectangle{Width: 24, Height: 24}, IsTrigger: true})
+	ecs.RegisterStore[*components.CoinComponent](world.Components).Add(coin.ID, &components.CoinComponent{})
+	return coin
+}
+
+// addTestBody adds an entity with a 20x40 collider at a position.
+func addTestBody(world *ecs.World, x, y float32, tags ...string) *ecs.Entity {
+	body := world.CreateEntity(tags...)
+	ecs.RegisterStore[*components.TransformComponent](world.Components).Add(body.ID, &components.TransformComponent{Position: rl.Vector2{X: x, Y: y}})
+	ecs.RegisterStore[*components.ColliderComponent](world.Components).Add(body.ID, &components.ColliderComponent{Bounds: rl.Rectangle{Width: 20, Height: 40}})
+	return body
+}
+
+func TestPickupCollectsTouchedCoins(t *testing.T) {
+	world := ecs.NewWorld()
+	player := addTestBody(world, 0, 0, "player")
+	score := &components.ScoreComponent{}
+	ecs.RegisterStore[*components.ScoreComponent](world.Components).Add(player.ID, score)
+	addTestBody(world, 300, 0, "enemy")
+
+	touched := addTestCoin(world, 10, 30)
+	away := addTestCoin(world, 100, 0)
+	underMob := addTestCoin(world, 300, 0)
+
+	var collected []ecs.Event
+	world.Events.Subscribe(ecs.EventCoinCollected, func(event ecs.Event) {
+		collected = append(collected, event)
+	})
+	scoreSystem := NewScoreSystem(world.Events, 100, 10)
+
+	for i := 0; i < 2; i++ {
+		NewPickupSystem().Update(world, 0.01)
+		world.Events.Process()
+		scoreSystem.Update(world, 0.01)
+	}
+
+	if len(collected) != 1 || collected[0].Source != player.ID || collected[0].Target != touched.ID {
+		t.Fatalf("collected %+v, want one event from the player for coin %d", collected, touched.ID)
+	}
+	if touched.Active {
+		t.Error("collected coin is still active")
+	}
+	if !away.Active || !underMob.Active {
+		t.Errorf("coins out of the player's reach collected (away %v, under mob %v)", !away.Active, !underMob.Active)
+	}
+	if score.Coins != 1 || score.Score != 10 {
+		t.Errorf("score %+v, want 1 coin and 10 points", *score)
+	}
+}
+
+func TestPickupIgnoresDeadPlayers(t *testing.T) {
+	world := ecs.NewWorld()
+	player := addTestBody(world, 0, 0, "player")
+	ecs.RegisterStore[*components.HealthComponent](world.Components).Add(player.ID, &components.HealthComponent{Current: 0, Max: 3})
+	coin := addTestCoin(world, 0, 0)
+
+	NewPickupSystem().Update(world, 0.01)
+
+	if !coin.Active {
+		t.Error("a dead player collected a coin")
+	}
+}
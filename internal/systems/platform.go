@@ -0,0 +1,126 @@
+package systems
+
+import (
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// PlatformSystem moves kinematic platforms along their waypoint paths and
+// carries the entities standing on them.
+type PlatformSystem struct{}
+
+// NewPlatformSystem creates a new PlatformSystem.
+func NewPlatformSystem() *PlatformSystem {
+	return &PlatformSystem{}
+}
+
+// Update advances all platforms and moves their riders by the same amount.
+// Riders are the entities the collision pass last grounded on a platform.
+func (s *PlatformSystem) Update(world *ecs.World, dt float32) {
+	platformStore, ok1 := ecs.GetStore[*components.PlatformComponent](world.Components)
+	transformStore, ok2 := ecs.GetStore[*components.TransformComponent](world.Components)
+	physicsStore, _ := ecs.GetStore[*components.PhysicsComponent](world.Components)
+
+	if !ok1 || !ok2 || dt <= 0 {
+		return
+	}
+
+	if dt > MaxPhysicsStep {
+		dt = MaxPhysicsStep
+	}
+
+	for _, id := range platformStore.All() {
+		entity := world.GetEntity(id)
+		if entity == nil || !entity.Active {
+			continue
+		}
+
+		transform, ok := transformStore.Get(id)
+		if !ok {
+			continue
+		}
+
+		platform, _ := platformStore.Get(id)
+
+		previous := transform.Position
+		transform.Position = advancePlatform(platform, transform.Position, dt)
+		delta := rl.Vector2Subtract(transform.Position, previous)
+		transform.Velocity = rl.Vector2Scale(delta, 1/dt)
+
+		if physicsStore == nil || (delta.X == 0 && delta.Y == 0) {
+			continue
+		}
+
+		// Carry riders along
+		for _, riderID := range physicsStore.All() {
+			physics, _ := physicsStore.Get(riderID)
+			if physics.GroundEntityID != uint32(id) {
+				continue
+			}
+			if riderTransform, ok := transformStore.Get(riderID); ok {
+				riderTransform.Position = rl.Vector2Add(riderTransform.Position, delta)
+			}
+		}
+	}
+}
+
+// advancePlatform steps a platform along its path and returns its new
+// position. Platforms with fewer than two waypoints stay where they are.
+func advancePlatform(platform *components.PlatformComponent, position rl.Vector2, dt float32) rl.Vector2 {
+	count := len(platform.Waypoints)
+	if count == 0 {
+		return position
+	}
+	if count == 1 {
+		return platform.Waypoints[0]
+	}
+
+	if platform.Direction == 0 {
+		platform.Direction = 1
+	}
+
+	from := platform.Waypoints[platform.FromIndex]
+
+	// Wait at the current waypoint
+	if platform.WaitTimer > 0 {
+		platform.WaitTimer -= dt
+		return from
+	}
+
+	toIndex := nextWaypoint(platform)
+	to := platform.Waypoints[toIndex]
+
+	length := rl.Vector2Distance(from, to)
+	if length > 0 && platform.Speed > 0 {
+		platform.Progress += platform.Speed * dt / length
+	} else {
+		platform.Progress = 1
+	}
+
+	if platform.Progress < 1 {
+		return rl.Vector2Lerp(from, to, platform.Easing.Apply(platform.Progress))
+	}
+
+	// Arrived: start waiting and pick the next segment
+	platform.Progress = 0
+	platform.FromIndex = toIndex
+	platform.WaitTimer = platform.WaitTime
+	if platform.PingPong {
+		if toIndex == count-1 && platform.Direction > 0 {
+			platform.Direction = -1
+		} else if toIndex == 0 && platform.Direction < 0 {
+			platform.Direction = 1
+		}
+	}
+	return to
+}
+
+// nextWaypoint returns the index of the waypoint the platform is heading to.
+func nextWaypoint(platform *components.PlatformComponent) int {
+	if platform.PingPong {
+		return platform.FromIndex + platform.Direction
+	}
+	return (platform.FromIndex + 1) % len(platform.Waypoints)
+}
@@ -0,0 +1,157 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// testWaypoints is a three-point path with 100-unit segments.
+var testWaypoints = []rl.Vector2{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}}
+
+func TestNextWaypoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		pingPong  bool
+		fromIndex int
+		direction int
+		want      int
+	}{
+		{"loop forwards", false, 0, 1, 1},
+		{"loop wraps to the start", false, 2, 1, 0},
+		{"ping-pong forwards", true, 1, 1, 2},
+		{"ping-pong backwards", true, 1, -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platform := &components.PlatformComponent{
+				Waypoints: testWaypoints,
+				PingPong:  tt.pingPong,
+				FromIndex: tt.fromIndex,
+				Direction: tt.direction,
+			}
+			if got := nextWaypoint(platform); got != tt.want {
+				t.Errorf("nextWaypoint = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// waypointOrder advances a platform one segment at a time and returns the
+// waypoints it arrives at.
+func waypointOrder(platform *components.PlatformComponent, segments int) []int {
+	var order []int
+	position := platform.Waypoints[0]
+	for len(order) < segments {
+		from := platform.FromIndex
+		position = advancePlatform(platform, position, 0.5)
+		if platform.FromIndex != from {
+			order = append(order, platform.FromIndex)
+		}
+	}
+	return order
+}
+
+func TestAdvancePlatformOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		pingPong bool
+		want     []int
+	}{
+		{"loop", false, []int{1, 2, 0, 1}},
+		{"ping-pong", true, []int{1, 2, 1, 0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platform := &components.PlatformComponent{Waypoints: testWaypoints, Speed: 100, PingPong: tt.pingPong}
+			got := waypointOrder(platform, len(tt.want))
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("arrived at %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAdvancePlatformTravelAndWait(t *testing.T) {
+	platform := &components.PlatformComponent{Waypoints: testWaypoints, Speed: 100, WaitTime: 1}
+
+	steps := []struct {
+		dt   float32
+		want rl.Vector2
+	}{
+		{0.5, rl.Vector2{X: 50, Y: 0}},  // Halfway along the first segment
+		{0.5, rl.Vector2{X: 100, Y: 0}}, // Arrives and starts waiting
+		{0.5, rl.Vector2{X: 100, Y: 0}}, // Waiting
+		{0.5, rl.Vector2{X: 100, Y: 0}}, // Wait runs out
+		{0.5, rl.Vector2{X: 100, Y: 50}},
+	}
+	position := testWaypoints[0]
+	for i, step := range steps {
+		position = advancePlatform(platform, position, step.dt)
+		if position != step.want {
+			t.Fatalf("step %d: position %v, want %v", i, position, step.want)
+		}
+	}
+}
+
+func TestAdvancePlatformEasing(t *testing.T) {
+	platform := &components.PlatformComponent{Waypoints: testWaypoints, Speed: 100, Easing: components.EaseInOutCubic}
+
+	// A quarter of the way in time is an eighth of the way in distance
+	position := advancePlatform(platform, testWaypoints[0], 0.25)
+	if want := (rl.Vector2{X: 6.25, Y: 0}); position != want {
+		t.Errorf("eased position %v, want %v", position, want)
+	}
+}
+
+func TestAdvancePlatformShortPaths(t *testing.T) {
+	start := rl.Vector2{X: 7, Y: 9}
+	if got := advancePlatform(&components.PlatformComponent{Speed: 100}, start, 0.5); got != start {
+		t.Errorf("no waypoints moved the platform to %v", got)
+	}
+
+	single := &components.PlatformComponent{Waypoints: []rl.Vector2{{X: 40, Y: 40}}, Speed: 100}
+	if got := advancePlatform(single, start, 0.5); got != single.Waypoints[0] {
+		t.Errorf("one waypoint put the platform at %v, want %v", got, single.Waypoints[0])
+	}
+}
+
+func TestPlatformSystemCarriesRiders(t *testing.T) {
+	world := ecs.NewWorld()
+	platformStore := ecs.RegisterStore[*components.PlatformComponent](world.Components)
+	transformStore := ecs.RegisterStore[*components.TransformComponent](world.Components)
+	physicsStore := ecs.RegisterStore[*components.PhysicsComponent](world.Components)
+
+	platform := world.CreateEntity("platform")
+	platformStore.Add(platform.ID, &components.PlatformComponent{Waypoints: testWaypoints, Speed: 60})
+	transformStore.Add(platform.ID, &components.TransformComponent{Position: testWaypoints[0]})
+
+	rider := world.CreateEntity("player")
+	riderTransform := &components.TransformComponent{Position: rl.Vector2{X: 10, Y: -16}}
+	transformStore.Add(rider.ID, riderTransform)
+	physicsStore.Add(rider.ID, &components.PhysicsComponent{GroundEntityID: uint32(platform.ID)})
+
+	bystander := world.CreateEntity("mob")
+	bystanderTransform := &components.TransformComponent{Position: rl.Vector2{X: 10, Y: -16}}
+	transformStore.Add(bystander.ID, bystanderTransform)
+	physicsStore.Add(bystander.ID, &components.PhysicsComponent{})
+
+	NewPlatformSystem().Update(world, MaxPhysicsStep)
+
+	if want := (rl.Vector2{X: 12, Y: -16}); riderTransform.Position != want {
+		t.Errorf("rider at %v, want %v", riderTransform.Position, want)
+	}
+	if want := (rl.Vector2{X: 10, Y: -16}); bystanderTransform.Position != want {
+		t.Errorf("bystander moved to %v", bystanderTransform.Position)
+	}
+	platformTransform, _ := transformStore.Get(platform.ID)
+	if velocity := platformTransform.Velocity; math.Abs(float64(velocity.X-60)) > 0.01 || velocity.Y != 0 {
+		t.Errorf("platform velocity %v, want {60 0}", velocity)
+	}
+}
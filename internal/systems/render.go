@@ -1,173 +1,689 @@
 package systems
 
 import (
+	"math"
+	"sort"
+
 	"fire/internal/components"
 	"fire/internal/ecs"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// TileChunkSize is the side, in world units, of the square chunks static
+// tiles are baked into.
+const TileChunkSize = 512
+
+// cullMargin grows the view when culling so a shaking, tilted camera does
+// not expose missing corners.
+const cullMargin = 64
+
+// Debug overlay drawing, in screen pixels
+const (
+	debugArrowHead = 6
+	debugTextSize  = 10
+)
+
 // RenderConfig holds configuration for the render system.
 type RenderConfig struct {
-	Background       rl.Texture2D
-	GrassTile        rl.Texture2D
-	HealthHeart      rl.Texture2D
+	ClearColor       rl.Color // Shown where no parallax layer covers the view
+	HUD              *HUD     // Drawn under the other UI layer entities; optional
 	HighlightBorders *bool
 }
 
-// RenderSystem draws all visible entities.
+// drawKind is what a draw list entry draws.
+type drawKind int
+
+const (
+	drawTile drawKind = iota
+	drawPlatform
+	drawSprite
+	drawDecoration
+	drawParallax
+	drawChunk
+	drawParticles
+)
+
+// defaultLayers is the layer of each kind of entity without a
+// RenderOrderComponent.
+var defaultLayers = map[drawKind]components.RenderLayer{
+	drawTile:       components.LayerTiles,
+	drawPlatform:   components.LayerTiles,
+	drawSprite:     components.LayerEntities,
+	drawDecoration: components.LayerBackground,
+	drawParallax:   components.LayerParallax,
+	drawChunk:      components.LayerTiles,
+	drawParticles:  components.LayerEntities,
+}
+
+// drawItem is one entry of the draw list.
+type drawItem struct {
+	layer components.RenderLayer
+	order float32
+	id    ecs.EntityID
+	kind  drawKind
+	chunk *tileChunk // Set for drawChunk
+}
+
+// chunkKey is a tile chunk position in chunks.
+type chunkKey struct {
+	X, Y int
+}
+
+// tileChunk is a square of the static tile layer baked into one texture.
+type tileChunk struct {
+	bounds  rl.Rectangle
+	texture rl.RenderTexture2D
+	tiles   []ecs.EntityID // Tiles overlapping the chunk
+}
+
+// renderStores holds the component stores used while drawing; missing
+// stores are nil.
+type renderStores struct {
+	transform  *ecs.ComponentStore[*components.TransformComponent]
+	collider   *ecs.ComponentStore[*components.ColliderComponent]
+	tile       *ecs.ComponentStore[*components.TileComponent]
+	platform   *ecs.ComponentStore[*components.PlatformComponent]
+	sprite     *ecs.ComponentStore[*components.SpriteComponent]
+	decoration *ecs.ComponentStore[*components.DecorationComponent]
+	parallax   *ecs.ComponentStore[*components.ParallaxComponent]
+	emitter    *ecs.ComponentStore[*components.ParticleEmitterComponent]
+	order      *ecs.ComponentStore[*components.RenderOrderComponent]
+}
+
+// getRenderStores looks up the stores used while drawing.
+func getRenderStores(world *ecs.World) renderStores {
+	var stores renderStores
+	stores.transform, _ = ecs.GetStore[*components.TransformComponent](world.Components)
+	stores.collider, _ = ecs.GetStore[*components.ColliderComponent](world.Components)
+	stores.tile, _ = ecs.GetStore[*components.TileComponent](world.Components)
+	stores.platform, _ = ecs.GetStore[*components.PlatformComponent](world.Components)
+	stores.sprite, _ = ecs.GetStore[*components.SpriteComponent](world.Components)
+	stores.decoration, _ = ecs.GetStore[*components.DecorationComponent](world.Components)
+	stores.parallax, _ = ecs.GetStore[*components.ParallaxComponent](world.Components)
+	stores.emitter, _ = ecs.GetStore[*components.ParticleEmitterComponent](world.Components)
+	stores.order, _ = ecs.GetStore[*components.RenderOrderComponent](world.Components)
+	return stores
+}
+
+// RenderSystem draws all visible entities. Every frame it gathers the ones
+// in view into a draw list sorted by layer, order and entity ID, so the draw
+// order is stable from frame to frame.
+//
+// Static tiles are baked into TileChunkSize chunks that are drawn as one
+// texture each. They are baked once, on the first frame: tiles do not change
+// after the map is spawned, and each map load builds a new world and render
+// system. Tiles with a RenderOrderComponent are drawn on their own instead.
 type RenderSystem struct {
-	Config RenderConfig
+	Config   RenderConfig
+	drawList []drawItem   // Reused every frame
+	view     rl.Rectangle // Visible world area this frame
+	cullView rl.Rectangle // View grown by cullMargin
+
+	chunks     map[chunkKey]*tileChunk
+	looseTiles []ecs.EntityID // Tiles drawn on their own
+	baked      bool
 }
 
 // NewRenderSystem creates a new RenderSystem with the given configuration.
 func NewRenderSystem(config RenderConfig) *RenderSystem {
-	return &RenderSystem{Config: config}
+	return &RenderSystem{
+		Config: config,
+		chunks: make(map[chunkKey]*tileChunk),
+	}
+}
+
+// Unload frees the chunk textures.
+func (s *RenderSystem) Unload() {
+	for key, chunk := range s.chunks {
+		rl.UnloadRenderTexture(chunk.texture)
+		delete(s.chunks, key)
+	}
 }
 
 // Update draws all entities (called during render phase).
 func (s *RenderSystem) Update(world *ecs.World, dt float32) {
+	stores := getRenderStores(world)
+	s.advanceParallax(world, stores, dt)
+
+	// The overlay's queue holds one frame's shapes, drawn or not
+	debug := FindDebugDraw(world)
+	if debug != nil {
+		defer func() { debug.Shapes = debug.Shapes[:0] }()
+	}
+
+	// Without a window (headless runs, tests) there is nothing to draw into
+	if !rl.IsWindowReady() {
+		return
+	}
+
+	// Chunks are baked before drawing starts, outside the camera
+	if !s.baked {
+		s.bakeTiles(world, stores)
+		s.baked = true
+	}
+
+	// The world layers are drawn through the camera when there is one
+	camera := FindCamera(world)
+	if camera != nil {
+		s.view = camera.View()
+	} else {
+		s.view = rl.Rectangle{Width: float32(rl.GetScreenWidth()), Height: float32(rl.GetScreenHeight())}
+	}
+	s.buildDrawList(world, stores)
+
 	rl.BeginDrawing()
+	rl.ClearBackground(s.Config.ClearColor)
+
+	if camera != nil {
+		rl.BeginMode2D(camera.Camera)
+	}
 
-	// Draw background
-	rl.DrawTextureEx(s.Config.Background, rl.Vector2{X: 0, Y: 0}, 0.0, 2.7, rl.White)
+	next := 0
+	for ; next < len(s.drawList) && s.drawList[next].layer < components.LayerUI; next++ {
+		s.draw(stores, s.drawList[next])
+	}
+	if debug != nil {
+		s.drawDebugShapes(debug, camera)
+	}
 
-	// Draw tiles first (background layer)
-	s.drawTiles(world)
+	if camera != nil {
+		rl.EndMode2D()
+	}
 
-	// Draw characters/mobs (entity layer)
-	s.drawSprites(world)
+	// UI layer (screen space)
+	if s.Config.HUD != nil {
+		s.Config.HUD.Draw(world, dt)
+	}
+	for ; next < len(s.drawList); next++ {
+		s.draw(stores, s.drawList[next])
+	}
 
-	// Draw health UI
-	s.drawHealth()
+	// Debug labels go over everything, at a readable size
+	if debug != nil {
+		s.drawDebugLabels(debug, camera)
+	}
 
 	rl.EndDrawing()
 }
 
-// drawTiles draws all tile entities.
-func (s *RenderSystem) drawTiles(world *ecs.World) {
-	transformStore, ok1 := ecs.GetStore[*components.TransformComponent](world.Components)
-	tileStore, ok2 := ecs.GetStore[*components.TileComponent](world.Components)
-	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
-
-	if !ok1 || !ok2 {
+// advanceParallax moves auto-scrolling parallax layers.
+func (s *RenderSystem) advanceParallax(world *ecs.World, stores renderStores, dt float32) {
+	if stores.parallax == nil {
 		return
 	}
-
-	for _, id := range tileStore.All() {
+	for _, id := range stores.parallax.All() {
 		entity := world.GetEntity(id)
 		if entity == nil || !entity.Active {
 			continue
 		}
+		layer, _ := stores.parallax.Get(id)
+		layer.Drift += layer.AutoScroll * dt
 
-		transform, ok := transformStore.Get(id)
-		if !ok {
-			continue
+		// Keep the drift small so precision does not degrade over time
+		width := layer.Source.Width * layer.Scale
+		if layer.Repeat && width > 0 {
+			layer.Drift = float32(math.Mod(float64(layer.Drift), float64(width)))
 		}
+	}
+}
 
-		tile, _ := tileStore.Get(id)
-		texture := s.textureForTile(tile.TileType)
+// bakeTiles indexes the static tiles by chunk and bakes every chunk.
+func (s *RenderSystem) bakeTiles(world *ecs.World, stores renderStores) {
+	chunkTiles := make(map[chunkKey][]ecs.EntityID)
+	s.looseTiles = s.looseTiles[:0]
+	if stores.tile != nil && stores.transform != nil && stores.collider != nil {
+		for _, id := range stores.tile.All() {
+			entity := world.GetEntity(id)
+			if entity == nil || !entity.Active {
+				continue
+			}
+			transform, ok := stores.transform.Get(id)
+			if !ok {
+				continue
+			}
+			collider, ok := stores.collider.Get(id)
+			if !ok {
+				continue
+			}
+			if stores.order != nil && stores.order.Has(id) {
+				s.looseTiles = append(s.looseTiles, id)
+				continue
+			}
 
-		tileWidth := float32(texture.Width)
-		tileHeight := float32(texture.Height)
+			indexTileChunks(chunkTiles, id, collider.GetWorldBounds(transform.Position))
+		}
+	}
 
-		sourceRec := rl.Rectangle{X: 0, Y: 0, Width: tileWidth, Height: tileHeight}
-		destRec := rl.Rectangle{X: transform.Position.X, Y: transform.Position.Y, Width: tileWidth, Height: tileHeight}
-		rl.DrawTexturePro(texture, sourceRec, destRec, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+	for key, tiles := range chunkTiles {
+		chunk := &tileChunk{
+			bounds:  chunkBounds(key),
+			texture: rl.LoadRenderTexture(TileChunkSize, TileChunkSize),
+			tiles:   tiles,
+		}
+		s.chunks[key] = chunk
+		s.bakeChunk(stores, chunk)
+	}
+}
 
-		// Draw border if highlight is enabled
-		if s.Config.HighlightBorders != nil && *s.Config.HighlightBorders {
-			if colliderStore != nil {
-				if collider, ok := colliderStore.Get(id); ok {
-					bounds := collider.GetWorldBounds(transform.Position)
-					rl.DrawRectangleLines(int32(bounds.X), int32(bounds.Y), int32(bounds.Width), int32(bounds.Height), rl.Green)
-				}
-			}
+// indexTileChunks adds a tile to every chunk its bounds overlap, so a tile
+// straddling chunk edges is baked into each of them.
+func indexTileChunks(chunkTiles map[chunkKey][]ecs.EntityID, id ecs.EntityID, bounds rl.Rectangle) {
+	first := chunkAt(bounds.X, bounds.Y)
+	last := chunkAt(bounds.X+bounds.Width-1, bounds.Y+bounds.Height-1)
+	for x := first.X; x <= last.X; x++ {
+		for y := first.Y; y <= last.Y; y++ {
+			key := chunkKey{X: x, Y: y}
+			chunkTiles[key] = append(chunkTiles[key], id)
 		}
 	}
 }
 
-// drawSprites draws all entities with Transform and Sprite components.
-func (s *RenderSystem) drawSprites(world *ecs.World) {
-	transformStore, ok1 := ecs.GetStore[*components.TransformComponent](world.Components)
-	spriteStore, ok2 := ecs.GetStore[*components.SpriteComponent](world.Components)
-	colliderStore, _ := ecs.GetStore[*components.ColliderComponent](world.Components)
+// bakeChunk draws a chunk's tiles into its texture.
+func (s *RenderSystem) bakeChunk(stores renderStores, chunk *tileChunk) {
+	rl.BeginTextureMode(chunk.texture)
+	rl.ClearBackground(rl.Blank)
+	for _, id := range chunk.tiles {
+		transform, _ := stores.transform.Get(id)
+		tile, _ := stores.tile.Get(id)
+		collider, _ := stores.collider.Get(id)
+
+		destRec := collider.GetWorldBounds(transform.Position)
+		destRec.X -= chunk.bounds.X
+		destRec.Y -= chunk.bounds.Y
+		rl.DrawTexturePro(tile.Texture, tile.Source, destRec, rl.Vector2{X: 0, Y: 0}, 0, tile.Tint)
+	}
+	rl.EndTextureMode()
+}
 
-	if !ok1 || !ok2 {
-		return
+// chunkAt returns the chunk containing a world position.
+func chunkAt(x, y float32) chunkKey {
+	return chunkKey{
+		X: int(math.Floor(float64(x / TileChunkSize))),
+		Y: int(math.Floor(float64(y / TileChunkSize))),
 	}
+}
 
-	for _, id := range spriteStore.All() {
-		entity := world.GetEntity(id)
-		if entity == nil || !entity.Active {
-			continue
+// chunkBounds returns the world area of a chunk.
+func chunkBounds(key chunkKey) rl.Rectangle {
+	return rl.Rectangle{
+		X:      float32(key.X * TileChunkSize),
+		Y:      float32(key.Y * TileChunkSize),
+		Width:  TileChunkSize,
+		Height: TileChunkSize,
+	}
+}
+
+// buildDrawList collects the active drawable entities in view and sorts
+// them.
+func (s *RenderSystem) buildDrawList(world *ecs.World, stores renderStores) {
+	s.drawList = s.drawList[:0]
+	s.cullView = rl.Rectangle{
+		X:      s.view.X - cullMargin,
+		Y:      s.view.Y - cullMargin,
+		Width:  s.view.Width + 2*cullMargin,
+		Height: s.view.Height + 2*cullMargin,
+	}
+	add := func(ids []ecs.EntityID, kind drawKind) {
+		for _, id := range ids {
+			entity := world.GetEntity(id)
+			if entity == nil || !entity.Active {
+				continue
+			}
+			if kind != drawParallax && !stores.transform.Has(id) {
+				continue
+			}
+
+			item := drawItem{layer: defaultLayers[kind], id: id, kind: kind}
+			if stores.order != nil {
+				if order, ok := stores.order.Get(id); ok {
+					item.layer = order.Layer
+					item.order = order.Order
+				}
+			}
+
+			// Screen-space UI is never culled
+			if item.layer < components.LayerUI {
+				if bounds, ok := s.bounds(stores, item); ok && !rl.CheckCollisionRecs(bounds, s.cullView) {
+					continue
+				}
+			}
+			s.drawList = append(s.drawList, item)
+		}
+	}
+	if stores.parallax != nil {
+		add(stores.parallax.All(), drawParallax)
+	}
+	for _, chunk := range s.chunks {
+		if rl.CheckCollisionRecs(chunk.bounds, s.cullView) {
+			s.drawList = append(s.drawList, drawItem{layer: defaultLayers[drawChunk], kind: drawChunk, chunk: chunk})
+		}
+	}
+	if stores.transform != nil {
+		if stores.tile != nil && stores.collider != nil {
+			add(s.looseTiles, drawTile)
+		}
+		if stores.platform != nil && stores.collider != nil {
+			add(stores.platform.All(), drawPlatform)
+		}
+		if stores.sprite != nil {
+			add(stores.sprite.All(), drawSprite)
 		}
+		if stores.decoration != nil {
+			add(stores.decoration.All(), drawDecoration)
+		}
+		if stores.emitter != nil {
+			add(stores.emitter.All(), drawParticles)
+		}
+	}
 
-		transform, ok := transformStore.Get(id)
-		if !ok {
-			continue
+	sortDrawList(s.drawList)
+}
+
+// sortDrawList orders a draw list back to front: by layer, then order
+// within the layer. Entity ID and kind break ties so equal entries keep the
+// same order every frame.
+func sortDrawList(list []drawItem) {
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.layer != b.layer {
+			return a.layer < b.layer
+		}
+		if a.order != b.order {
+			return a.order < b.order
+		}
+		if a.id != b.id {
+			return a.id < b.id
 		}
+		return a.kind < b.kind
+	})
+}
 
-		sprite, _ := spriteStore.Get(id)
+// bounds returns the world area a draw list entry covers, if it has a fixed
+// one to cull by.
+func (s *RenderSystem) bounds(stores renderStores, item drawItem) (rl.Rectangle, bool) {
+	switch item.kind {
+	case drawTile, drawPlatform:
+		transform, _ := stores.transform.Get(item.id)
+		collider, ok := stores.collider.Get(item.id)
+		if !ok {
+			return rl.Rectangle{}, false
+		}
+		return collider.GetWorldBounds(transform.Position), true
+	case drawSprite:
+		transform, _ := stores.transform.Get(item.id)
+		sprite, _ := stores.sprite.Get(item.id)
 		animData := sprite.GetCurrentAnimation()
 		if animData == nil {
-			continue
+			return rl.Rectangle{}, false
 		}
+		frame := animData.FrameRect(animData.CurrentFrame)
+		return rl.Rectangle{
+			X:      transform.Position.X,
+			Y:      transform.Position.Y,
+			Width:  frame.Width * sprite.Scale,
+			Height: frame.Height * sprite.Scale,
+		}, true
+	case drawDecoration:
+		transform, _ := stores.transform.Get(item.id)
+		decoration, _ := stores.decoration.Get(item.id)
+		return rl.Rectangle{
+			X:      transform.Position.X,
+			Y:      transform.Position.Y,
+			Width:  decoration.Source.Width * decoration.Scale,
+			Height: decoration.Source.Height * decoration.Scale,
+		}, true
+	}
+	return rl.Rectangle{}, false
+}
+
+// draw draws one draw list entry.
+func (s *RenderSystem) draw(stores renderStores, item drawItem) {
+	switch item.kind {
+	case drawTile:
+		s.drawTile(stores, item.id)
+	case drawPlatform:
+		s.drawPlatform(stores, item.id)
+	case drawSprite:
+		s.drawSprite(stores, item.id)
+	case drawDecoration:
+		s.drawDecoration(stores, item.id)
+	case drawParallax:
+		s.drawParallax(stores, item.id)
+	case drawChunk:
+		s.drawChunk(stores, item.chunk)
+	case drawParticles:
+		s.drawParticles(stores, item.id)
+	}
+}
 
-		frameWidth := float32(animData.Texture.Width)
-		frameHeight := float32(animData.Texture.Height)
+// highlight draws the collider border of an entity if highlighting is
+// enabled.
+func (s *RenderSystem) highlight(stores renderStores, id ecs.EntityID, position rl.Vector2, color rl.Color) {
+	if s.Config.HighlightBorders == nil || !*s.Config.HighlightBorders || stores.collider == nil {
+		return
+	}
+	if collider, ok := stores.collider.Get(id); ok {
+		bounds := collider.GetWorldBounds(position)
+		rl.DrawRectangleLines(int32(bounds.X), int32(bounds.Y), int32(bounds.Width), int32(bounds.Height), color)
+	}
+}
 
-		var sourceRec rl.Rectangle
-		if animData.IsSpriteSheet {
-			// Sprite sheet: select frame by X offset
-			sourceRec = rl.Rectangle{
-				X:      float32(animData.CurrentFrame) * frameWidth / float32(animData.FrameCount),
-				Y:      0,
-				Width:  frameWidth / float32(animData.FrameCount),
-				Height: frameHeight,
-			}
-			frameWidth = frameWidth / float32(animData.FrameCount)
-		} else {
-			// GIF animation: texture is already updated
-			sourceRec = rl.Rectangle{X: 0, Y: 0, Width: frameWidth, Height: frameHeight}
-		}
+// drawTile draws a tile entity's tileset piece over its collider.
+func (s *RenderSystem) drawTile(stores renderStores, id ecs.EntityID) {
+	transform, _ := stores.transform.Get(id)
+	tile, _ := stores.tile.Get(id)
+	collider, ok := stores.collider.Get(id)
+	if !ok {
+		return
+	}
+
+	destRec := collider.GetWorldBounds(transform.Position)
+	rl.DrawTexturePro(tile.Texture, tile.Source, destRec, rl.Vector2{X: 0, Y: 0}, 0, tile.Tint)
+	s.highlight(stores, id, transform.Position, rl.Green)
+}
 
-		// Flip horizontally if facing left
-		if !transform.FacingRight {
-			sourceRec.Width = -sourceRec.Width
+// drawChunk draws a baked tile chunk, and the borders of its tiles when
+// highlighting is enabled.
+func (s *RenderSystem) drawChunk(stores renderStores, chunk *tileChunk) {
+	// Render textures are stored upside down
+	sourceRec := rl.Rectangle{X: 0, Y: 0, Width: TileChunkSize, Height: -TileChunkSize}
+	rl.DrawTexturePro(chunk.texture.Texture, sourceRec, chunk.bounds, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+
+	for _, id := range chunk.tiles {
+		if transform, ok := stores.transform.Get(id); ok {
+			s.highlight(stores, id, transform.Position, rl.Green)
 		}
+	}
+}
 
-		destRec := rl.Rectangle{
-			X:      transform.Position.X,
-			Y:      transform.Position.Y,
-			Width:  frameWidth * sprite.Scale,
-			Height: frameHeight * sprite.Scale,
+// drawPlatform draws a moving platform by repeating its tileset piece across
+// its collider.
+func (s *RenderSystem) drawPlatform(stores renderStores, id ecs.EntityID) {
+	transform, _ := stores.transform.Get(id)
+	platform, _ := stores.platform.Get(id)
+	collider, ok := stores.collider.Get(id)
+	if !ok || platform.PieceWidth <= 0 {
+		return
+	}
+
+	bounds := collider.GetWorldBounds(transform.Position)
+	for offset := float32(0); offset < bounds.Width; offset += platform.PieceWidth {
+		width := min(platform.PieceWidth, bounds.Width-offset)
+
+		// A partial last piece shows the matching part of the source
+		sourceRec := platform.Source
+		sourceRec.Width *= width / platform.PieceWidth
+		destRec := rl.Rectangle{X: bounds.X + offset, Y: bounds.Y, Width: width, Height: bounds.Height}
+		rl.DrawTexturePro(platform.Texture, sourceRec, destRec, rl.Vector2{X: 0, Y: 0}, 0, platform.Tint)
+	}
+
+	s.highlight(stores, id, transform.Position, rl.Green)
+}
+
+// drawSprite draws the current animation frame of a sprite entity.
+func (s *RenderSystem) drawSprite(stores renderStores, id ecs.EntityID) {
+	transform, _ := stores.transform.Get(id)
+	sprite, _ := stores.sprite.Get(id)
+	animData := sprite.GetCurrentAnimation()
+	if animData == nil {
+		return
+	}
+
+	// The frame is selected purely by its source rectangle, so the
+	// shared texture is never modified
+	sourceRec := animData.FrameRect(animData.CurrentFrame)
+	frameWidth := sourceRec.Width
+	frameHeight := sourceRec.Height
+
+	// Flip horizontally if facing left
+	if !transform.FacingRight {
+		sourceRec.Width = -sourceRec.Width
+	}
+
+	destRec := rl.Rectangle{
+		X:      transform.Position.X,
+		Y:      transform.Position.Y,
+		Width:  frameWidth * sprite.Scale,
+		Height: frameHeight * sprite.Scale,
+	}
+
+	rl.DrawTexturePro(animData.Texture, sourceRec, destRec, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+	s.highlight(stores, id, transform.Position, rl.Red)
+}
+
+// drawDecoration draws a scenery entity.
+func (s *RenderSystem) drawDecoration(stores renderStores, id ecs.EntityID) {
+	transform, _ := stores.transform.Get(id)
+	decoration, _ := stores.decoration.Get(id)
+
+	destRec := rl.Rectangle{
+		X:      transform.Position.X,
+		Y:      transform.Position.Y,
+		Width:  decoration.Source.Width * decoration.Scale,
+		Height: decoration.Source.Height * decoration.Scale,
+	}
+	rl.DrawTexturePro(decoration.Texture, decoration.Source, destRec, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+}
+
+// drawParticles draws an emitter's particles in view, blending their colour
+// and size over their lifetime.
+func (s *RenderSystem) drawParticles(stores renderStores, id ecs.EntityID) {
+	emitter, _ := stores.emitter.Get(id)
+	for i := range emitter.Particles {
+		p := &emitter.Particles[i]
+		if !rl.CheckCollisionPointRec(p.Position, s.cullView) {
+			continue
 		}
+		t := p.Age / p.Lifetime
+		size := emitter.SizeStart + (emitter.SizeEnd-emitter.SizeStart)*t
+		if size <= 0 {
+			continue
+		}
+		color := lerpColor(emitter.ColorStart, emitter.ColorEnd, t)
 
-		rl.DrawTexturePro(animData.Texture, sourceRec, destRec, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+		// Particles rotate around their centre
+		if emitter.Texture.ID == 0 {
+			destRec := rl.Rectangle{X: p.Position.X, Y: p.Position.Y, Width: size, Height: size}
+			rl.DrawRectanglePro(destRec, rl.Vector2{X: size / 2, Y: size / 2}, p.Rotation, color)
+			continue
+		}
+		height := size * emitter.Source.Height / emitter.Source.Width
+		destRec := rl.Rectangle{X: p.Position.X, Y: p.Position.Y, Width: size, Height: height}
+		rl.DrawTexturePro(emitter.Texture, emitter.Source, destRec, rl.Vector2{X: size / 2, Y: height / 2}, p.Rotation, color)
+	}
+}
 
-		// Draw border if highlight is enabled
-		if s.Config.HighlightBorders != nil && *s.Config.HighlightBorders {
-			if colliderStore != nil {
-				if collider, ok := colliderStore.Get(id); ok {
-					bounds := collider.GetWorldBounds(transform.Position)
-					rl.DrawRectangleLines(int32(bounds.X), int32(bounds.Y), int32(bounds.Width), int32(bounds.Height), rl.Red)
-				}
+// drawDebugShapes draws the debug overlay's queued shapes in world space,
+// keeping line widths and arrow heads the same size on screen.
+func (s *RenderSystem) drawDebugShapes(debug *components.DebugDrawComponent, camera *components.CameraComponent) {
+	pixel := float32(1)
+	if camera != nil && camera.Camera.Zoom > 0 {
+		pixel = 1 / camera.Camera.Zoom
+	}
+	for _, shape := range debug.Shapes {
+		switch shape.Kind {
+		case components.DebugLine:
+			rl.DrawLineEx(shape.From, shape.To, pixel, shape.Color)
+		case components.DebugArrow:
+			rl.DrawLineEx(shape.From, shape.To, pixel, shape.Color)
+			length := float32(math.Hypot(float64(shape.To.X-shape.From.X), float64(shape.To.Y-shape.From.Y)))
+			if length == 0 {
+				continue
 			}
+			head := debugArrowHead * pixel
+			dirX, dirY := (shape.To.X-shape.From.X)/length, (shape.To.Y-shape.From.Y)/length
+			back := rl.Vector2{X: shape.To.X - dirX*head, Y: shape.To.Y - dirY*head}
+			side := rl.Vector2{X: -dirY * head / 2, Y: dirX * head / 2}
+			rl.DrawLineEx(shape.To, rl.Vector2{X: back.X + side.X, Y: back.Y + side.Y}, pixel, shape.Color)
+			rl.DrawLineEx(shape.To, rl.Vector2{X: back.X - side.X, Y: back.Y - side.Y}, pixel, shape.Color)
+		case components.DebugRect:
+			rl.DrawRectangleLinesEx(shape.Rect, pixel, shape.Color)
+		case components.DebugCircle:
+			rl.DrawCircleLinesV(shape.From, shape.Radius, shape.Color)
+		}
+	}
+}
+
+// drawDebugLabels draws the debug overlay's queued text in screen space.
+func (s *RenderSystem) drawDebugLabels(debug *components.DebugDrawComponent, camera *components.CameraComponent) {
+	for _, shape := range debug.Shapes {
+		if shape.Kind != components.DebugText {
+			continue
 		}
+		position := shape.From
+		if camera != nil {
+			position = camera.WorldToScreen(position)
+		}
+		rl.DrawText(shape.Text, int32(position.X), int32(position.Y), debugTextSize, shape.Color)
 	}
 }
 
-// drawHealth draws the health UI.
-func (s *RenderSystem) drawHealth() {
-	for i := 0; i < 5; i++ {
-		rl.DrawTextureEx(s.Config.HealthHeart, rl.Vector2{X: float32(10 + i*40), Y: 10}, 0.0, 0.02, rl.White)
+// drawParallax draws a parallax layer relative to the view, repeated across
+// it if the layer tiles.
+func (s *RenderSystem) drawParallax(stores renderStores, id ecs.EntityID) {
+	layer, _ := stores.parallax.Get(id)
+	width := layer.Source.Width * layer.Scale
+	height := layer.Source.Height * layer.Scale
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	view := s.view
+	origin := parallaxOrigin(layer, view)
+	x, y := origin.X, origin.Y
+
+	draw := func(x float32) {
+		destRec := rl.Rectangle{X: x, Y: y, Width: width, Height: height}
+		rl.DrawTexturePro(layer.Texture, layer.Source, destRec, rl.Vector2{X: 0, Y: 0}, 0, rl.White)
+	}
+	if !layer.Repeat {
+		draw(x)
+		return
+	}
+
+	// One extra copy on each side covers the corners of a shaking view
+	start := x + float32(math.Floor(float64((view.X-x)/width)))*width - width
+	for tileX := start; tileX < view.X+view.Width+width; tileX += width {
+		draw(tileX)
 	}
 }
 
-// textureForTile returns the appropriate texture for a tile type.
-func (s *RenderSystem) textureForTile(tileType components.TileType) rl.Texture2D {
-	// Currently all tiles use grass texture
-	// Extend this when adding more tile types
-	return s.Config.GrassTile
+// parallaxOrigin returns the world position of a parallax layer's top left
+// corner in view: the view centre scaled by the scroll factor plus offset
+// and drift, anchored to the view top or bottom or scrolled vertically.
+func parallaxOrigin(layer *components.ParallaxComponent, view rl.Rectangle) rl.Vector2 {
+	centre := rl.Vector2{X: view.X + view.Width/2, Y: view.Y + view.Height/2}
+
+	x := centre.X*(1-layer.ScrollFactor.X) + layer.Offset.X + layer.Drift
+	var y float32
+	switch layer.Anchor {
+	case components.AnchorTop:
+		y = view.Y + layer.Offset.Y
+	case components.AnchorBottom:
+		y = view.Y + view.Height - layer.Source.Height*layer.Scale + layer.Offset.Y
+	default:
+		y = centre.Y*(1-layer.ScrollFactor.Y) + layer.Offset.Y
+	}
+	return rl.Vector2{X: x, Y: y}
 }
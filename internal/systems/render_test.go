@@ -0,0 +1,148 @@
+package systems
+
+import (
+	"testing"
+
+	"fire/internal/components"
+	"fire/internal/ecs"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestSortDrawList(t *testing.T) {
+	list := []drawItem{
+		{layer: components.LayerForeground, id: 1, kind: drawDecoration},
+		{layer: components.LayerEntities, order: 2, id: 2, kind: drawSprite},
+		{layer: components.LayerEntities, order: 1, id: 9, kind: drawSprite},
+		{layer: components.LayerParallax, id: 7, kind: drawParallax},
+		{layer: components.LayerEntities, order: 2, id: 2, kind: drawParticles},
+		{layer: components.LayerEntities, order: 2, id: 1, kind: drawSprite},
+		{layer: components.LayerTiles, kind: drawChunk},
+	}
+	sortDrawList(list)
+
+	want := []drawItem{
+		{layer: components.LayerParallax, id: 7, kind: drawParallax},
+		{layer: components.LayerTiles, kind: drawChunk},
+		{layer: components.LayerEntities, order: 1, id: 9, kind: drawSprite}, // Lower order first
+		{layer: components.LayerEntities, order: 2, id: 1, kind: drawSprite}, // Then by entity
+		{layer: components.LayerEntities, order: 2, id: 2, kind: drawSprite}, // Then by kind
+		{layer: components.LayerEntities, order: 2, id: 2, kind: drawParticles},
+		{layer: components.LayerForeground, id: 1, kind: drawDecoration},
+	}
+	for i := range want {
+		if list[i] != want[i] {
+			t.Fatalf("entry %d: %+v, want %+v", i, list[i], want[i])
+		}
+	}
+}
+
+func TestParallaxOrigin(t *testing.T) {
+	view := rl.Rectangle{X: 1000, Y: 200, Width: 800, Height: 400}
+	source := rl.Rectangle{Width: 100, Height: 50}
+	tests := []struct {
+		name  string
+		layer components.ParallaxComponent
+		want  rl.Vector2
+	}{
+		{"fixed to the view", components.ParallaxComponent{Source: source, Scale: 1}, rl.Vector2{X: 1400, Y: 400}},
+		{"moves with the world", components.ParallaxComponent{Source: source, Scale: 1, ScrollFactor: rl.Vector2{X: 1, Y: 1}}, rl.Vector2{X: 0, Y: 0}},
+		{"half speed", components.ParallaxComponent{Source: source, Scale: 1, ScrollFactor: rl.Vector2{X: 0.5, Y: 0.5}}, rl.Vector2{X: 700, Y: 200}},
+		{"offset and drift", components.ParallaxComponent{Source: source, Scale: 1, ScrollFactor: rl.Vector2{X: 1, Y: 1}, Offset: rl.Vector2{X: 10, Y: 20}, Drift: 5}, rl.Vector2{X: 15, Y: 20}},
+		{"anchored to the top", components.ParallaxComponent{Source: source, Scale: 1, ScrollFactor: rl.Vector2{X: 1, Y: 1}, Anchor: components.AnchorTop}, rl.Vector2{X: 0, Y: 200}},
+		{"anchored to the bottom", components.ParallaxComponent{Source: source, Scale: 2, ScrollFactor: rl.Vector2{X: 1, Y: 1}, Anchor: components.AnchorBottom}, rl.Vector2{X: 0, Y: 500}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parallaxOrigin(&tt.layer, view); got != tt.want {
+				t.Errorf("origin %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvanceParallaxWrapsDrift(t *testing.T) {
+	world := ecs.NewWorld()
+	parallaxStore := ecs.RegisterStore[*components.ParallaxComponent](world.Components)
+
+	repeating := &components.ParallaxComponent{Source: rl.Rectangle{Width: 100}, Scale: 2, Repeat: true, AutoScroll: 150}
+	single := &components.ParallaxComponent{Source: rl.Rectangle{Width: 100}, Scale: 2, AutoScroll: 150}
+	parallaxStore.Add(world.CreateEntity().ID, repeating)
+	parallaxStore.Add(world.CreateEntity().ID, single)
+
+	system := &RenderSystem{}
+	stores := getRenderStores(world)
+	for i := 0; i < 3; i++ {
+		system.advanceParallax(world, stores, 0.5)
+	}
+
+	// 225 units drifted; a repeating layer 200 wide wraps to 25
+	if repeating.Drift != 25 {
+		t.Errorf("repeating drift %v, want 25", repeating.Drift)
+	}
+	if single.Drift != 225 {
+		t.Errorf("single drift %v, want 225", single.Drift)
+	}
+}
+
+func TestChunkAt(t *testing.T) {
+	tests := []struct {
+		x, y float32
+		want chunkKey
+	}{
+		{0, 0, chunkKey{0, 0}},
+		{511.9, 511.9, chunkKey{0, 0}},
+		{512, 0, chunkKey{1, 0}},
+		{-0.1, 0, chunkKey{-1, 0}}, // Rounds down, not towards zero
+		{-512, -512, chunkKey{-1, -1}},
+		{-512.1, 1024, chunkKey{-2, 2}},
+	}
+	for _, tt := range tests {
+		if got := chunkAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("chunkAt(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestIndexTileChunks(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds rl.Rectangle
+		want   []chunkKey
+	}{
+		{"inside one chunk", rl.Rectangle{X: 32, Y: 32, Width: 32, Height: 32}, []chunkKey{{0, 0}}},
+		{"touching the far edge", rl.Rectangle{X: 480, Y: 480, Width: 32, Height: 32}, []chunkKey{{0, 0}}},
+		{"straddling a vertical edge", rl.Rectangle{X: 496, Y: 0, Width: 32, Height: 32}, []chunkKey{{0, 0}, {1, 0}}},
+		{"straddling the origin", rl.Rectangle{X: -16, Y: -16, Width: 32, Height: 32}, []chunkKey{{-1, -1}, {-1, 0}, {0, -1}, {0, 0}}},
+		{"negative, inside one chunk", rl.Rectangle{X: -64, Y: -544, Width: 32, Height: 32}, []chunkKey{{-1, -2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunkTiles := make(map[chunkKey][]ecs.EntityID)
+			indexTileChunks(chunkTiles, 7, tt.bounds)
+			if len(chunkTiles) != len(tt.want) {
+				t.Fatalf("in chunks %v, want %v", chunkTiles, tt.want)
+			}
+			for _, key := range tt.want {
+				if tiles := chunkTiles[key]; len(tiles) != 1 || tiles[0] != 7 {
+					t.Errorf("chunk %v holds %v, want [7]", key, tiles)
+				}
+			}
+		})
+	}
+}
+
+func TestChunkBounds(t *testing.T) {
+	want := rl.Rectangle{X: -512, Y: 1024, Width: TileChunkSize, Height: TileChunkSize}
+	if got := chunkBounds(chunkKey{-1, 2}); got != want {
+		t.Errorf("chunkBounds = %v, want %v", got, want)
+	}
+	// Every corner of a chunk is inside it
+	key := chunkKey{-3, 4}
+	bounds := chunkBounds(key)
+	for _, corner := range [][2]float32{{bounds.X, bounds.Y}, {bounds.X + bounds.Width - 1, bounds.Y + bounds.Height - 1}} {
+		if got := chunkAt(corner[0], corner[1]); got != key {
+			t.Errorf("corner %v is in chunk %v, want %v", corner, got, key)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package systems
+
+import (
+	"fire/internal/components"
+	"fire/internal/ecs"
+)
+
+// ScoreSystem awards score to entities with a ScoreComponent: PerKill for
+// each entity they kill and PerCoin for each coin they collect.
+type ScoreSystem struct {
+	PerKill int
+	PerCoin int
+
+	// Earners gathered from events since the last update
+	kills []ecs.EntityID
+	coins []ecs.EntityID
+}
+
+// NewScoreSystem creates a new ScoreSystem that scores the death and coin
+// events of the given bus. Deaths count for their Source (the attacker);
+// coins for the Source that collected them.
+func NewScoreSystem(events *ecs.EventBus, perKill, perCoin int) *ScoreSystem {
+	s := &ScoreSystem{PerKill: perKill, PerCoin: perCoin}
+	events.Subscribe(ecs.EventDeath, func(event ecs.Event) {
+		// Hazards kill without a source
+		if event.Source != 0 {
+			s.kills = append(s.kills, event.Source)
+		}
+	})
+	events.Subscribe(ecs.EventCoinCollected, func(event ecs.Event) {
+		s.coins = append(s.coins, event.Source)
+	})
+	return s
+}
+
+// Update adds the kills and coins since the last update to their earners'
+// scores.
+func (s *ScoreSystem) Update(world *ecs.World, dt float32) {
+	scoreStore, ok := ecs.GetStore[*components.ScoreComponent](world.Components)
+	if ok {
+		for _, id := range s.kills {
+			if score, ok := scoreStore.Get(id); ok {
+				score.Score += s.PerKill
+			}
+		}
+		for _, id := range s.coins {
+			if score, ok := scoreStore.Get(id); ok {
+				score.Coins++
+				score.Score += s.PerCoin
+			}
+		}
+	}
+
+	s.kills = s.kills[:0]
+	s.coins = s.coins[:0]
+}
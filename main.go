@@ -30,7 +30,7 @@ func main() {
 		switch game.Mode {
 		case core.ModeMainMenu:
 			rl.BeginDrawing()
-			game.MainMenu.Draw(game.Bg)
+			game.MainMenu.Draw(game.Assets.Texture(core.AssetBackground))
 			rl.EndDrawing()
 
 			newMode := game.MainMenu.Update()
@@ -66,7 +66,7 @@ func main() {
 
 		case core.ModeSettings:
 			rl.BeginDrawing()
-			game.Settings.Draw(game.Bg)
+			game.Settings.Draw(game.Assets.Texture(core.AssetBackground))
 			rl.EndDrawing()
 
 			newMode := game.Settings.Update()
@@ -83,23 +83,49 @@ func initGameWorld(game *core.Game) {
 	game.InitWorld()
 
 	// Spawn player entity
-	core.SpawnPlayer(game.World, game)
+	player := core.SpawnPlayer(game.World, game)
 
 	// Spawn mob entity
-	core.SpawnMob(game.World, game, 500, 450)
+	core.SpawnMob(game.World, game, core.AssetSnail, 500, 450)
 
-	// Load and spawn map tiles
-	core.LoadAndSpawnMap(game.World, game.GrassTile)
+	// Load and spawn map tiles, platforms and scenery
+	levelMap := core.LoadAndSpawnMap(game.World, game)
+
+	// Dust, hit sparks and ambient effects
+	core.SpawnParticles(game.World, game, levelMap)
+
+	// Follow the player through the level
+	core.SpawnCamera(game.World, game, player, levelMap)
+
+	// Shapes queued by systems for the debug overlay
+	core.SpawnDebugDraw(game.World, game)
 
 	// Register systems in execution order
+	collision := systems.NewCollisionSystem()
 	game.World.AddSystem(systems.NewInputSystem())
-	game.World.AddSystem(systems.NewPhysicsSystem())
-	game.World.AddSystem(systems.NewCollisionSystem())
+	game.World.AddSystem(systems.NewAbilitySystem(collision))
+	game.World.AddSystem(systems.NewMeleeSystem())
+	game.World.AddSystem(systems.NewPhysicsSystem(systems.NewPlatformSystem(), collision))
+	game.World.AddSystem(systems.NewHazardSystem())
+	game.World.AddSystem(systems.NewPickupSystem())
+	game.World.AddSystem(systems.NewScoreSystem(game.World.Events, core.ScorePerKill, core.ScorePerCoin))
+	game.World.AddSystem(systems.NewCameraSystem(game.World.Events))
 	game.World.AddSystem(systems.NewAnimationSystem())
+	game.World.AddSystem(systems.NewParticleSystem(game.World.Events))
+	game.World.AddSystem(systems.NewDebugSystem(&game.DebugOverlay))
+
+	// The HUD reads the player's components and is drawn with the UI layer
+	hud := systems.NewHUD(game.World.Events, systems.HUDConfig{
+		Font:            game.Assets.Font(core.AssetFont),
+		Heart:           game.Assets.Texture(core.AssetHealthHeart),
+		ReferenceHeight: core.HUDReferenceHeight,
+		HealthPerHeart:  core.HUDHealthPerHeart,
+		DamageFlashTime: core.HUDDamageFlashTime,
+		ShowTimer:       core.HUDShowTimer,
+	})
 	game.World.AddSystem(systems.NewRenderSystem(systems.RenderConfig{
-		Background:       game.Bg,
-		GrassTile:        game.GrassTile,
-		HealthHeart:      game.HealthHeart,
+		ClearColor:       rl.NewColor(147, 227, 228, 255), // Bottom of the sky
+		HUD:              hud,
 		HighlightBorders: &game.HighlightBorders,
 	}))
 }